@@ -320,6 +320,9 @@ func runSynchronizer(cfg config.Config, etherman *etherman.Client, ethTxManagerS
 	if err != nil {
 		log.Fatal(err)
 	}
+	if cfg.Synchronizer.HealthCheckPort != 0 {
+		go startHealthCheckHttpServer(cfg.Synchronizer, sy)
+	}
 	if err := sy.Sync(); err != nil {
 		log.Fatal(err)
 	}
@@ -359,7 +362,7 @@ func runJSONRPCServer(c config.Config, etherman *etherman.Client, chainID uint64
 	if _, ok := apis[jsonrpc.APIZKEVM]; ok {
 		services = append(services, jsonrpc.Service{
 			Name:    jsonrpc.APIZKEVM,
-			Service: jsonrpc.NewZKEVMEndpoints(c.RPC, st, etherman),
+			Service: jsonrpc.NewZKEVMEndpoints(c.RPC, chainID, pool, st, etherman, c.State.Batch.Constraints, c.L2GasPriceSuggester, storage),
 		})
 	}
 
@@ -589,6 +592,33 @@ func startMetricsHttpServer(c metrics.Config) {
 	}
 }
 
+func startHealthCheckHttpServer(c synchronizer.Config, sy synchronizer.Synchronizer) {
+	const ten = 10
+	mux := http.NewServeMux()
+	address := fmt.Sprintf("%s:%d", c.HealthCheckHost, c.HealthCheckPort)
+	lis, err := net.Listen("tcp", address)
+	if err != nil {
+		log.Errorf("failed to create tcp listener for health check: %v", err)
+		return
+	}
+	mux.Handle(synchronizer.HealthCheckEndpoint, synchronizer.NewHealthHandler(sy, c.HealthCheckMaxLag))
+
+	healthCheckServer := &http.Server{
+		Handler:           mux,
+		ReadHeaderTimeout: ten * time.Second,
+		ReadTimeout:       ten * time.Second,
+	}
+	log.Infof("health check server listening on port %d", c.HealthCheckPort)
+	if err := healthCheckServer.Serve(lis); err != nil {
+		if err == http.ErrServerClosed {
+			log.Warnf("http server for health check stopped")
+			return
+		}
+		log.Errorf("closed http connection for health check server: %v", err)
+		return
+	}
+}
+
 func logVersion() {
 	log.Infow("Starting application",
 		// node version is already logged by default
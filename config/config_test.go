@@ -40,6 +40,30 @@ func Test_Defaults(t *testing.T) {
 			path:          "Synchronizer.SyncChunkSize",
 			expectedValue: uint64(100),
 		},
+		{
+			path:          "Synchronizer.MaxIncrementalRetries",
+			expectedValue: int(3),
+		},
+		{
+			path:          "Synchronizer.MaxTrustedBatchProcessingTime",
+			expectedValue: types.NewDuration(0),
+		},
+		{
+			path:          "Synchronizer.MaxFlushIDRetries",
+			expectedValue: int(3),
+		},
+		{
+			path:          "Synchronizer.HealthCheckHost",
+			expectedValue: "0.0.0.0",
+		},
+		{
+			path:          "Synchronizer.HealthCheckPort",
+			expectedValue: int(0),
+		},
+		{
+			path:          "Synchronizer.HealthCheckMaxLag",
+			expectedValue: uint64(10),
+		},
 		{
 			path:          "Synchronizer.L1SynchronizationMode",
 			expectedValue: "parallel",
@@ -80,6 +104,10 @@ func Test_Defaults(t *testing.T) {
 			path:          "Sequencer.L2ReorgRetrievalInterval",
 			expectedValue: types.NewDuration(5 * time.Second),
 		},
+		{
+			path:          "Sequencer.WorkerQueueDepthWarnThreshold",
+			expectedValue: uint64(10000),
+		},
 		{
 			path:          "Sequencer.Finalizer.GERDeadlineTimeout",
 			expectedValue: types.NewDuration(5 * time.Second),
@@ -96,6 +124,46 @@ func Test_Defaults(t *testing.T) {
 			path:          "Sequencer.Finalizer.ResourcePercentageToCloseBatch",
 			expectedValue: uint32(10),
 		},
+		{
+			path:          "Sequencer.Finalizer.AdaptiveResourceThreshold.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "Sequencer.Finalizer.AdaptiveResourceThreshold.NumOfBatches",
+			expectedValue: uint32(10),
+		},
+		{
+			path:          "Sequencer.Finalizer.AdaptiveResourceThreshold.Step",
+			expectedValue: uint32(1),
+		},
+		{
+			path:          "Sequencer.Finalizer.AdaptiveResourceThreshold.MinResourcePercentage",
+			expectedValue: uint32(5),
+		},
+		{
+			path:          "Sequencer.Finalizer.AdaptiveResourceThreshold.MaxResourcePercentage",
+			expectedValue: uint32(30),
+		},
+		{
+			path:          "Sequencer.Finalizer.BatchFillRateController.Enabled",
+			expectedValue: false,
+		},
+		{
+			path:          "Sequencer.Finalizer.BatchFillRateController.TargetFillRate",
+			expectedValue: float64(0.8),
+		},
+		{
+			path:          "Sequencer.Finalizer.BatchFillRateController.Step",
+			expectedValue: types.NewDuration(500 * time.Millisecond),
+		},
+		{
+			path:          "Sequencer.Finalizer.BatchFillRateController.MinL2BlockTime",
+			expectedValue: types.NewDuration(1 * time.Second),
+		},
+		{
+			path:          "Sequencer.Finalizer.BatchFillRateController.MaxL2BlockTime",
+			expectedValue: types.NewDuration(12 * time.Second),
+		},
 		{
 			path:          "Sequencer.Finalizer.GERFinalityNumberOfBlocks",
 			expectedValue: uint64(64),
@@ -132,6 +200,14 @@ func Test_Defaults(t *testing.T) {
 			path:          "Sequencer.Finalizer.StopSequencerOnBatchNum",
 			expectedValue: uint64(0),
 		},
+		{
+			path:          "Sequencer.Finalizer.HaltWebhookURL",
+			expectedValue: "",
+		},
+		{
+			path:          "Sequencer.Finalizer.ReplayProtectionWindowBatches",
+			expectedValue: uint64(0),
+		},
 		{
 			path:          "Sequencer.Finalizer.TimestampResolution",
 			expectedValue: types.NewDuration(10 * time.Second),
@@ -224,6 +300,14 @@ func Test_Defaults(t *testing.T) {
 			path:          "MTClient.URI",
 			expectedValue: "zkevm-prover:50061",
 		},
+		{
+			path:          "State.BatchL2DataCompressionThreshold",
+			expectedValue: uint64(0),
+		},
+		{
+			path:          "State.L1InfoTreeCacheTTL",
+			expectedValue: types.NewDuration(0),
+		},
 		{
 			path:          "State.DB.User",
 			expectedValue: "state_user",
@@ -389,10 +473,30 @@ func Test_Defaults(t *testing.T) {
 			path:          "RPC.MaxNativeBlockHashBlockRange",
 			expectedValue: uint64(60000),
 		},
+		{
+			path:          "RPC.MaxForcedBatchRangeSize",
+			expectedValue: uint64(10000),
+		},
+		{
+			path:          "RPC.MaxInlineReceiptTxs",
+			expectedValue: uint64(1000),
+		},
+		{
+			path:          "RPC.AdminAPIEnabled",
+			expectedValue: false,
+		},
 		{
 			path:          "RPC.EnableHttpLog",
 			expectedValue: true,
 		},
+		{
+			path:          "RPC.GetCodeCacheSize",
+			expectedValue: int(10000),
+		},
+		{
+			path:          "RPC.HTTP2Enabled",
+			expectedValue: false,
+		},
 		{
 			path:          "RPC.WebSockets.Enabled",
 			expectedValue: true,
@@ -513,6 +617,10 @@ func Test_Defaults(t *testing.T) {
 			path:          "State.Batch.Constraints.MaxBinaries",
 			expectedValue: uint32(473170),
 		},
+		{
+			path:          "State.Batch.Constraints.MaxForcedBatchesPerIteration",
+			expectedValue: uint32(10),
+		},
 	}
 	file, err := os.CreateTemp("", "genesisConfig")
 	require.NoError(t, err)
@@ -12,6 +12,8 @@ Level = "info"
 Outputs = ["stderr"]
 
 [State]
+BatchL2DataCompressionThreshold = 0
+L1InfoTreeCacheTTL = "0s"
 	[State.DB]
 	User = "state_user"
 	Password = "state_password"
@@ -33,6 +35,7 @@ Outputs = ["stderr"]
 		MaxBinaries = 473170
 		MaxSteps = 7570538
 		MaxSHA256Hashes = 1596
+		MaxForcedBatchesPerIteration = 10
 
 [Pool]
 IntervalToRefreshBlockedAddresses = "5m"
@@ -89,7 +92,12 @@ BatchRequestsLimit = 20
 MaxLogsCount = 10000
 MaxLogsBlockRange = 10000
 MaxNativeBlockHashBlockRange = 60000
+MaxForcedBatchRangeSize = 10000
+MaxInlineReceiptTxs = 1000
+AdminAPIEnabled = false
 EnableHttpLog = true
+GetCodeCacheSize = 10000
+HTTP2Enabled = false
 	[RPC.WebSockets]
 		Enabled = true
 		Host = "0.0.0.0"
@@ -100,6 +108,12 @@ EnableHttpLog = true
 SyncInterval = "1s"
 SyncChunkSize = 100
 TrustedSequencerURL = "" # If it is empty or not specified, then the value is read from the smc
+MaxIncrementalRetries = 3
+MaxTrustedBatchProcessingTime = "0s"
+MaxFlushIDRetries = 3
+HealthCheckHost = "0.0.0.0"
+HealthCheckPort = 0
+HealthCheckMaxLag = 10
 L1SynchronizationMode = "parallel"
 	[Synchronizer.L1ParallelSynchronization]
 		MaxClients = 10
@@ -123,6 +137,7 @@ TxLifetimeCheckTimeout = "10m"
 MaxTxLifetime = "3h"
 PoolRetrievalInterval = "500ms"
 L2ReorgRetrievalInterval = "5s"
+WorkerQueueDepthWarnThreshold = 10000
 	[Sequencer.Finalizer]
 		GERDeadlineTimeout = "5s"
 		ForcedBatchDeadlineTimeout = "60s"
@@ -139,6 +154,20 @@ L2ReorgRetrievalInterval = "5s"
 		L2BlockTime = "3s"
 		StopSequencerOnBatchNum = 0
 		SequentialReprocessFullBatch = false
+		HaltWebhookURL = ""
+		ReplayProtectionWindowBatches = 0
+		[Sequencer.Finalizer.AdaptiveResourceThreshold]
+			Enabled = false
+			NumOfBatches = 10
+			Step = 1
+			MinResourcePercentage = 5
+			MaxResourcePercentage = 30
+		[Sequencer.Finalizer.BatchFillRateController]
+			Enabled = false
+			TargetFillRate = 0.8
+			Step = "500ms"
+			MinL2BlockTime = "1s"
+			MaxL2BlockTime = "12s"
 	[Sequencer.StreamServer]
 		Port = 0
 		Filename = ""
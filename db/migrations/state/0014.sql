@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE state.batch
+    ADD COLUMN used_resources JSONB;
+
+-- +migrate Down
+ALTER TABLE state.batch
+    DROP COLUMN IF EXISTS used_resources;
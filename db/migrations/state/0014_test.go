@@ -0,0 +1,44 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the remaining resources of the wip batch
+type migrationTest0014 struct{}
+
+func (m migrationTest0014) InsertData(db *sql.DB) error {
+	const insertBatch = `
+		INSERT INTO state.batch (batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, forced_batch_num, wip) 
+		VALUES (1,'0x0000', '0x0000', '0x0000', '0x0000', now(), '0x0000', null, null, true)`
+
+	_, err := db.Exec(insertBatch)
+	return err
+}
+
+func (m migrationTest0014) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const updateUsedResources = `UPDATE state.batch SET used_resources = $1 WHERE batch_num = 1`
+	_, err := db.Exec(updateUsedResources, `{"Bytes": 100}`)
+	assert.NoError(t, err)
+
+	var usedResources string
+	err = db.QueryRow("SELECT used_resources FROM state.batch WHERE batch_num = 1").Scan(&usedResources)
+	assert.NoError(t, err)
+	assert.JSONEq(t, `{"Bytes": 100}`, usedResources)
+}
+
+func (m migrationTest0014) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	// Check column used_resources doesn't exist in state.batch table
+	const getUsedResourcesColumn = `SELECT count(*) FROM information_schema.columns WHERE table_name='batch' and column_name='used_resources'`
+	row := db.QueryRow(getUsedResourcesColumn)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0014(t *testing.T) {
+	runMigrationTest(t, 14, migrationTest0014{})
+}
@@ -0,0 +1,48 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the L1 block number known when the batch was opened
+type migrationTest0015 struct{}
+
+func (m migrationTest0015) InsertData(db *sql.DB) error {
+	const insertBatch = `
+		INSERT INTO state.batch (batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, forced_batch_num, wip) 
+		VALUES (1,'0x0000', '0x0000', '0x0000', '0x0000', now(), '0x0000', null, null, true)`
+
+	_, err := db.Exec(insertBatch)
+	return err
+}
+
+func (m migrationTest0015) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var l1BlockNum *uint64
+	err := db.QueryRow("SELECT l1_block_num FROM state.batch WHERE batch_num = 1").Scan(&l1BlockNum)
+	assert.NoError(t, err)
+	assert.Nil(t, l1BlockNum)
+
+	const updateL1BlockNum = `UPDATE state.batch SET l1_block_num = $1 WHERE batch_num = 1`
+	_, err = db.Exec(updateL1BlockNum, 123)
+	assert.NoError(t, err)
+
+	err = db.QueryRow("SELECT l1_block_num FROM state.batch WHERE batch_num = 1").Scan(&l1BlockNum)
+	assert.NoError(t, err)
+	assert.Equal(t, uint64(123), *l1BlockNum)
+}
+
+func (m migrationTest0015) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	// Check column l1_block_num doesn't exist in state.batch table
+	const getL1BlockNumColumn = `SELECT count(*) FROM information_schema.columns WHERE table_name='batch' and column_name='l1_block_num'`
+	row := db.QueryRow(getL1BlockNumColumn)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0015(t *testing.T) {
+	runMigrationTest(t, 15, migrationTest0015{})
+}
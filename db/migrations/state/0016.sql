@@ -0,0 +1,5 @@
+-- +migrate Up
+CREATE INDEX IF NOT EXISTS forced_batch_block_num_idx ON state.forced_batch (block_num);
+
+-- +migrate Down
+DROP INDEX IF EXISTS state.forced_batch_block_num_idx;
@@ -0,0 +1,35 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds an index to query the forced batches by L1 block number
+type migrationTest0016 struct{}
+
+func (m migrationTest0016) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0016) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = $1;`
+	row := db.QueryRow(getIndex, "forced_batch_block_num_idx")
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 1, result)
+}
+
+func (m migrationTest0016) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = $1;`
+	row := db.QueryRow(getIndex, "forced_batch_block_num_idx")
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0016(t *testing.T) {
+	runMigrationTest(t, 16, migrationTest0016{})
+}
@@ -0,0 +1,5 @@
+-- +migrate Up
+CREATE INDEX IF NOT EXISTS batch_closing_reason_timestamp_idx ON state.batch (closing_reason, "timestamp");
+
+-- +migrate Down
+DROP INDEX IF EXISTS state.batch_closing_reason_timestamp_idx;
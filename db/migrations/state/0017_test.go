@@ -0,0 +1,38 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds an index to query the batches by closing reason and timestamp,
+// the index on the timestamp alone already exists (batch_timestamp_idx)
+type migrationTest0017 struct{}
+
+func (m migrationTest0017) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0017) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = $1;`
+	for _, index := range []string{"batch_closing_reason_timestamp_idx", "batch_timestamp_idx"} {
+		row := db.QueryRow(getIndex, index)
+		var result int
+		assert.NoError(t, row.Scan(&result))
+		assert.Equal(t, 1, result, index)
+	}
+}
+
+func (m migrationTest0017) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = $1;`
+	row := db.QueryRow(getIndex, "batch_closing_reason_timestamp_idx")
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0017(t *testing.T) {
+	runMigrationTest(t, 17, migrationTest0017{})
+}
@@ -0,0 +1,5 @@
+-- +migrate Up
+CREATE INDEX IF NOT EXISTS virtual_batch_sequencer_addr_batch_num_idx ON state.virtual_batch (sequencer_addr, batch_num);
+
+-- +migrate Down
+DROP INDEX IF EXISTS state.virtual_batch_sequencer_addr_batch_num_idx;
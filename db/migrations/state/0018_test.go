@@ -0,0 +1,35 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds an index to query the virtual batches by sequencer address
+type migrationTest0018 struct{}
+
+func (m migrationTest0018) InsertData(db *sql.DB) error {
+	return nil
+}
+
+func (m migrationTest0018) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = $1;`
+	row := db.QueryRow(getIndex, "virtual_batch_sequencer_addr_batch_num_idx")
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 1, result)
+}
+
+func (m migrationTest0018) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	const getIndex = `SELECT count(*) FROM pg_indexes WHERE indexname = $1;`
+	row := db.QueryRow(getIndex, "virtual_batch_sequencer_addr_batch_num_idx")
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0018(t *testing.T) {
+	runMigrationTest(t, 18, migrationTest0018{})
+}
@@ -0,0 +1,7 @@
+-- +migrate Up
+ALTER TABLE state.batch
+    ADD COLUMN avg_effective_gas_price DECIMAL(78, 0);
+
+-- +migrate Down
+ALTER TABLE state.batch
+    DROP COLUMN IF EXISTS avg_effective_gas_price;
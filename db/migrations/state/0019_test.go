@@ -0,0 +1,48 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the average effective gas price of the txs of the batch
+type migrationTest0019 struct{}
+
+func (m migrationTest0019) InsertData(db *sql.DB) error {
+	const insertBatch = `
+		INSERT INTO state.batch (batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, forced_batch_num, wip) 
+		VALUES (1,'0x0000', '0x0000', '0x0000', '0x0000', now(), '0x0000', null, null, true)`
+
+	_, err := db.Exec(insertBatch)
+	return err
+}
+
+func (m migrationTest0019) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	var avgEffectiveGasPrice *string
+	err := db.QueryRow("SELECT avg_effective_gas_price FROM state.batch WHERE batch_num = 1").Scan(&avgEffectiveGasPrice)
+	assert.NoError(t, err)
+	assert.Nil(t, avgEffectiveGasPrice)
+
+	const updateAvgEffectiveGasPrice = `UPDATE state.batch SET avg_effective_gas_price = $1 WHERE batch_num = 1`
+	_, err = db.Exec(updateAvgEffectiveGasPrice, "1000000000000000000000")
+	assert.NoError(t, err)
+
+	err = db.QueryRow("SELECT avg_effective_gas_price FROM state.batch WHERE batch_num = 1").Scan(&avgEffectiveGasPrice)
+	assert.NoError(t, err)
+	assert.Equal(t, "1000000000000000000000", *avgEffectiveGasPrice)
+}
+
+func (m migrationTest0019) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	// Check column avg_effective_gas_price doesn't exist in state.batch table
+	const getAvgEffectiveGasPriceColumn = `SELECT count(*) FROM information_schema.columns WHERE table_name='batch' and column_name='avg_effective_gas_price'`
+	row := db.QueryRow(getAvgEffectiveGasPriceColumn)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0019(t *testing.T) {
+	runMigrationTest(t, 19, migrationTest0019{})
+}
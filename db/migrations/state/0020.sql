@@ -0,0 +1,5 @@
+-- +migrate Up
+CREATE INDEX IF NOT EXISTS receipt_block_num_tx_index_idx ON state.receipt (block_num, tx_index);
+
+-- +migrate Down
+DROP INDEX IF EXISTS state.receipt_block_num_tx_index_idx;
@@ -0,0 +1,5 @@
+-- +migrate Up
+CREATE INDEX IF NOT EXISTS batch_state_root_idx ON state.batch (state_root);
+
+-- +migrate Down
+DROP INDEX IF EXISTS state.batch_state_root_idx;
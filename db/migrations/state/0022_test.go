@@ -0,0 +1,48 @@
+package migrations_test
+
+import (
+	"database/sql"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// this migration adds the flag that marks the raw_txs_data of the batch as compressed
+type migrationTest0022 struct{}
+
+func (m migrationTest0022) InsertData(db *sql.DB) error {
+	const insertBatch = `
+		INSERT INTO state.batch (batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, forced_batch_num, wip) 
+		VALUES (1,'0x0000', '0x0000', '0x0000', '0x0000', now(), '0x0000', '\xff28b52ffd', null, true)`
+
+	_, err := db.Exec(insertBatch)
+	return err
+}
+
+func (m migrationTest0022) RunAssertsAfterMigrationUp(t *testing.T, db *sql.DB) {
+	// The batches already stored are not compressed
+	var compressed bool
+	err := db.QueryRow("SELECT raw_txs_data_compressed FROM state.batch WHERE batch_num = 1").Scan(&compressed)
+	assert.NoError(t, err)
+	assert.False(t, compressed)
+
+	_, err = db.Exec("UPDATE state.batch SET raw_txs_data_compressed = TRUE WHERE batch_num = 1")
+	assert.NoError(t, err)
+
+	err = db.QueryRow("SELECT raw_txs_data_compressed FROM state.batch WHERE batch_num = 1").Scan(&compressed)
+	assert.NoError(t, err)
+	assert.True(t, compressed)
+}
+
+func (m migrationTest0022) RunAssertsAfterMigrationDown(t *testing.T, db *sql.DB) {
+	// Check column raw_txs_data_compressed doesn't exist in state.batch table
+	const getRawTxsDataCompressedColumn = `SELECT count(*) FROM information_schema.columns WHERE table_name='batch' and column_name='raw_txs_data_compressed'`
+	row := db.QueryRow(getRawTxsDataCompressedColumn)
+	var result int
+	assert.NoError(t, row.Scan(&result))
+	assert.Equal(t, 0, result)
+}
+
+func TestMigration0022(t *testing.T) {
+	runMigrationTest(t, 22, migrationTest0022{})
+}
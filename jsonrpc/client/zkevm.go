@@ -58,6 +58,129 @@ func (c *Client) BatchByNumber(ctx context.Context, number *big.Int) (*types.Bat
 	return result, nil
 }
 
+// VirtualBatch returns the sequencing information of the batch with the provided number,
+// nil is returned if the batch is not sequenced yet
+func (c *Client) VirtualBatch(ctx context.Context, number uint64) (*types.VirtualBatch, error) {
+	response, err := JSONRPCCall(c.url, "zkevm_getVirtualBatch", hex.EncodeUint64(number))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error.RPCError()
+	}
+
+	var result *types.VirtualBatch
+	err = json.Unmarshal(response.Result, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// VerifiedBatch returns the verification information of the batch with the provided number,
+// nil is returned if the batch is not verified yet
+func (c *Client) VerifiedBatch(ctx context.Context, number uint64) (*types.VerifiedBatch, error) {
+	response, err := JSONRPCCall(c.url, "zkevm_getVerifiedBatch", hex.EncodeUint64(number))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error.RPCError()
+	}
+
+	var result *types.VerifiedBatch
+	err = json.Unmarshal(response.Result, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// BatchStats returns the distribution of the gas used by the transactions of a batch
+func (c *Client) BatchStats(ctx context.Context, number uint64) (*types.BatchStats, error) {
+	response, err := JSONRPCCall(c.url, "zkevm_getBatchStats", hex.EncodeUint64(number))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error.RPCError()
+	}
+
+	var result *types.BatchStats
+	err = json.Unmarshal(response.Result, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// BatchWitness returns the input the prover needs to prove the batch
+func (c *Client) BatchWitness(ctx context.Context, number uint64) (*types.BatchWitness, error) {
+	response, err := JSONRPCCall(c.url, "zkevm_getBatchWitness", hex.EncodeUint64(number))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error.RPCError()
+	}
+
+	var result *types.BatchWitness
+	err = json.Unmarshal(response.Result, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// BatchesByStateRoot returns the batches that reference the provided state root
+func (c *Client) BatchesByStateRoot(ctx context.Context, stateRoot common.Hash) ([]types.BatchByStateRoot, error) {
+	response, err := JSONRPCCall(c.url, "zkevm_getBatchesByStateRoot", stateRoot.String())
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error.RPCError()
+	}
+
+	var result []types.BatchByStateRoot
+	err = json.Unmarshal(response.Result, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
+// ForcedBatchesByRange returns the forced batches included in the L1 blocks from fromL1Block
+// to toL1Block, both included
+func (c *Client) ForcedBatchesByRange(ctx context.Context, fromL1Block, toL1Block uint64) ([]types.ForcedBatch, error) {
+	response, err := JSONRPCCall(c.url, "zkevm_getForcedBatchesByRange", hex.EncodeUint64(fromL1Block), hex.EncodeUint64(toL1Block))
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error.RPCError()
+	}
+
+	var result []types.ForcedBatch
+	err = json.Unmarshal(response.Result, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 // ExitRootsByGER returns the exit roots accordingly to the provided Global Exit Root
 func (c *Client) ExitRootsByGER(ctx context.Context, globalExitRoot common.Hash) (*types.ExitRoots, error) {
 	response, err := JSONRPCCall(c.url, "zkevm_getExitRootsByGER", globalExitRoot.String())
@@ -77,3 +200,23 @@ func (c *Client) ExitRootsByGER(ctx context.Context, globalExitRoot common.Hash)
 
 	return result, nil
 }
+
+// LatestGlobalExitRoot returns the most recent global exit root along with its exit roots
+func (c *Client) LatestGlobalExitRoot(ctx context.Context) (*types.GlobalExitRoot, error) {
+	response, err := JSONRPCCall(c.url, "zkevm_getLatestGlobalExitRoot")
+	if err != nil {
+		return nil, err
+	}
+
+	if response.Error != nil {
+		return nil, response.Error.RPCError()
+	}
+
+	var result *types.GlobalExitRoot
+	err = json.Unmarshal(response.Result, &result)
+	if err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
@@ -59,9 +59,30 @@ type Config struct {
 	// native block hashes in a single call to the state, if zero it means no limit
 	MaxNativeBlockHashBlockRange uint64 `mapstructure:"MaxNativeBlockHashBlockRange"`
 
+	// MaxForcedBatchRangeSize is a configuration to set the max range of L1 blocks when querying
+	// forced batches in a single call, if zero it means no limit
+	MaxForcedBatchRangeSize uint64 `mapstructure:"MaxForcedBatchRangeSize"`
+
+	// MaxInlineReceiptTxs is a configuration to set the max number of transactions a block can have
+	// to be returned with the receipts inline by zkevm_getFullBlockByNumber and zkevm_getFullBlockByHash,
+	// if zero it means no limit
+	MaxInlineReceiptTxs uint64 `mapstructure:"MaxInlineReceiptTxs"`
+
+	// AdminAPIEnabled enables the endpoints that are expensive to compute and are meant to be used
+	// only by the node operator infrastructure, like zkevm_getBatchWitness
+	AdminAPIEnabled bool `mapstructure:"AdminAPIEnabled"`
+
 	// EnableHttpLog allows the user to enable or disable the logs related to the HTTP
 	// requests to be captured by the server.
 	EnableHttpLog bool `mapstructure:"EnableHttpLog"`
+
+	// GetCodeCacheSize is the max number of entries kept in the cache used by eth_getCode
+	// to store the code of an address for a given state root, if zero the cache is disabled
+	GetCodeCacheSize int `mapstructure:"GetCodeCacheSize"`
+
+	// HTTP2Enabled enables HTTP/2 over cleartext connections (h2c) on the HTTP port, so clients can
+	// multiplex concurrent requests over a single connection. HTTP/1.1 requests are still accepted
+	HTTP2Enabled bool `mapstructure:"HTTP2Enabled"`
 }
 
 // WebSocketsConfig has parameters to config the rpc websocket support
@@ -94,6 +94,13 @@ func (d *DebugEndpoints) TraceBlockByNumber(number types.BlockNumber, cfg *trace
 	})
 }
 
+// TraceBlock creates a response for debug_traceBlock request.
+// It receives the block number instead of the RLP encoded block and returns
+// the same response as debug_traceBlockByNumber.
+func (d *DebugEndpoints) TraceBlock(number types.BlockNumber, cfg *traceConfig) (interface{}, types.Error) {
+	return d.TraceBlockByNumber(number, cfg)
+}
+
 // TraceBlockByHash creates a response for debug_traceBlockByHash request.
 // See https://geth.ethereum.org/docs/interacting-with-geth/rpc/ns-debug#debugtraceblockbyhash
 func (d *DebugEndpoints) TraceBlockByHash(hash types.ArgHash, cfg *traceConfig) (interface{}, types.Error) {
@@ -0,0 +1,100 @@
+package jsonrpc
+
+import (
+	"context"
+	"encoding/json"
+	"math/big"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
+	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/trie"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTraceBlock(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	type traceResponse struct {
+		Result json.RawMessage `json:"result"`
+	}
+
+	type testCase struct {
+		Name           string
+		Number         *big.Int
+		ExpectedResult []traceResponse
+		ExpectedError  *types.RPCError
+		SetupMocks     func(m *mocksWrapper, tc *testCase)
+	}
+
+	txs := []*ethTypes.Transaction{
+		ethTypes.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), []byte{}),
+		ethTypes.NewTransaction(2, common.HexToAddress("0x2"), big.NewInt(2), 21000, big.NewInt(2), []byte{}),
+	}
+
+	testCases := []testCase{
+		{
+			Name:   "Trace all the block txs successfully",
+			Number: big.NewInt(1),
+			ExpectedResult: []traceResponse{
+				{Result: json.RawMessage(`{"gas":1}`)},
+				{Result: json.RawMessage(`{"gas":2}`)},
+			},
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+
+				header := state.NewL2Header(&ethTypes.Header{Number: tc.Number})
+				block := state.NewL2Block(header, txs, []*state.L2Header{}, []*ethTypes.Receipt{}, &trie.StackTrie{})
+				m.State.On("GetL2BlockByNumber", context.Background(), tc.Number.Uint64(), m.DbTx).Return(block, nil).Once()
+
+				for i, expected := range tc.ExpectedResult {
+					m.State.
+						On("DebugTransaction", context.Background(), txs[i].Hash(), mock.IsType(state.TraceConfig{}), m.DbTx).
+						Return(&runtime.ExecutionResult{TraceResult: expected.Result}, nil).
+						Once()
+				}
+			},
+		},
+		{
+			Name:          "Block not found",
+			Number:        big.NewInt(2),
+			ExpectedError: types.NewRPCError(types.DefaultErrorCode, "block #2 not found"),
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetL2BlockByNumber", context.Background(), tc.Number.Uint64(), m.DbTx).Return(nil, state.ErrNotFound).Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m, &tc)
+
+			res, err := s.JSONRPCCall("debug_traceBlock", hex.EncodeBig(tc.Number))
+			require.NoError(t, err)
+
+			if tc.ExpectedError != nil {
+				require.NotNil(t, res.Error)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+				return
+			}
+
+			require.Nil(t, res.Error)
+			var result []traceResponse
+			err = json.Unmarshal(res.Result, &result)
+			require.NoError(t, err)
+			assert.Equal(t, tc.ExpectedResult, result)
+		})
+	}
+}
@@ -19,6 +19,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/jackc/pgx/v4"
 )
@@ -37,11 +38,25 @@ type EthEndpoints struct {
 	etherman types.EthermanInterface
 	storage  storageInterface
 	txMan    DBTxManager
+	// codeCache stores the code of an address for a given state root,
+	// it is nil when the cache is disabled
+	codeCache *lru.Cache[codeCacheKey, []byte]
+	// forkIDs resolves the fork id of the L2 blocks returned
+	forkIDs *l2BlockForkIDCache
+}
+
+// codeCacheKey identifies the code of an address at a given state root
+type codeCacheKey struct {
+	address   common.Address
+	stateRoot common.Hash
 }
 
 // NewEthEndpoints creates an new instance of Eth
 func NewEthEndpoints(cfg Config, chainID uint64, p types.PoolInterface, s types.StateInterface, etherman types.EthermanInterface, storage storageInterface) *EthEndpoints {
-	e := &EthEndpoints{cfg: cfg, chainID: chainID, pool: p, state: s, etherman: etherman, storage: storage}
+	e := &EthEndpoints{cfg: cfg, chainID: chainID, pool: p, state: s, etherman: etherman, storage: storage, forkIDs: newL2BlockForkIDCache()}
+	if cfg.GetCodeCacheSize > 0 {
+		e.codeCache = lru.NewCache[codeCacheKey, []byte](cfg.GetCodeCacheSize)
+	}
 	s.RegisterNewL2BlockEventHandler(e.onNewL2Block)
 
 	return e
@@ -74,39 +89,11 @@ func (e *EthEndpoints) Call(arg *types.TxArgs, blockArg *types.BlockNumberOrHash
 		if respErr != nil {
 			return nil, respErr
 		}
-		var blockToProcess *uint64
-		if blockArg != nil {
-			blockNumArg := blockArg.Number()
-			if blockNumArg != nil && (*blockArg.Number() == types.LatestBlockNumber || *blockArg.Number() == types.PendingBlockNumber) {
-				blockToProcess = nil
-			} else {
-				n := block.NumberU64()
-				blockToProcess = &n
-			}
-		}
-
-		// If the caller didn't supply the gas limit in the message, then we set it to maximum possible => block gas limit
-		if arg.Gas == nil || uint64(*arg.Gas) <= 0 {
-			header, err := e.state.GetL2BlockHeaderByNumber(ctx, block.NumberU64(), dbTx)
-			if err != nil {
-				return RPCErrorResponse(types.DefaultErrorCode, "failed to get block header", err, true)
-			}
+		blockToProcess := getBlockToProcess(block, blockArg)
 
-			gas := types.ArgUint64(header.GasLimit)
-			arg.Gas = &gas
-		}
-
-		defaultSenderAddress := common.HexToAddress(state.DefaultSenderAddress)
-		sender, tx, err := arg.ToTransaction(ctx, e.state, e.cfg.MaxCumulativeGasUsed, block.Root(), defaultSenderAddress, dbTx)
-		if err != nil {
-			return RPCErrorResponse(types.DefaultErrorCode, "failed to convert arguments into an unsigned transaction", err, false)
-		}
-
-		result, err := e.state.ProcessUnsignedTransaction(ctx, tx, sender, blockToProcess, true, dbTx)
-		if err != nil {
-			errMsg := fmt.Sprintf("failed to execute the unsigned transaction: %v", err.Error())
-			logError := !runtime.IsOutOfCounterError(err) && !errors.Is(err, runtime.ErrOutOfGas)
-			return RPCErrorResponse(types.DefaultErrorCode, errMsg, nil, logError)
+		result, respErr := e.internalCall(ctx, arg, block, blockToProcess, dbTx)
+		if respErr != nil {
+			return nil, respErr
 		}
 
 		if result.Reverted() {
@@ -121,6 +108,101 @@ func (e *EthEndpoints) Call(arg *types.TxArgs, blockArg *types.BlockNumberOrHash
 	})
 }
 
+// BatchCall executes a list of message calls against the state of the same block
+// and returns the result of each one of them in the same order they were provided.
+// A call that fails or reverts doesn't stop the execution of the remaining calls,
+// its error is reported in the corresponding result instead.
+// Note, this function doesn't make any changes in the state/blockchain.
+func (e *EthEndpoints) BatchCall(args []*types.TxArgs, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
+	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		if len(args) == 0 {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 0", nil, false)
+		} else if blockArg == nil {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, "missing value for required argument 1", nil, false)
+		}
+		if e.cfg.BatchRequestsLimit > 0 && len(args) > int(e.cfg.BatchRequestsLimit) {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("calls limit exceeded, max allowed: %v", e.cfg.BatchRequestsLimit), nil, false)
+		}
+		for i, arg := range args {
+			if arg == nil {
+				return RPCErrorResponse(types.InvalidParamsErrorCode, fmt.Sprintf("missing value for call %v", i), nil, false)
+			}
+		}
+
+		block, respErr := e.getBlockByArg(ctx, blockArg, dbTx)
+		if respErr != nil {
+			return nil, respErr
+		}
+		blockToProcess := getBlockToProcess(block, blockArg)
+
+		results := make([]types.CallResult, 0, len(args))
+		for _, arg := range args {
+			result, respErr := e.internalCall(ctx, arg, block, blockToProcess, dbTx)
+			if respErr != nil {
+				results = append(results, types.CallResult{Result: types.ArgBytes{}, Error: respErr.Error()})
+				continue
+			}
+
+			callResult := types.CallResult{Result: types.ArgBytes(result.ReturnValue)}
+			if callResult.Result == nil {
+				callResult.Result = types.ArgBytes{}
+			}
+			if result.Failed() {
+				callResult.Error = result.Err.Error()
+			}
+			results = append(results, callResult)
+		}
+
+		return results, nil
+	})
+}
+
+// getBlockToProcess returns the block number the executor must use to process
+// an unsigned transaction, nil means the tx must be processed on top of the latest state
+func getBlockToProcess(block *state.L2Block, blockArg *types.BlockNumberOrHash) *uint64 {
+	if blockArg == nil {
+		return nil
+	}
+	blockNumArg := blockArg.Number()
+	if blockNumArg != nil && (*blockNumArg == types.LatestBlockNumber || *blockNumArg == types.PendingBlockNumber) {
+		return nil
+	}
+	n := block.NumberU64()
+	return &n
+}
+
+// internalCall executes the provided message call on top of the provided block
+func (e *EthEndpoints) internalCall(ctx context.Context, arg *types.TxArgs, block *state.L2Block, blockToProcess *uint64, dbTx pgx.Tx) (*runtime.ExecutionResult, types.Error) {
+	// If the caller didn't supply the gas limit in the message, then we set it to maximum possible => block gas limit
+	if arg.Gas == nil || uint64(*arg.Gas) <= 0 {
+		header, err := e.state.GetL2BlockHeaderByNumber(ctx, block.NumberU64(), dbTx)
+		if err != nil {
+			_, respErr := RPCErrorResponse(types.DefaultErrorCode, "failed to get block header", err, true)
+			return nil, respErr
+		}
+
+		gas := types.ArgUint64(header.GasLimit)
+		arg.Gas = &gas
+	}
+
+	defaultSenderAddress := common.HexToAddress(state.DefaultSenderAddress)
+	sender, tx, err := arg.ToTransaction(ctx, e.state, e.cfg.MaxCumulativeGasUsed, block.Root(), defaultSenderAddress, dbTx)
+	if err != nil {
+		_, respErr := RPCErrorResponse(types.DefaultErrorCode, "failed to convert arguments into an unsigned transaction", err, false)
+		return nil, respErr
+	}
+
+	result, err := e.state.ProcessUnsignedTransaction(ctx, tx, sender, blockToProcess, true, dbTx)
+	if err != nil {
+		errMsg := fmt.Sprintf("failed to execute the unsigned transaction: %v", err.Error())
+		logError := !runtime.IsOutOfCounterError(err) && !errors.Is(err, runtime.ErrOutOfGas)
+		_, respErr := RPCErrorResponse(types.DefaultErrorCode, errMsg, nil, logError)
+		return nil, respErr
+	}
+
+	return result, nil
+}
+
 // ChainId returns the chain id of the client
 func (e *EthEndpoints) ChainId() (interface{}, types.Error) { //nolint:revive
 	return hex.EncodeUint64(e.chainID), nil
@@ -169,16 +251,7 @@ func (e *EthEndpoints) EstimateGas(arg *types.TxArgs, blockArg *types.BlockNumbe
 			return nil, respErr
 		}
 
-		var blockToProcess *uint64
-		if blockArg != nil {
-			blockNumArg := blockArg.Number()
-			if blockNumArg != nil && (*blockArg.Number() == types.LatestBlockNumber || *blockArg.Number() == types.PendingBlockNumber) {
-				blockToProcess = nil
-			} else {
-				n := block.NumberU64()
-				blockToProcess = &n
-			}
-		}
+		blockToProcess := getBlockToProcess(block, blockArg)
 
 		defaultSenderAddress := common.HexToAddress(state.DefaultSenderAddress)
 		sender, tx, err := arg.ToTransaction(ctx, e.state, e.cfg.MaxCumulativeGasUsed, block.Root(), defaultSenderAddress, dbTx)
@@ -288,29 +361,7 @@ func (e *EthEndpoints) getBlockByArg(ctx context.Context, blockArg *types.BlockN
 // GetBlockByHash returns information about a block by hash
 func (e *EthEndpoints) GetBlockByHash(hash types.ArgHash, fullTx bool) (interface{}, types.Error) {
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
-		l2Block, err := e.state.GetL2BlockByHash(ctx, hash.Hash(), dbTx)
-		if errors.Is(err, state.ErrNotFound) {
-			return nil, nil
-		} else if err != nil {
-			return RPCErrorResponse(types.DefaultErrorCode, "failed to get block by hash from state", err, true)
-		}
-
-		txs := l2Block.Transactions()
-		receipts := make([]ethTypes.Receipt, 0, len(txs))
-		for _, tx := range txs {
-			receipt, err := e.state.GetTransactionReceipt(ctx, tx.Hash(), dbTx)
-			if err != nil {
-				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load receipt for tx %v", tx.Hash().String()), err, true)
-			}
-			receipts = append(receipts, *receipt)
-		}
-
-		rpcBlock, err := types.NewBlock(state.HashPtr(l2Block.Hash()), l2Block, receipts, fullTx, false)
-		if err != nil {
-			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't build block response for block by hash %v", hash.Hash()), err, true)
-		}
-
-		return rpcBlock, nil
+		return getRPCBlockByHash(ctx, e.state, e.forkIDs, hash.Hash(), fullTx, false, nil, dbTx)
 	})
 }
 
@@ -329,7 +380,8 @@ func (e *EthEndpoints) GetBlockByNumber(number types.BlockNumber, fullTx bool) (
 				UncleHash:  ethTypes.EmptyUncleHash,
 			})
 			l2Block := state.NewL2BlockWithHeader(l2Header)
-			rpcBlock, err := types.NewBlock(nil, l2Block, nil, fullTx, false)
+			// the pending block has no exit roots yet, so they are zero regardless of the fork id
+			rpcBlock, err := types.NewBlock(nil, l2Block, nil, fullTx, false, 0)
 			if err != nil {
 				return RPCErrorResponse(types.DefaultErrorCode, "couldn't build the pending block response", err, true)
 			}
@@ -359,7 +411,12 @@ func (e *EthEndpoints) GetBlockByNumber(number types.BlockNumber, fullTx bool) (
 			receipts = append(receipts, *receipt)
 		}
 
-		rpcBlock, err := types.NewBlock(state.HashPtr(l2Block.Hash()), l2Block, receipts, fullTx, false)
+		forkID, err := e.forkIDs.get(ctx, e.state, l2Block.NumberU64(), dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load fork id for block %v", l2Block.NumberU64()), err, true)
+		}
+
+		rpcBlock, err := types.NewBlock(state.HashPtr(l2Block.Hash()), l2Block, receipts, fullTx, false, forkID)
 		if err != nil {
 			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't build block response for block by number %v", blockNumber), err, true)
 		}
@@ -377,6 +434,11 @@ func (e *EthEndpoints) GetCode(address types.ArgAddress, blockArg *types.BlockNu
 			return nil, rpcErr
 		}
 
+		key := codeCacheKey{address: address.Address(), stateRoot: block.Root()}
+		if code, found := e.getCodeFromCache(key); found {
+			return types.ArgBytes(code), nil
+		}
+
 		code, err := e.state.GetCode(ctx, address.Address(), block.Root())
 		if errors.Is(err, state.ErrNotFound) {
 			return "0x", nil
@@ -384,10 +446,29 @@ func (e *EthEndpoints) GetCode(address types.ArgAddress, blockArg *types.BlockNu
 			return RPCErrorResponse(types.DefaultErrorCode, "failed to get code", err, true)
 		}
 
+		e.addCodeToCache(key, code)
+
 		return types.ArgBytes(code), nil
 	})
 }
 
+// getCodeFromCache returns the cached code for the provided key, if any
+func (e *EthEndpoints) getCodeFromCache(key codeCacheKey) ([]byte, bool) {
+	if e.codeCache == nil {
+		return nil, false
+	}
+	return e.codeCache.Get(key)
+}
+
+// addCodeToCache stores the code for the provided key, the code of an address
+// never changes for the same state root so the entry never gets stale
+func (e *EthEndpoints) addCodeToCache(key codeCacheKey, code []byte) {
+	if e.codeCache == nil {
+		return
+	}
+	e.codeCache.Add(key, code)
+}
+
 // GetCompilers eth_getCompilers
 func (e *EthEndpoints) GetCompilers() (interface{}, types.Error) {
 	return []interface{}{}, nil
@@ -505,7 +586,11 @@ func (e *EthEndpoints) internalGetLogs(ctx context.Context, dbTx pgx.Tx, filter
 
 	result := make([]types.Log, 0, len(logs))
 	for _, l := range logs {
-		result = append(result, types.NewLog(*l))
+		rpcLog, err := types.NewLog(*l)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to build log response", err, true)
+		}
+		result = append(result, rpcLog)
 	}
 
 	return result, nil
@@ -513,9 +598,10 @@ func (e *EthEndpoints) internalGetLogs(ctx context.Context, dbTx pgx.Tx, filter
 
 // GetStorageAt gets the value stored for an specific address and position
 func (e *EthEndpoints) GetStorageAt(address types.ArgAddress, storageKeyStr string, blockArg *types.BlockNumberOrHash) (interface{}, types.Error) {
-	storageKey := types.ArgHash{}
-	err := storageKey.UnmarshalText([]byte(storageKeyStr))
-	if err != nil {
+	storageKey, err := types.DecodeStoragePosition(storageKeyStr)
+	if errors.Is(err, types.ErrInvalidStoragePosition) {
+		return RPCErrorResponse(types.InvalidParamsErrorCode, err.Error(), nil, false)
+	} else if err != nil {
 		return RPCErrorResponse(types.DefaultErrorCode, "unable to decode storage key: hex string invalid", nil, false)
 	}
 
@@ -525,7 +611,7 @@ func (e *EthEndpoints) GetStorageAt(address types.ArgAddress, storageKeyStr stri
 			return nil, respErr
 		}
 
-		value, err := e.state.GetStorageAt(ctx, address.Address(), storageKey.Hash().Big(), block.Root())
+		value, err := e.state.GetStorageAt(ctx, address.Address(), storageKey.Big(), block.Root())
 		if errors.Is(err, state.ErrNotFound) {
 			return types.ArgBytesPtr(common.Hash{}.Bytes()), nil
 		} else if err != nil {
@@ -538,6 +624,7 @@ func (e *EthEndpoints) GetStorageAt(address types.ArgAddress, storageKeyStr stri
 
 // GetTransactionByBlockHashAndIndex returns information about a transaction by
 // block hash and transaction index position.
+// Returns null when the block does not exist or the index is out of range.
 func (e *EthEndpoints) GetTransactionByBlockHashAndIndex(hash types.ArgHash, index types.Index) (interface{}, types.Error) {
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
 		tx, err := e.state.GetTransactionByL2BlockHashAndIndex(ctx, hash.Hash(), uint64(index), dbTx)
@@ -565,6 +652,7 @@ func (e *EthEndpoints) GetTransactionByBlockHashAndIndex(hash types.ArgHash, ind
 
 // GetTransactionByBlockNumberAndIndex returns information about a transaction by
 // block number and transaction index position.
+// Returns null when the block does not exist or the index is out of range.
 func (e *EthEndpoints) GetTransactionByBlockNumberAndIndex(number *types.BlockNumber, index types.Index) (interface{}, types.Error) {
 	return e.txMan.NewDbTxScope(e.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
 		var err error
@@ -630,7 +718,9 @@ func (e *EthEndpoints) GetTransactionByHash(hash types.ArgHash) (interface{}, ty
 		} else if err != nil {
 			return RPCErrorResponse(types.DefaultErrorCode, "failed to load transaction by hash from pool", err, true)
 		}
-		if poolTx.Status == pool.TxStatusPending {
+		// pending and selected txs are not mined yet, so they are returned
+		// without block hash, block number and tx index
+		if poolTx.Status == pool.TxStatusPending || poolTx.Status == pool.TxStatusSelected {
 			tx = &poolTx.Transaction
 			res, err := types.NewTransaction(*tx, nil, false)
 			if err != nil {
@@ -875,6 +965,11 @@ func (e *EthEndpoints) newPendingTransactionFilter(wsConn *concurrentWsConn) (in
 // - for Sequencer nodes it tries to add the tx to the pool
 // - for Non-Sequencer nodes it relays the Tx to the Sequencer node
 func (e *EthEndpoints) SendRawTransaction(httpRequest *http.Request, input string) (interface{}, types.Error) {
+	tx, rpcErr := decodeRawTx(input)
+	if rpcErr != nil {
+		return nil, rpcErr
+	}
+
 	if e.cfg.SequencerNodeURI != "" {
 		return e.relayTxToSequencerNode(input)
 	} else {
@@ -889,7 +984,7 @@ func (e *EthEndpoints) SendRawTransaction(httpRequest *http.Request, input strin
 			ip = strings.Split(ips, ",")[0]
 		}
 
-		return e.tryToAddTxToPool(input, ip)
+		return e.tryToAddTxToPool(tx, ip)
 	}
 }
 
@@ -908,11 +1003,7 @@ func (e *EthEndpoints) relayTxToSequencerNode(input string) (interface{}, types.
 	return txHash, nil
 }
 
-func (e *EthEndpoints) tryToAddTxToPool(input, ip string) (interface{}, types.Error) {
-	tx, err := hexToTx(input)
-	if err != nil {
-		return RPCErrorResponse(types.InvalidParamsErrorCode, "invalid tx input", err, false)
-	}
+func (e *EthEndpoints) tryToAddTxToPool(tx *ethTypes.Transaction, ip string) (interface{}, types.Error) {
 	log.Infof("adding TX to the pool: %v", tx.Hash().Hex())
 	if err := e.pool.AddTx(context.Background(), *tx, ip); err != nil {
 		// it's not needed to log the error here, because we check and log if needed
@@ -1012,6 +1103,23 @@ func hexToTx(str string) (*ethTypes.Transaction, error) {
 	return tx, nil
 }
 
+// decodeRawTx decodes a raw tx and checks its EIP-2718 envelope type is supported, so txs
+// that would be rejected by the pool aren't added to it or relayed to the sequencer node
+func decodeRawTx(input string) (*ethTypes.Transaction, types.Error) {
+	tx, err := hexToTx(input)
+	if err != nil {
+		_, rpcErr := RPCErrorResponse(types.InvalidParamsErrorCode, "invalid tx input", err, false)
+		return nil, rpcErr
+	}
+
+	// Accept only legacy transactions, as the pool does
+	if tx.Type() != ethTypes.LegacyTxType {
+		return nil, types.NewRPCError(types.DefaultErrorCode, fmt.Sprintf("unsupported transaction type: 0x%02x", tx.Type()))
+	}
+
+	return tx, nil
+}
+
 func (e *EthEndpoints) updateFilterLastPoll(filterID string) types.Error {
 	err := e.storage.UpdateFilterLastPoll(filterID)
 	if err != nil && !errors.Is(err, ErrNotFound) {
@@ -1076,7 +1184,12 @@ func (e *EthEndpoints) notifyNewHeads(wg *sync.WaitGroup, event state.NewL2Block
 	defer wg.Done()
 	start := time.Now()
 
-	b, err := types.NewBlock(state.HashPtr(event.Block.Hash()), &event.Block, nil, false, false)
+	forkID, err := e.forkIDs.get(context.Background(), e.state, event.Block.NumberU64(), nil)
+	if err != nil {
+		log.Errorf("failed to get the fork id of the block to build the response to subscription: %v", err)
+		return
+	}
+	b, err := types.NewBlock(state.HashPtr(event.Block.Hash()), &event.Block, nil, false, false, forkID)
 	if err != nil {
 		log.Errorf("failed to build block response to subscription: %v", err)
 		return
@@ -1245,7 +1358,12 @@ func filterLogs(logsToFilter []*ethTypes.Log, filter *Filter) []types.Log {
 			}
 		}
 		if match {
-			logs = append(logs, types.NewLog(*l))
+			rpcLog, err := types.NewLog(*l)
+			if err != nil {
+				log.Errorf("failed to build log response for log filter: %v", err)
+				continue
+			}
+			logs = append(logs, rpcLog)
 		}
 	}
 	return logs
@@ -1310,3 +1428,44 @@ func parallelize[T any](maxWorkers int, items []T, fn func(worker int, items []T
 	}
 	wg.Wait()
 }
+
+// getRPCBlockByHash returns the response of the L2 block with the given hash, nil if it doesn't exist. The zkEVM
+// extra info of the block is included if includeExtraInfo is true. checkTxs, if not nil, is called with the
+// number of txs of the block before loading their receipts
+func getRPCBlockByHash(ctx context.Context, st types.StateInterface, forkIDs *l2BlockForkIDCache, hash common.Hash, fullTx, includeExtraInfo bool,
+	checkTxs func(numTxs int) types.Error, dbTx pgx.Tx) (interface{}, types.Error) {
+	l2Block, err := st.GetL2BlockByHash(ctx, hash, dbTx)
+	if errors.Is(err, state.ErrNotFound) {
+		return nil, nil
+	} else if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get block by hash from state", err, true)
+	}
+
+	txs := l2Block.Transactions()
+	if checkTxs != nil {
+		if rpcErr := checkTxs(len(txs)); rpcErr != nil {
+			return nil, rpcErr
+		}
+	}
+
+	receipts := make([]ethTypes.Receipt, 0, len(txs))
+	for _, tx := range txs {
+		receipt, err := st.GetTransactionReceipt(ctx, tx.Hash(), dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load receipt for tx %v", tx.Hash().String()), err, true)
+		}
+		receipts = append(receipts, *receipt)
+	}
+
+	forkID, err := forkIDs.get(ctx, st, l2Block.NumberU64(), dbTx)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load fork id for block %v", l2Block.NumberU64()), err, true)
+	}
+
+	rpcBlock, err := types.NewBlock(state.HashPtr(l2Block.Hash()), l2Block, receipts, fullTx, includeExtraInfo, forkID)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't build block response for block by hash %v", hash), err, true)
+	}
+
+	return rpcBlock, nil
+}
@@ -14,6 +14,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/encoding"
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/mocks"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
@@ -22,10 +23,12 @@ import (
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/trie"
+	"github.com/holiman/uint256"
 	"github.com/jackc/pgx/v4"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -180,6 +183,39 @@ func TestCall(t *testing.T) {
 					Once()
 			},
 		},
+		{
+			name: "Transaction with explicit nonce does not read the nonce from state",
+			params: []interface{}{
+				types.TxArgs{
+					From:     state.HexToAddressPtr("0x1"),
+					To:       state.HexToAddressPtr("0x2"),
+					Gas:      types.ArgUint64Ptr(24000),
+					GasPrice: types.ArgBytesPtr(big.NewInt(1).Bytes()),
+					Value:    types.ArgBytesPtr(big.NewInt(2).Bytes()),
+					Data:     types.ArgBytesPtr([]byte("data")),
+					Nonce:    types.ArgUint64Ptr(42),
+				},
+				map[string]interface{}{
+					types.BlockNumberKey: hex.EncodeBig(blockNumOne),
+				},
+			},
+			expectedResult: []byte("hello world"),
+			expectedError:  nil,
+			setupMocks: func(c Config, m *mocksWrapper, testCase *testCase) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				txArgs := testCase.params[0].(types.TxArgs)
+				txMatchBy := mock.MatchedBy(func(tx *ethTypes.Transaction) bool {
+					return tx != nil && tx.Nonce() == uint64(*txArgs.Nonce)
+				})
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot}))
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
+				m.State.
+					On("ProcessUnsignedTransaction", context.Background(), txMatchBy, *txArgs.From, &blockNumOneUint64, true, m.DbTx).
+					Return(&runtime.ExecutionResult{ReturnValue: testCase.expectedResult}, nil).
+					Once()
+			},
+		},
 		{
 			name: "Transaction with all information from block by hash with EIP-1898",
 			params: []interface{}{
@@ -564,6 +600,112 @@ func TestCall(t *testing.T) {
 	}
 }
 
+func TestBatchCall(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	type testCase struct {
+		name            string
+		params          []interface{}
+		expectedResults []types.CallResult
+		expectedError   interface{}
+		setupMocks      func(*mocksWrapper, *testCase)
+	}
+
+	txArgs := types.TxArgs{
+		From:     state.HexToAddressPtr("0x1"),
+		To:       state.HexToAddressPtr("0x2"),
+		Gas:      types.ArgUint64Ptr(24000),
+		GasPrice: types.ArgBytesPtr(big.NewInt(1).Bytes()),
+		Value:    types.ArgBytesPtr(big.NewInt(2).Bytes()),
+		Data:     types.ArgBytesPtr([]byte("data")),
+	}
+
+	testCases := []*testCase{
+		{
+			name: "Execute calls successfully against the same block",
+			params: []interface{}{
+				[]types.TxArgs{txArgs, txArgs},
+				map[string]interface{}{
+					types.BlockNumberKey: hex.EncodeBig(blockNumOne),
+				},
+			},
+			expectedResults: []types.CallResult{
+				{Result: []byte("hello world")},
+				{Result: []byte{}, Error: runtime.ErrExecutionReverted.Error()},
+			},
+			expectedError: nil,
+			setupMocks: func(m *mocksWrapper, testCase *testCase) {
+				nonce := uint64(7)
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot}))
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumOneUint64, m.DbTx).Return(block, nil).Once()
+				m.State.On("GetNonce", context.Background(), *txArgs.From, blockRoot).Return(nonce, nil).Twice()
+				m.State.
+					On("ProcessUnsignedTransaction", context.Background(), mock.IsType(&ethTypes.Transaction{}), *txArgs.From, &blockNumOneUint64, true, m.DbTx).
+					Return(&runtime.ExecutionResult{ReturnValue: []byte("hello world")}, nil).
+					Once()
+				m.State.
+					On("ProcessUnsignedTransaction", context.Background(), mock.IsType(&ethTypes.Transaction{}), *txArgs.From, &blockNumOneUint64, true, m.DbTx).
+					Return(&runtime.ExecutionResult{Err: runtime.ErrExecutionReverted}, nil).
+					Once()
+			},
+		},
+		{
+			name: "Missing calls",
+			params: []interface{}{
+				[]types.TxArgs{},
+				latest,
+			},
+			expectedResults: nil,
+			expectedError:   types.NewRPCError(types.InvalidParamsErrorCode, "missing value for required argument 0"),
+			setupMocks: func(m *mocksWrapper, testCase *testCase) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+			},
+		},
+		{
+			name: "Missing block argument",
+			params: []interface{}{
+				[]types.TxArgs{txArgs},
+			},
+			expectedResults: nil,
+			expectedError:   types.NewRPCError(types.InvalidParamsErrorCode, "missing value for required argument 1"),
+			setupMocks: func(m *mocksWrapper, testCase *testCase) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			testCase.setupMocks(m, testCase)
+
+			res, err := s.JSONRPCCall("eth_batchCall", testCase.params...)
+			require.NoError(t, err)
+
+			if testCase.expectedResults != nil {
+				require.NotNil(t, res.Result)
+				require.Nil(t, res.Error)
+
+				var results []types.CallResult
+				err = json.Unmarshal(res.Result, &results)
+				require.NoError(t, err)
+
+				assert.Equal(t, testCase.expectedResults, results)
+			}
+
+			if testCase.expectedError != nil {
+				expectedErr := testCase.expectedError.(*types.RPCError)
+				assert.Equal(t, expectedErr.ErrorCode(), res.Error.Code)
+				assert.Equal(t, expectedErr.Error(), res.Error.Message)
+			}
+		})
+	}
+}
+
 func TestChainID(t *testing.T) {
 	s, _, c := newSequencerMockedServer(t)
 	defer s.Stop()
@@ -1047,7 +1189,7 @@ func TestGetL2BlockByHash(t *testing.T) {
 			Hash: common.HexToHash("0x345"),
 			ExpectedResult: ethTypes.NewBlock(
 				&ethTypes.Header{Number: big.NewInt(1), UncleHash: ethTypes.EmptyUncleHash, Root: ethTypes.EmptyRootHash},
-				[]*ethTypes.Transaction{ethTypes.NewTransaction(1, common.Address{}, big.NewInt(1), 1, big.NewInt(1), []byte{})},
+				[]*ethTypes.Transaction{signTx(ethTypes.NewTransaction(1, common.Address{}, big.NewInt(1), 1, big.NewInt(1), []byte{}), chainID)},
 				nil,
 				[]*ethTypes.Receipt{ethTypes.NewReceipt([]byte{}, false, uint64(0))},
 				&trie.StackTrie{},
@@ -1081,6 +1223,16 @@ func TestGetL2BlockByHash(t *testing.T) {
 						Return(ethTypes.NewReceipt([]byte{}, false, uint64(0)), nil).
 						Once()
 				}
+
+				m.State.
+					On("BatchNumberByL2BlockNumber", context.Background(), block.NumberU64(), m.DbTx).
+					Return(uint64(1), nil).
+					Once()
+
+				m.State.
+					On("GetForkIDByBatchNumber", uint64(1)).
+					Return(uint64(state.FORKID_ETROG)).
+					Once()
 			},
 		},
 	}
@@ -1311,6 +1463,16 @@ func TestGetL2BlockByNumber(t *testing.T) {
 						Return(receipt, nil).
 						Once()
 				}
+
+				m.State.
+					On("BatchNumberByL2BlockNumber", context.Background(), l2Block.NumberU64(), m.DbTx).
+					Return(uint64(1), nil).
+					Once()
+
+				m.State.
+					On("GetForkIDByBatchNumber", uint64(1)).
+					Return(uint64(state.FORKID_ETROG)).
+					Once()
 			},
 		},
 		{
@@ -1345,6 +1507,8 @@ func TestGetL2BlockByNumber(t *testing.T) {
 						Return(receipt, nil).
 						Once()
 				}
+
+				// the fork id of the block was cached by the previous case
 			},
 		},
 		{
@@ -1413,6 +1577,7 @@ func TestGetL2BlockByNumber(t *testing.T) {
 				tc.ExpectedResult.Sha3Uncles = ethTypes.EmptyUncleHash
 				tc.ExpectedResult.Size = 501
 				tc.ExpectedResult.ExtraData = []byte{}
+				tc.ExpectedResult.Nonce = types.ArgBytesPtr(make([]byte, 8))
 
 				m.DbTx.
 					On("Commit", context.Background()).
@@ -1773,6 +1938,43 @@ func TestGetCode(t *testing.T) {
 	}
 }
 
+func TestGetCodeUsesCache(t *testing.T) {
+	st := mocks.NewStateMock(t)
+	dbTx := mocks.NewDBTxMock(t)
+	var newL2BlockEventHandler state.NewL2BlockEventHandler = func(e state.NewL2BlockEvent) {}
+	st.On("RegisterNewL2BlockEventHandler", mock.IsType(newL2BlockEventHandler)).Once()
+
+	e := NewEthEndpoints(Config{GetCodeCacheSize: 10}, chainID, mocks.NewPoolMock(t), st, mocks.NewEthermanMock(t), newStorageMock(t))
+
+	expectedCode := []byte{1, 2, 3}
+	block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot}))
+	st.On("BeginStateTransaction", context.Background()).Return(dbTx, nil).Twice()
+	dbTx.On("Commit", context.Background()).Return(nil).Twice()
+	st.On("GetL2BlockByNumber", context.Background(), blockNumOne.Uint64(), dbTx).Return(block, nil).Twice()
+	st.On("GetCode", context.Background(), addressArg, blockRoot).Return(expectedCode, nil).Once()
+
+	blockArg := types.BlockNumberOrHash{}
+	blockArg.SetNumber(types.BlockNumber(blockNumOne.Int64()))
+	for i := 0; i < 2; i++ {
+		code, err := e.GetCode(types.ArgAddress(addressArg), &blockArg)
+		require.Nil(t, err)
+		assert.Equal(t, types.ArgBytes(expectedCode), code)
+	}
+}
+
+func BenchmarkGetCodeFromCache(b *testing.B) {
+	e := &EthEndpoints{codeCache: lru.NewCache[codeCacheKey, []byte](10000)}
+	key := codeCacheKey{address: addressArg, stateRoot: blockRoot}
+	e.addCodeToCache(key, make([]byte, 24576))
+
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		if _, found := e.getCodeFromCache(key); !found {
+			b.Fatal("code not found in cache")
+		}
+	}
+}
+
 func TestGetStorageAt(t *testing.T) {
 	s, m, _ := newSequencerMockedServer(t)
 	defer s.Stop()
@@ -1947,6 +2149,85 @@ func TestGetStorageAt(t *testing.T) {
 					Once()
 			},
 		},
+		{
+			Name: "get storage at short position 0x0",
+			Params: []interface{}{
+				addressArg.String(),
+				"0x0",
+				map[string]interface{}{
+					types.BlockNumberKey: hex.EncodeBig(blockNumOne),
+				},
+			},
+			ExpectedResult: common.BigToHash(big.NewInt(123)).Bytes(),
+			ExpectedError:  nil,
+
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				blockNumber := big.NewInt(1)
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumber, Root: blockRoot}))
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumber.Uint64(), m.DbTx).Return(block, nil).Once()
+
+				m.State.
+					On("GetStorageAt", context.Background(), addressArg, common.Hash{}.Big(), blockRoot).
+					Return(big.NewInt(123), nil).
+					Once()
+			},
+		},
+		{
+			Name: "get storage at short position 0x1",
+			Params: []interface{}{
+				addressArg.String(),
+				"0x1",
+				map[string]interface{}{
+					types.BlockNumberKey: hex.EncodeBig(blockNumOne),
+				},
+			},
+			ExpectedResult: common.BigToHash(big.NewInt(123)).Bytes(),
+			ExpectedError:  nil,
+
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				blockNumber := big.NewInt(1)
+				block := state.NewL2BlockWithHeader(state.NewL2Header(&ethTypes.Header{Number: blockNumber, Root: blockRoot}))
+				m.State.On("GetL2BlockByNumber", context.Background(), blockNumber.Uint64(), m.DbTx).Return(block, nil).Once()
+
+				m.State.
+					On("GetStorageAt", context.Background(), addressArg, common.BigToHash(big.NewInt(1)).Big(), blockRoot).
+					Return(big.NewInt(123), nil).
+					Once()
+			},
+		},
+		{
+			Name: "storage position longer than 32 bytes",
+			Params: []interface{}{
+				addressArg.String(),
+				"0x01" + strings.Repeat("00", common.HashLength),
+				map[string]interface{}{
+					types.BlockNumberKey: hex.EncodeBig(blockNumOne),
+				},
+			},
+			ExpectedResult: nil,
+			ExpectedError:  types.NewRPCError(types.InvalidParamsErrorCode, types.ErrInvalidStoragePosition.Error()),
+			SetupMocks:     func(m *mocksWrapper, tc *testCase) {},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -2622,7 +2903,7 @@ func TestGetTransactionByHash(t *testing.T) {
 			Name:            "Get TX Successfully from pool",
 			Hash:            common.HexToHash("0x123"),
 			ExpectedPending: true,
-			ExpectedResult:  ethTypes.NewTransaction(1, common.Address{}, big.NewInt(1), 1, big.NewInt(1), []byte{}),
+			ExpectedResult:  signTx(ethTypes.NewTransaction(1, common.Address{}, big.NewInt(1), 1, big.NewInt(1), []byte{}), chainID),
 			ExpectedError:   nil,
 			SetupMocks: func(m *mocksWrapper, tc testCase) {
 				m.DbTx.
@@ -2674,6 +2955,62 @@ func TestGetTransactionByHash(t *testing.T) {
 					Once()
 			},
 		},
+		{
+			Name:            "Get selected TX from pool",
+			Hash:            common.HexToHash("0x123"),
+			ExpectedPending: true,
+			ExpectedResult:  signTx(ethTypes.NewTransaction(1, common.Address{}, big.NewInt(1), 1, big.NewInt(1), []byte{}), chainID),
+			ExpectedError:   nil,
+			SetupMocks: func(m *mocksWrapper, tc testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetTransactionByHash", context.Background(), tc.Hash, m.DbTx).
+					Return(nil, state.ErrNotFound).
+					Once()
+
+				m.Pool.
+					On("GetTxByHash", context.Background(), tc.Hash).
+					Return(&pool.Transaction{Transaction: *tc.ExpectedResult, Status: pool.TxStatusSelected}, nil).
+					Once()
+			},
+		},
+		{
+			Name:            "Failed TX in pool is not returned",
+			Hash:            common.HexToHash("0x123"),
+			ExpectedPending: false,
+			ExpectedResult:  nil,
+			ExpectedError:   ethereum.NotFound,
+			SetupMocks: func(m *mocksWrapper, tc testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetTransactionByHash", context.Background(), tc.Hash, m.DbTx).
+					Return(nil, state.ErrNotFound).
+					Once()
+
+				m.Pool.
+					On("GetTxByHash", context.Background(), tc.Hash).
+					Return(&pool.Transaction{Transaction: *ethTypes.NewTransaction(1, common.Address{}, big.NewInt(1), 1, big.NewInt(1), []byte{}), Status: pool.TxStatusFailed}, nil).
+					Once()
+			},
+		},
 		{
 			Name:            "TX failed to load from the state",
 			Hash:            common.HexToHash("0x123"),
@@ -3594,6 +3931,34 @@ func TestSendRawTransactionJSONRPCCall(t *testing.T) {
 			},
 			SetupMocks: func(t *testing.T, m *mocksWrapper, tc testCase) {},
 		},
+		{
+			Name: "Send dynamic fee tx",
+			Prepare: func(t *testing.T, tc *testCase) {
+				tx := ethTypes.NewTx(&ethTypes.DynamicFeeTx{ChainID: big.NewInt(1), Nonce: 1, GasTipCap: big.NewInt(1), GasFeeCap: big.NewInt(1), Gas: 1, Value: big.NewInt(1)})
+
+				txBinary, err := tx.MarshalBinary()
+				require.NoError(t, err)
+
+				tc.Input = hex.EncodeToHex(txBinary)
+				tc.ExpectedResult = nil
+				tc.ExpectedError = types.NewRPCError(types.DefaultErrorCode, "unsupported transaction type: 0x02")
+			},
+			SetupMocks: func(t *testing.T, m *mocksWrapper, tc testCase) {},
+		},
+		{
+			Name: "Send blob tx",
+			Prepare: func(t *testing.T, tc *testCase) {
+				tx := ethTypes.NewTx(&ethTypes.BlobTx{ChainID: uint256.NewInt(1), Nonce: 1, GasTipCap: uint256.NewInt(1), GasFeeCap: uint256.NewInt(1), Gas: 1, Value: uint256.NewInt(1), BlobFeeCap: uint256.NewInt(1)})
+
+				txBinary, err := tx.MarshalBinary()
+				require.NoError(t, err)
+
+				tc.Input = hex.EncodeToHex(txBinary)
+				tc.ExpectedResult = nil
+				tc.ExpectedError = types.NewRPCError(types.DefaultErrorCode, "unsupported transaction type: 0x03")
+			},
+			SetupMocks: func(t *testing.T, m *mocksWrapper, tc testCase) {},
+		},
 	}
 
 	for _, testCase := range testCases {
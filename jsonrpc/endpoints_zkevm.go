@@ -2,13 +2,17 @@ package jsonrpc
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/gasprice"
 	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/l1infotree"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
@@ -18,19 +22,37 @@ import (
 
 // ZKEVMEndpoints contains implementations for the "zkevm" RPC endpoints
 type ZKEVMEndpoints struct {
-	cfg      Config
-	state    types.StateInterface
-	etherman types.EthermanInterface
-	txMan    DBTxManager
-}
-
-// NewZKEVMEndpoints returns ZKEVMEndpoints
-func NewZKEVMEndpoints(cfg Config, state types.StateInterface, etherman types.EthermanInterface) *ZKEVMEndpoints {
-	return &ZKEVMEndpoints{
-		cfg:      cfg,
-		state:    state,
-		etherman: etherman,
+	cfg              Config
+	chainID          uint64
+	pool             types.PoolInterface
+	state            types.StateInterface
+	etherman         types.EthermanInterface
+	batchConstraints state.BatchConstraintsCfg
+	gasPriceCfg      gasprice.Config
+	storage          storageInterface
+	txMan            DBTxManager
+	// forkIDs resolves the fork id of the L2 blocks returned
+	forkIDs *l2BlockForkIDCache
+}
+
+// NewZKEVMEndpoints returns ZKEVMEndpoints, batchConstraints are used to compute the fill
+// percentage of the wip batch of the sequencer and gasPriceCfg is the config of the L2 gas
+// price suggester, used to explain the suggested gas price
+func NewZKEVMEndpoints(cfg Config, chainID uint64, pool types.PoolInterface, state types.StateInterface, etherman types.EthermanInterface, batchConstraints state.BatchConstraintsCfg, gasPriceCfg gasprice.Config, storage storageInterface) *ZKEVMEndpoints {
+	z := &ZKEVMEndpoints{
+		cfg:              cfg,
+		chainID:          chainID,
+		pool:             pool,
+		state:            state,
+		etherman:         etherman,
+		batchConstraints: batchConstraints,
+		gasPriceCfg:      gasPriceCfg,
+		storage:          storage,
+		forkIDs:          newL2BlockForkIDCache(),
 	}
+	state.RegisterNewVerifiedBatchEventHandler(z.onNewVerifiedBatch)
+
+	return z
 }
 
 // ConsolidatedBlockNumber returns last block number related to the last verified batch
@@ -180,6 +202,7 @@ func (z *ZKEVMEndpoints) GetBatchByNumber(batchNumber types.BatchNumber, fullTx
 		if err != nil && !errors.Is(err, state.ErrNotFound) {
 			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load full GER from state by number %v", batchNumber), err, true)
 		} else if errors.Is(err, state.ErrNotFound) {
+			// The batch is returned with zero exit roots, NewBatch reports them as invalid
 			ger = &state.GlobalExitRoot{}
 		}
 
@@ -189,14 +212,83 @@ func (z *ZKEVMEndpoints) GetBatchByNumber(batchNumber types.BatchNumber, fullTx
 		}
 
 		batch.Transactions = txs
-		rpcBatch, err := types.NewBatch(batch, virtualBatch, verifiedBatch, blocks, receipts, fullTx, true, ger)
-		if err != nil {
+		rpcBatch, err := types.NewBatch(batch, virtualBatch, verifiedBatch, blocks, receipts, fullTx, true, true, ger, z.state.GetForkIDByBatchNumber(batchNumber))
+		if errors.Is(err, types.ErrInvalidExitRoots) {
+			log.Warnf("batch %v response built with invalid exit roots: %v", batchNumber, err)
+		} else if err != nil {
 			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't build the batch %v response", batchNumber), err, true)
 		}
 		return rpcBatch, nil
 	})
 }
 
+// GetBatchStats returns the distribution of the gas used by the transactions of a batch by batch number
+func (z *ZKEVMEndpoints) GetBatchStats(batchNumber types.BatchNumber) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		batchNumber, rpcErr := batchNumber.GetNumericBatchNumber(ctx, z.state, z.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		_, err := z.state.GetBatchByNumber(ctx, batchNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load batch from state by number %v", batchNumber), err, true)
+		}
+
+		txsGasUsed, err := z.state.GetTxsGasUsedByBatchNumber(ctx, batchNumber, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load the gas used by the batch txs from state by number %v", batchNumber), err, true)
+		}
+
+		return types.NewBatchStats(batchNumber, len(txsGasUsed), state.ComputeGasProfile(txsGasUsed)), nil
+	})
+}
+
+// GetVirtualBatch returns the sequencing information of a batch by batch number
+func (z *ZKEVMEndpoints) GetVirtualBatch(batchNumber types.BatchNumber) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		batchNumber, rpcErr := batchNumber.GetNumericBatchNumber(ctx, z.state, z.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		virtualBatch, err := z.state.GetVirtualBatch(ctx, batchNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load virtual batch from state by number %v", batchNumber), err, true)
+		}
+
+		return types.NewVirtualBatch(virtualBatch), nil
+	})
+}
+
+// GetVerifiedBatch returns the verification information of a batch by batch number
+func (z *ZKEVMEndpoints) GetVerifiedBatch(batchNumber types.BatchNumber) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		batchNumber, rpcErr := batchNumber.GetNumericBatchNumber(ctx, z.state, z.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		verifiedBatch, err := z.state.GetVerifiedBatch(ctx, batchNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load verified batch from state by number %v", batchNumber), err, true)
+		}
+
+		l1Block, err := z.state.GetBlockByNumber(ctx, verifiedBatch.BlockNumber, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load L1 block %v where the batch %v was verified", verifiedBatch.BlockNumber, batchNumber), err, true)
+		}
+
+		return types.NewVerifiedBatch(verifiedBatch, l1Block), nil
+	})
+}
+
 // GetFullBlockByNumber returns information about a block by block number
 func (z *ZKEVMEndpoints) GetFullBlockByNumber(number types.BlockNumber, fullTx bool) (interface{}, types.Error) {
 	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
@@ -212,7 +304,8 @@ func (z *ZKEVMEndpoints) GetFullBlockByNumber(number types.BlockNumber, fullTx b
 				UncleHash:  ethTypes.EmptyUncleHash,
 			})
 			l2Block := state.NewL2BlockWithHeader(l2Header)
-			rpcBlock, err := types.NewBlock(nil, l2Block, nil, fullTx, false)
+			// the pending block has no exit roots yet, so they are zero regardless of the fork id
+			rpcBlock, err := types.NewBlock(nil, l2Block, nil, fullTx, false, 0)
 			if err != nil {
 				return RPCErrorResponse(types.DefaultErrorCode, "couldn't build the pending block response", err, true)
 			}
@@ -233,6 +326,10 @@ func (z *ZKEVMEndpoints) GetFullBlockByNumber(number types.BlockNumber, fullTx b
 		}
 
 		txs := l2Block.Transactions()
+		if rpcErr := z.checkInlineReceiptTxs(len(txs)); rpcErr != nil {
+			return nil, rpcErr
+		}
+
 		receipts := make([]ethTypes.Receipt, 0, len(txs))
 		for _, tx := range txs {
 			receipt, err := z.state.GetTransactionReceipt(ctx, tx.Hash(), dbTx)
@@ -242,7 +339,12 @@ func (z *ZKEVMEndpoints) GetFullBlockByNumber(number types.BlockNumber, fullTx b
 			receipts = append(receipts, *receipt)
 		}
 
-		rpcBlock, err := types.NewBlock(state.HashPtr(l2Block.Hash()), l2Block, receipts, fullTx, true)
+		forkID, err := z.forkIDs.get(ctx, z.state, l2Block.NumberU64(), dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load fork id for block %v", l2Block.NumberU64()), err, true)
+		}
+
+		rpcBlock, err := types.NewBlock(state.HashPtr(l2Block.Hash()), l2Block, receipts, fullTx, true, forkID)
 		if err != nil {
 			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't build block response for block by number %v", blockNumber), err, true)
 		}
@@ -254,29 +356,311 @@ func (z *ZKEVMEndpoints) GetFullBlockByNumber(number types.BlockNumber, fullTx b
 // GetFullBlockByHash returns information about a block by hash
 func (z *ZKEVMEndpoints) GetFullBlockByHash(hash types.ArgHash, fullTx bool) (interface{}, types.Error) {
 	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
-		l2Block, err := z.state.GetL2BlockByHash(ctx, hash.Hash(), dbTx)
+		return getRPCBlockByHash(ctx, z.state, z.forkIDs, hash.Hash(), fullTx, true, z.checkInlineReceiptTxs, dbTx)
+	})
+}
+
+// GetL2BlockByHash returns information about a block by hash, the same as eth_getBlockByHash, with the
+// zkEVM fields of the L2 block (globalExitRoot and blockInfoRoot)
+func (z *ZKEVMEndpoints) GetL2BlockByHash(hash types.ArgHash, fullTx bool) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		return getRPCBlockByHash(ctx, z.state, z.forkIDs, hash.Hash(), fullTx, false, nil, dbTx)
+	})
+}
+
+// GetTransactionByBatchNumberAndIndex returns the transaction at the given position of a batch,
+// with its receipt when available, null if the batch doesn't have a transaction at that position
+func (z *ZKEVMEndpoints) GetTransactionByBatchNumberAndIndex(batchNumber types.BatchNumber, index types.Index) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		batchNumber, rpcErr := batchNumber.GetNumericBatchNumber(ctx, z.state, z.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		tx, err := z.state.GetTransactionByBatchNumberAndIndex(ctx, batchNumber, uint64(index), dbTx)
 		if errors.Is(err, state.ErrNotFound) {
 			return nil, nil
 		} else if err != nil {
-			return RPCErrorResponse(types.DefaultErrorCode, "failed to get block by hash from state", err, true)
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load transaction %v from state by batch number %v", index, batchNumber), err, true)
 		}
 
-		txs := l2Block.Transactions()
-		receipts := make([]ethTypes.Receipt, 0, len(txs))
-		for _, tx := range txs {
-			receipt, err := z.state.GetTransactionReceipt(ctx, tx.Hash(), dbTx)
+		receipt, err := z.state.GetTransactionReceipt(ctx, tx.Hash(), dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			receipt = nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load receipt for tx %v", tx.Hash().String()), err, true)
+		}
+
+		res, err := types.NewTransaction(*tx, receipt, true)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to build transaction response", err, true)
+		}
+
+		return res, nil
+	})
+}
+
+// GetBlockInfoRoot returns the block info root and the global exit root of a L2 block, read from its
+// header without loading its transactions. Both roots are zero for the blocks before etrog
+func (z *ZKEVMEndpoints) GetBlockInfoRoot(number types.BlockNumber) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		blockNumber, rpcErr := number.GetNumericBlockNumber(ctx, z.state, z.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		header, err := z.state.GetL2BlockHeaderByNumber(ctx, blockNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load block header from state by number %v", blockNumber), err, true)
+		}
+
+		forkID, err := z.forkIDs.get(ctx, z.state, blockNumber, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load fork id for block %v", blockNumber), err, true)
+		}
+
+		res := types.BlockInfoRoot{
+			BlockNumber: types.ArgUint64(blockNumber),
+		}
+		// The blocks before etrog don't have these roots
+		if forkID >= state.FORKID_ETROG {
+			res.BlockInfoRoot = header.BlockInfoRoot
+			res.GlobalExitRoot = header.GlobalExitRoot
+		}
+		return res, nil
+	})
+}
+
+// GetProof returns the merkle proofs of the given account and storage keys. The zkEVM state is a
+// sparse merkle tree, so the proofs don't follow the EIP-1186 format of eth_getProof
+func (z *ZKEVMEndpoints) GetProof(address types.ArgAddress, storageKeys []types.ArgHash, number types.BlockNumber) (interface{}, types.Error) {
+	keys := make([]common.Hash, 0, len(storageKeys))
+	positions := make([]*big.Int, 0, len(storageKeys))
+	for _, storageKey := range storageKeys {
+		keys = append(keys, storageKey.Hash())
+		positions = append(positions, storageKey.Hash().Big())
+	}
+
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		blockNumber, rpcErr := number.GetNumericBlockNumber(ctx, z.state, z.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		header, err := z.state.GetL2BlockHeaderByNumber(ctx, blockNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, types.NewRPCError(types.DefaultErrorCode, "header not found")
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load block header from state by number %v", blockNumber), err, true)
+		}
+
+		proof, err := z.state.GetAccountProof(ctx, address.Address(), positions, header.Root)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get proof from state", err, true)
+		}
+
+		return types.NewAccountProof(address.Address(), keys, proof), nil
+	})
+}
+
+// GetTransactionEffectiveGasPrice returns the effective gas price of a tx, null if the tx is pending or
+// it was processed before the effective gas price was stored
+func (z *ZKEVMEndpoints) GetTransactionEffectiveGasPrice(hash types.ArgHash) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		effectiveGasPrice, err := z.state.GetTransactionEffectiveGasPrice(ctx, hash.Hash(), dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get transaction effective gas price from state", err, true)
+		}
+		if effectiveGasPrice == nil {
+			return nil, nil
+		}
+
+		return types.TransactionEffectiveGasPrice{
+			TxHash:            hash.Hash(),
+			EffectiveGasPrice: types.ArgBig(*effectiveGasPrice),
+		}, nil
+	})
+}
+
+// GetGasStationPrice returns the gas prices used by the sequencer to accept txs. minGasPrice
+// is the min gas price allowed by the pool. When the follower gas price suggester is used the
+// suggestedGasPrice is computed as
+//
+//	suggestedGasPrice = max(minGasPrice, l1GasPrice * l1GasPriceFactor)
+//
+// capped by the configured max gas price and truncated to its 3 most significant digits,
+// so a tx sent with a gas price equal or greater than suggestedGasPrice is not underpriced.
+// For the other suggester types l1GasPriceFactor is not used and it's returned as 0
+func (z *ZKEVMEndpoints) GetGasStationPrice() (interface{}, types.Error) {
+	if z.cfg.SequencerNodeURI != "" {
+		return z.getGasStationPriceFromSequencerNode()
+	}
+	ctx := context.Background()
+	gasPrices, err := z.pool.GetGasPrices(ctx)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get gas prices from pool", err, true)
+	}
+	gasStationPrice := types.GasStationPrice{
+		MinGasPrice:       types.ArgUint64(z.pool.GetDefaultMinGasPriceAllowed()),
+		SuggestedGasPrice: types.ArgUint64(gasPrices.L2GasPrice),
+		L1GasPrice:        types.ArgUint64(gasPrices.L1GasPrice),
+	}
+	if z.gasPriceCfg.Type == gasprice.FollowerType {
+		gasStationPrice.L1GasPriceFactor = z.gasPriceCfg.Factor
+	}
+	return gasStationPrice, nil
+}
+
+func (z *ZKEVMEndpoints) getGasStationPriceFromSequencerNode() (interface{}, types.Error) {
+	res, err := client.JSONRPCCall(z.cfg.SequencerNodeURI, "zkevm_getGasStationPrice")
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to get gas station price from sequencer node", err, true)
+	}
+
+	if res.Error != nil {
+		return RPCErrorResponse(res.Error.Code, res.Error.Message, nil, false)
+	}
+
+	var gasStationPrice types.GasStationPrice
+	err = json.Unmarshal(res.Result, &gasStationPrice)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to read gas station price from sequencer node", err, true)
+	}
+	return gasStationPrice, nil
+}
+
+// checkInlineReceiptTxs returns an error if a block with the provided number of txs
+// can't be returned with the receipts inline
+func (z *ZKEVMEndpoints) checkInlineReceiptTxs(numTxs int) types.Error {
+	if z.cfg.MaxInlineReceiptTxs > 0 && uint64(numTxs) > z.cfg.MaxInlineReceiptTxs {
+		errMsg := fmt.Sprintf(state.ErrMaxInlineReceiptTxsLimitExceeded.Error(), z.cfg.MaxInlineReceiptTxs)
+		return types.NewRPCError(types.InvalidParamsErrorCode, errMsg)
+	}
+	return nil
+}
+
+// GetBatchWitness returns the input the prover needs to prove the batch, this endpoint
+// is only available when the admin API is enabled due to its computational cost
+func (z *ZKEVMEndpoints) GetBatchWitness(batchNumber types.BatchNumber) (interface{}, types.Error) {
+	if !z.cfg.AdminAPIEnabled {
+		return nil, types.NewRPCError(types.DefaultErrorCode, "admin API is disabled")
+	}
+
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		batchNumber, rpcErr := batchNumber.GetNumericBatchNumber(ctx, z.state, z.etherman, dbTx)
+		if rpcErr != nil {
+			return nil, rpcErr
+		}
+
+		if batchNumber == 0 {
+			return nil, types.NewRPCError(types.InvalidParamsErrorCode, "genesis batch has no witness")
+		}
+
+		forkID := z.state.GetForkIDByBatchNumber(batchNumber)
+		if forkID < state.FORKID_ETROG {
+			return nil, types.NewRPCError(types.InvalidParamsErrorCode, fmt.Sprintf("batch witness is not available for batches before fork id %v", state.FORKID_ETROG))
+		}
+
+		batch, err := z.state.GetBatchByNumber(ctx, batchNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load batch from state by number %v", batchNumber), err, true)
+		}
+
+		previousBatch, err := z.state.GetBatchByNumber(ctx, batchNumber-1, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load previous batch from state by number %v", batchNumber-1), err, true)
+		}
+
+		l1InfoTreeData := map[uint32]state.L1DataV2{}
+		l1InfoRoot := state.ZeroHash
+		forcedBlockHashL1 := state.ZeroHash
+		// The initial batch and the forced batches don't use the L1 info tree, they are
+		// processed with the parent hash of the L1 block where they were sequenced or forced
+		if batch.BatchNumber == 1 {
+			forcedBlockHashL1, err = z.state.GetVirtualBatchParentHash(ctx, batch.BatchNumber, dbTx)
 			if err != nil {
-				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load receipt for tx %v", tx.Hash().String()), err, true)
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load L1 parent hash of batch %v", batchNumber), err, true)
+			}
+		} else if batch.ForcedBatchNum != nil {
+			l1InfoRoot = batch.GlobalExitRoot
+			forcedBlockHashL1, err = z.state.GetForcedBatchParentHash(ctx, *batch.ForcedBatchNum, dbTx)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load L1 parent hash of forced batch %v", *batch.ForcedBatchNum), err, true)
+			}
+		} else {
+			l1InfoTreeData, l1InfoRoot, err = z.state.GetL1InfoTreeDataFromBatchL2Data(ctx, batch.BatchL2Data, dbTx)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load L1 info tree data for batch %v", batchNumber), err, true)
+			}
+			l1InfoTreeData, err = z.addL1InfoTreeSmtProofs(ctx, l1InfoTreeData, l1InfoRoot, dbTx)
+			if err != nil {
+				return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't compute L1 info tree proofs for batch %v", batchNumber), err, true)
 			}
-			receipts = append(receipts, *receipt)
 		}
 
-		rpcBlock, err := types.NewBlock(state.HashPtr(l2Block.Hash()), l2Block, receipts, fullTx, true)
+		return types.NewBatchWitness(batch, previousBatch, l1InfoTreeData, l1InfoRoot, forcedBlockHashL1, z.chainID, forkID), nil
+	})
+}
+
+// addL1InfoTreeSmtProofs returns a copy of the L1 info tree data with the proof of each leaf
+// against the L1 info root
+func (z *ZKEVMEndpoints) addL1InfoTreeSmtProofs(ctx context.Context, l1InfoTreeData map[uint32]state.L1DataV2, l1InfoRoot common.Hash, dbTx pgx.Tx) (map[uint32]state.L1DataV2, error) {
+	res := make(map[uint32]state.L1DataV2, len(l1InfoTreeData))
+	if len(l1InfoTreeData) == 0 {
+		return res, nil
+	}
+
+	storedLeaves, err := z.state.GetLeafsByL1InfoRoot(ctx, l1InfoRoot, dbTx)
+	if err != nil {
+		return nil, err
+	}
+	leaves := make([][32]byte, 0, len(storedLeaves))
+	for _, leaf := range storedLeaves {
+		leaves = append(leaves, leaf.L1InfoTreeLeaf.Hash())
+	}
+
+	tree, err := l1infotree.NewL1InfoTree(32, [][32]byte{}) //nolint:gomnd
+	if err != nil {
+		return nil, err
+	}
+
+	for index, data := range l1InfoTreeData {
+		smtProof, _, err := tree.ComputeMerkleProof(index, leaves)
 		if err != nil {
-			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't build block response for block by hash %v", hash.Hash()), err, true)
+			return nil, err
 		}
+		data.SmtProof = make([][]byte, 0, len(smtProof))
+		for _, proof := range smtProof {
+			proof := proof
+			data.SmtProof = append(data.SmtProof, proof[:])
+		}
+		res[index] = data
+	}
 
-		return rpcBlock, nil
+	return res, nil
+}
+
+// GetBatchesByStateRoot returns the batches that reference the provided state root, either as
+// the state root after processing them or as their initial state root. It's meant to diagnose
+// state root mismatches, so it's only available when the admin API is enabled
+func (z *ZKEVMEndpoints) GetBatchesByStateRoot(stateRoot types.ArgHash) (interface{}, types.Error) {
+	if !z.cfg.AdminAPIEnabled {
+		return nil, types.NewRPCError(types.DefaultErrorCode, "admin API is disabled")
+	}
+
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		batches, err := z.state.GetBatchesByStateRoot(ctx, stateRoot.Hash(), dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load batches from state by state root %v", stateRoot.Hash().String()), err, true)
+		}
+
+		return types.NewBatchesByStateRoot(batches, stateRoot.Hash()), nil
 	})
 }
 
@@ -302,12 +686,41 @@ func (z *ZKEVMEndpoints) GetNativeBlockHashesInRange(filter NativeBlockHashBlock
 	})
 }
 
+// GetForcedBatchesByRange returns the forced batches included in the L1 blocks from fromL1Block
+// to toL1Block, both included. The L1 block range is limited to MaxForcedBatchRangeSize, larger
+// ranges must be queried as consecutive ranges, starting each one at the next L1 block of the
+// previous one
+func (z *ZKEVMEndpoints) GetForcedBatchesByRange(fromL1Block, toL1Block types.ArgUint64) (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		if toL1Block < fromL1Block {
+			return RPCErrorResponse(types.InvalidParamsErrorCode, state.ErrInvalidBlockRange.Error(), nil, false)
+		}
+
+		if z.cfg.MaxForcedBatchRangeSize > 0 && uint64(toL1Block-fromL1Block) > z.cfg.MaxForcedBatchRangeSize {
+			errMsg := fmt.Sprintf(state.ErrMaxForcedBatchRangeLimitExceeded.Error(), z.cfg.MaxForcedBatchRangeSize)
+			return RPCErrorResponse(types.InvalidParamsErrorCode, errMsg, nil, false)
+		}
+
+		forcedBatches, err := z.state.GetForcedBatchesByL1BlockRange(ctx, uint64(fromL1Block), uint64(toL1Block), dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get forced batches from state", err, true)
+		}
+
+		result := make([]types.ForcedBatch, 0, len(forcedBatches))
+		for _, forcedBatch := range forcedBatches {
+			result = append(result, types.NewForcedBatch(forcedBatch))
+		}
+
+		return result, nil
+	})
+}
+
 // GetExitRootsByGER returns the exit roots accordingly to the provided Global Exit Root
 func (z *ZKEVMEndpoints) GetExitRootsByGER(globalExitRoot common.Hash) (interface{}, types.Error) {
 	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
 		exitRoots, err := z.state.GetExitRootByGlobalExitRoot(ctx, globalExitRoot, dbTx)
 		if errors.Is(err, state.ErrNotFound) {
-			return nil, nil
+			return RPCErrorResponse(types.DefaultErrorCode, state.ErrGERNotFound.Error(), nil, false)
 		} else if err != nil {
 			return RPCErrorResponse(types.DefaultErrorCode, "failed to get exit roots by global exit root from state", err, true)
 		}
@@ -318,3 +731,84 @@ func (z *ZKEVMEndpoints) GetExitRootsByGER(globalExitRoot common.Hash) (interfac
 		}, nil
 	})
 }
+
+// GetLatestGlobalExitRoot returns the most recent global exit root along with its exit roots
+func (z *ZKEVMEndpoints) GetLatestGlobalExitRoot() (interface{}, types.Error) {
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		ger, err := z.state.GetLastGlobalExitRoot(ctx, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get the latest global exit root from state", err, true)
+		}
+
+		batchNumber, err := z.state.GetLastBatchNumber(ctx, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get the last batch number from state", err, true)
+		}
+
+		return types.NewGlobalExitRoot(ger, batchNumber), nil
+	})
+}
+
+// GetSequencerBatchStatus returns the status of the wip batch of the sequencer, it returns
+// null if the node is not the sequencer or there is no wip batch
+func (z *ZKEVMEndpoints) GetSequencerBatchStatus() (interface{}, types.Error) {
+	if z.cfg.SequencerNodeURI != "" {
+		return nil, nil
+	}
+
+	return z.txMan.NewDbTxScope(z.state, func(ctx context.Context, dbTx pgx.Tx) (interface{}, types.Error) {
+		batchNumber, err := z.state.GetLastBatchNumber(ctx, dbTx)
+		if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, "failed to get the last batch number from state", err, true)
+		}
+
+		batch, err := z.state.GetBatchByNumber(ctx, batchNumber, dbTx)
+		if errors.Is(err, state.ErrNotFound) {
+			return nil, nil
+		} else if err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load batch from state by number %v", batchNumber), err, true)
+		}
+		if !batch.WIP {
+			return nil, nil
+		}
+
+		txs, _, err := z.state.GetTransactionsByBatchNumber(ctx, batchNumber, dbTx)
+		if !errors.Is(err, state.ErrNotFound) && err != nil {
+			return RPCErrorResponse(types.DefaultErrorCode, fmt.Sprintf("couldn't load batch txs from state by number %v", batchNumber), err, true)
+		}
+
+		return types.NewSequencerBatchStatus(batch, len(txs), z.batchConstraints, time.Now()), nil
+	})
+}
+
+// SubscribeToVerifiedBatches creates a subscription that notifies each batch verified on L1.
+// A verification can verify several batches at once, in that case only the last one is notified
+func (z *ZKEVMEndpoints) SubscribeToVerifiedBatches(wsConn *concurrentWsConn) (interface{}, types.Error) {
+	if wsConn == nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "notifications not supported", nil, false)
+	}
+
+	id, err := z.storage.NewVerifiedBatchFilter(wsConn)
+	if err != nil {
+		return RPCErrorResponse(types.DefaultErrorCode, "failed to create new verified batch filter", err, true)
+	}
+
+	return id, nil
+}
+
+// onNewVerifiedBatch is triggered when the state triggers the event for a new verified batch
+func (z *ZKEVMEndpoints) onNewVerifiedBatch(event state.NewVerifiedBatchEvent) {
+	log.Debugf("[onNewVerifiedBatch] new verified batch event detected for batch %v", event.VerifiedBatch.BatchNumber)
+
+	data, err := json.Marshal(types.NewVerifiedBatchNotification(event.VerifiedBatch))
+	if err != nil {
+		log.Errorf("failed to marshal verified batch response to subscription: %v", err)
+		return
+	}
+
+	for _, filter := range z.storage.GetAllVerifiedBatchFiltersWithWSConn() {
+		filter.EnqueueSubscriptionDataToBeSent(data)
+	}
+}
@@ -7,18 +7,23 @@ import (
 	"fmt"
 	"math/big"
 	"strings"
+	"sync"
 	"testing"
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/l1infotree"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
+	"github.com/0xPolygonHermez/zkevm-node/pool"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/0xPolygonHermez/zkevm-node/test/operations"
 	"github.com/ethereum/go-ethereum/accounts/abi/bind"
 	"github.com/ethereum/go-ethereum/common"
 	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rpc"
 	"github.com/ethereum/go-ethereum/trie"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
@@ -657,6 +662,7 @@ func TestGetBatchByNumber(t *testing.T) {
 				Timestamp:           1,
 				SendSequencesTxHash: ptrHash(common.HexToHash("0x10")),
 				VerifyBatchTxHash:   ptrHash(common.HexToHash("0x20")),
+				ProofStatus:         types.BatchProofStatusVerified,
 			},
 			ExpectedError: nil,
 			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
@@ -784,6 +790,11 @@ func TestGetBatchByNumber(t *testing.T) {
 					On("GetL2BlocksByBatchNumber", context.Background(), hex.DecodeBig(tc.Number).Uint64(), m.DbTx).
 					Return(blocks, nil).
 					Once()
+
+				m.State.
+					On("GetForkIDByBatchNumber", hex.DecodeBig(tc.Number).Uint64()).
+					Return(uint64(state.FORKID_ETROG)).
+					Once()
 			},
 		},
 		{
@@ -799,6 +810,7 @@ func TestGetBatchByNumber(t *testing.T) {
 				Timestamp:           1,
 				SendSequencesTxHash: ptrHash(common.HexToHash("0x10")),
 				VerifyBatchTxHash:   ptrHash(common.HexToHash("0x20")),
+				ProofStatus:         types.BatchProofStatusVerified,
 			},
 			ExpectedError: nil,
 			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
@@ -908,6 +920,11 @@ func TestGetBatchByNumber(t *testing.T) {
 					Return(blocks, nil).
 					Once()
 
+				m.State.
+					On("GetForkIDByBatchNumber", hex.DecodeBig(tc.Number).Uint64()).
+					Return(uint64(state.FORKID_ETROG)).
+					Once()
+
 				tc.ExpectedResult.BatchL2Data = batchL2Data
 			},
 		},
@@ -916,15 +933,18 @@ func TestGetBatchByNumber(t *testing.T) {
 			Number:       "latest",
 			WithTxDetail: true,
 			ExpectedResult: &types.Batch{
-				Number:              1,
-				ForcedBatchNumber:   ptrArgUint64FromUint64(1),
-				Coinbase:            common.HexToAddress("0x1"),
-				StateRoot:           common.HexToHash("0x2"),
-				AccInputHash:        common.HexToHash("0x3"),
-				GlobalExitRoot:      common.HexToHash("0x4"),
-				Timestamp:           1,
-				SendSequencesTxHash: ptrHash(common.HexToHash("0x10")),
-				VerifyBatchTxHash:   ptrHash(common.HexToHash("0x20")),
+				Number:                   1,
+				ForcedBatchNumber:        ptrArgUint64FromUint64(1),
+				Coinbase:                 common.HexToAddress("0x1"),
+				StateRoot:                common.HexToHash("0x2"),
+				AccInputHash:             common.HexToHash("0x3"),
+				GlobalExitRoot:           common.HexToHash("0x4"),
+				Timestamp:                1,
+				L1BlockNumber:            ptrArgUint64FromUint64(100),
+				AverageEffectiveGasPrice: ptrArgBig(big.NewInt(1000000000)),
+				SendSequencesTxHash:      ptrHash(common.HexToHash("0x10")),
+				VerifyBatchTxHash:        ptrHash(common.HexToHash("0x20")),
+				ProofStatus:              types.BatchProofStatusVerified,
 			},
 			ExpectedError: nil,
 			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
@@ -1000,14 +1020,16 @@ func TestGetBatchByNumber(t *testing.T) {
 				require.NoError(t, err)
 				var fb uint64 = 1
 				batch := &state.Batch{
-					BatchNumber:    1,
-					ForcedBatchNum: &fb,
-					Coinbase:       common.HexToAddress("0x1"),
-					StateRoot:      common.HexToHash("0x2"),
-					AccInputHash:   common.HexToHash("0x3"),
-					GlobalExitRoot: common.HexToHash("0x4"),
-					Timestamp:      time.Unix(1, 0),
-					BatchL2Data:    batchL2Data,
+					BatchNumber:       1,
+					ForcedBatchNum:    &fb,
+					Coinbase:          common.HexToAddress("0x1"),
+					StateRoot:         common.HexToHash("0x2"),
+					AccInputHash:      common.HexToHash("0x3"),
+					GlobalExitRoot:    common.HexToHash("0x4"),
+					Timestamp:         time.Unix(1, 0),
+					BatchL2Data:       batchL2Data,
+					L1BlockNumber:     100,
+					EffectiveGasPrice: big.NewInt(1000000000),
 				}
 
 				m.State.
@@ -1062,6 +1084,11 @@ func TestGetBatchByNumber(t *testing.T) {
 					On("GetL2BlocksByBatchNumber", context.Background(), uint64(tc.ExpectedResult.Number), m.DbTx).
 					Return(blocks, nil).
 					Once()
+
+				m.State.
+					On("GetForkIDByBatchNumber", uint64(tc.ExpectedResult.Number)).
+					Return(uint64(state.FORKID_ETROG)).
+					Once()
 				tc.ExpectedResult.BatchL2Data = batchL2Data
 			},
 		},
@@ -1142,6 +1169,16 @@ func TestGetBatchByNumber(t *testing.T) {
 					if tc.ExpectedResult.ForcedBatchNumber != nil {
 						assert.Equal(t, tc.ExpectedResult.ForcedBatchNumber.Hex(), batch["forcedBatchNumber"].(string))
 					}
+					if tc.ExpectedResult.L1BlockNumber != nil {
+						assert.Equal(t, tc.ExpectedResult.L1BlockNumber.Hex(), batch["l1BlockNumber"].(string))
+					} else {
+						assert.NotContains(t, batch, "l1BlockNumber")
+					}
+					if tc.ExpectedResult.AverageEffectiveGasPrice != nil {
+						assert.Equal(t, tc.ExpectedResult.AverageEffectiveGasPrice.Hex(), batch["averageEffectiveGasPrice"].(string))
+					} else {
+						assert.NotContains(t, batch, "averageEffectiveGasPrice")
+					}
 					assert.Equal(t, tc.ExpectedResult.Coinbase.String(), batch["coinbase"].(string))
 					assert.Equal(t, tc.ExpectedResult.StateRoot.String(), batch["stateRoot"].(string))
 					assert.Equal(t, tc.ExpectedResult.GlobalExitRoot.String(), batch["globalExitRoot"].(string))
@@ -1150,6 +1187,7 @@ func TestGetBatchByNumber(t *testing.T) {
 					assert.Equal(t, tc.ExpectedResult.Timestamp.Hex(), batch["timestamp"].(string))
 					assert.Equal(t, tc.ExpectedResult.SendSequencesTxHash.String(), batch["sendSequencesTxHash"].(string))
 					assert.Equal(t, tc.ExpectedResult.VerifyBatchTxHash.String(), batch["verifyBatchTxHash"].(string))
+					assert.Equal(t, tc.ExpectedResult.ProofStatus, batch["proofStatus"].(string))
 					batchTxs := batch["transactions"].([]interface{})
 					for i, txOrHash := range tc.ExpectedResult.Transactions {
 						switch batchTxOrHash := batchTxs[i].(type) {
@@ -1188,6 +1226,42 @@ func TestGetBatchByNumber(t *testing.T) {
 	}
 }
 
+func TestGetBatchByNumberGERNotFound(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	batchNumber := uint64(1)
+	batch := &state.Batch{
+		BatchNumber:    batchNumber,
+		StateRoot:      common.HexToHash("0x2"),
+		GlobalExitRoot: common.HexToHash("0x4"),
+		Timestamp:      time.Unix(1, 0),
+	}
+
+	m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+	m.State.On("GetBatchByNumber", context.Background(), batchNumber, m.DbTx).Return(batch, nil).Once()
+	m.State.On("GetBatchTimestamp", context.Background(), batchNumber, (*uint64)(nil), m.DbTx).Return(&batch.Timestamp, nil).Once()
+	m.State.On("GetTransactionsByBatchNumber", context.Background(), batchNumber, m.DbTx).Return([]ethTypes.Transaction{}, []uint8{}, nil).Once()
+	m.State.On("GetVirtualBatch", context.Background(), batchNumber, m.DbTx).Return(nil, state.ErrNotFound).Once()
+	m.State.On("GetVerifiedBatch", context.Background(), batchNumber, m.DbTx).Return(nil, state.ErrNotFound).Once()
+	m.State.On("GetExitRootByGlobalExitRoot", context.Background(), batch.GlobalExitRoot, m.DbTx).Return(nil, state.ErrGERNotFound).Once()
+	m.State.On("GetL2BlocksByBatchNumber", context.Background(), batchNumber, m.DbTx).Return([]state.L2Block{}, nil).Once()
+	m.State.On("GetForkIDByBatchNumber", batchNumber).Return(uint64(state.FORKID_ETROG)).Once()
+
+	res, err := s.JSONRPCCall("zkevm_getBatchByNumber", hex.EncodeUint64(batchNumber), false)
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var result types.Batch
+	err = json.Unmarshal(res.Result, &result)
+	require.NoError(t, err)
+	assert.Equal(t, types.ArgUint64(batchNumber), result.Number)
+	assert.Equal(t, batch.GlobalExitRoot, result.GlobalExitRoot)
+	assert.Equal(t, common.Hash{}, result.MainnetExitRoot)
+	assert.Equal(t, common.Hash{}, result.RollupExitRoot)
+}
+
 func TestGetL2FullBlockByHash(t *testing.T) {
 	type testCase struct {
 		Name           string
@@ -1280,6 +1354,16 @@ func TestGetL2FullBlockByHash(t *testing.T) {
 						Return(ethTypes.NewReceipt([]byte{}, false, uint64(0)), nil).
 						Once()
 				}
+
+				m.State.
+					On("BatchNumberByL2BlockNumber", context.Background(), block.NumberU64(), m.DbTx).
+					Return(uint64(1), nil).
+					Once()
+
+				m.State.
+					On("GetForkIDByBatchNumber", uint64(1)).
+					Return(uint64(state.FORKID_ETROG)).
+					Once()
 			},
 		},
 	}
@@ -1320,6 +1404,72 @@ func TestGetL2FullBlockByHash(t *testing.T) {
 	}
 }
 
+func TestZKEVMGetL2BlockByHash(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	l2Header := state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1), UncleHash: ethTypes.EmptyUncleHash, Root: ethTypes.EmptyRootHash})
+	l2Header.GlobalExitRoot = common.HexToHash("0x1")
+	l2Header.BlockInfoRoot = common.HexToHash("0x2")
+	tx := ethTypes.NewTransaction(1, common.Address{}, big.NewInt(1), 1, big.NewInt(1), []byte{})
+	receipt := ethTypes.NewReceipt([]byte{}, false, uint64(0))
+	l2Block := state.NewL2Block(l2Header, []*ethTypes.Transaction{tx}, nil, []*ethTypes.Receipt{receipt}, &trie.StackTrie{})
+
+	m.DbTx.
+		On("Commit", context.Background()).
+		Return(nil).
+		Twice()
+
+	m.State.
+		On("BeginStateTransaction", context.Background()).
+		Return(m.DbTx, nil).
+		Twice()
+
+	m.State.
+		On("GetL2BlockByHash", context.Background(), l2Block.Hash(), m.DbTx).
+		Return(l2Block, nil).
+		Once()
+
+	m.State.
+		On("GetTransactionReceipt", context.Background(), tx.Hash(), m.DbTx).
+		Return(receipt, nil).
+		Once()
+
+	m.State.
+		On("BatchNumberByL2BlockNumber", context.Background(), l2Block.NumberU64(), m.DbTx).
+		Return(uint64(1), nil).
+		Once()
+
+	m.State.
+		On("GetForkIDByBatchNumber", uint64(1)).
+		Return(uint64(state.FORKID_ETROG)).
+		Once()
+
+	res, err := s.JSONRPCCall("zkevm_getL2BlockByHash", l2Block.Hash().String(), false)
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+
+	var result types.Block
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Equal(t, uint64(1), uint64(result.Number))
+	assert.Equal(t, ptr(l2Block.Hash()), result.Hash)
+	assert.Equal(t, l2Header.GlobalExitRoot, result.GlobalExitRoot)
+	assert.Equal(t, l2Header.BlockInfoRoot, result.BlockInfoRoot)
+	require.Len(t, result.Transactions, 1)
+	assert.Equal(t, ptr(tx.Hash()), result.Transactions[0].Hash)
+
+	notFoundHash := common.HexToHash("0x123")
+	m.State.
+		On("GetL2BlockByHash", context.Background(), notFoundHash, m.DbTx).
+		Return(nil, state.ErrNotFound).
+		Once()
+
+	res, err = s.JSONRPCCall("zkevm_getL2BlockByHash", notFoundHash.String(), false)
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.Equal(t, "null", string(res.Result))
+}
+
 func TestGetL2FullBlockByNumber(t *testing.T) {
 	type testCase struct {
 		Name           string
@@ -1518,6 +1668,16 @@ func TestGetL2FullBlockByNumber(t *testing.T) {
 						Return(receipt, nil).
 						Once()
 				}
+
+				m.State.
+					On("BatchNumberByL2BlockNumber", context.Background(), l2Block.NumberU64(), m.DbTx).
+					Return(uint64(1), nil).
+					Once()
+
+				m.State.
+					On("GetForkIDByBatchNumber", uint64(1)).
+					Return(uint64(state.FORKID_ETROG)).
+					Once()
 			},
 		},
 		{
@@ -1554,6 +1714,8 @@ func TestGetL2FullBlockByNumber(t *testing.T) {
 						Return(receipt, nil).
 						Once()
 				}
+
+				// the fork id of the block was cached by the previous case
 			},
 		},
 		{
@@ -1621,6 +1783,7 @@ func TestGetL2FullBlockByNumber(t *testing.T) {
 				tc.ExpectedResult.Sha3Uncles = ethTypes.EmptyUncleHash
 				tc.ExpectedResult.Size = 501
 				tc.ExpectedResult.ExtraData = []byte{}
+				tc.ExpectedResult.Nonce = types.ArgBytesPtr(make([]byte, 8))
 
 				m.DbTx.
 					On("Commit", context.Background()).
@@ -1889,10 +2052,10 @@ func TestGetExitRootsByGER(t *testing.T) {
 			Name:           "GER not found",
 			GER:            common.HexToHash("0x123"),
 			ExpectedResult: nil,
-			ExpectedError:  nil,
+			ExpectedError:  types.NewRPCError(types.DefaultErrorCode, "global exit root object not found"),
 			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
 				m.DbTx.
-					On("Commit", context.Background()).
+					On("Rollback", context.Background()).
 					Return(nil).
 					Once()
 
@@ -1903,17 +2066,18 @@ func TestGetExitRootsByGER(t *testing.T) {
 
 				m.State.
 					On("GetExitRootByGlobalExitRoot", context.Background(), tc.GER, m.DbTx).
-					Return(nil, state.ErrNotFound)
+					Return(nil, state.ErrGERNotFound).
+					Once()
 			},
 		},
 		{
 			Name:           "get exit roots fails to load exit roots from state",
 			GER:            common.HexToHash("0x123"),
 			ExpectedResult: nil,
-			ExpectedError:  nil,
+			ExpectedError:  types.NewRPCError(types.DefaultErrorCode, "failed to get exit roots by global exit root from state"),
 			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
 				m.DbTx.
-					On("Commit", context.Background()).
+					On("Rollback", context.Background()).
 					Return(nil).
 					Once()
 
@@ -1924,7 +2088,8 @@ func TestGetExitRootsByGER(t *testing.T) {
 
 				m.State.
 					On("GetExitRootByGlobalExitRoot", context.Background(), tc.GER, m.DbTx).
-					Return(nil, fmt.Errorf("failed to load exit roots from state"))
+					Return(nil, fmt.Errorf("failed to load exit roots from state")).
+					Once()
 			},
 		},
 		{
@@ -1953,7 +2118,8 @@ func TestGetExitRootsByGER(t *testing.T) {
 
 				m.State.
 					On("GetExitRootByGlobalExitRoot", context.Background(), tc.GER, m.DbTx).
-					Return(er, nil)
+					Return(er, nil).
+					Once()
 			},
 		},
 	}
@@ -1969,7 +2135,6 @@ func TestGetExitRootsByGER(t *testing.T) {
 			testCase.SetupMocks(s, m, &tc)
 
 			exitRoots, err := c.ExitRootsByGER(context.Background(), tc.GER)
-			require.NoError(t, err)
 
 			if exitRoots != nil || tc.ExpectedResult != nil {
 				assert.Equal(t, tc.ExpectedResult.MainnetExitRoot.String(), exitRoots.MainnetExitRoot.String())
@@ -1985,31 +2150,1734 @@ func TestGetExitRootsByGER(t *testing.T) {
 	}
 }
 
-func ptrUint64(n uint64) *uint64 {
-	return &n
-}
+func TestGetL2FullBlockMaxInlineReceiptTxs(t *testing.T) {
+	auth := operations.MustGetAuth(operations.DefaultSequencerPrivateKey, operations.DefaultL2ChainID)
+	var signedTransactions []*ethTypes.Transaction
+	for nonce := uint64(1); nonce <= 2; nonce++ {
+		tx := ethTypes.NewTx(&ethTypes.LegacyTx{Nonce: nonce, To: ptr(common.HexToAddress("0x1")), Gas: 21000, GasPrice: big.NewInt(1)})
+		signedTx, err := auth.Signer(auth.From, tx)
+		require.NoError(t, err)
+		signedTransactions = append(signedTransactions, signedTx)
+	}
+	l2Block := state.NewL2Block(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1)}), signedTransactions, nil, nil, &trie.StackTrie{})
 
-func ptrArgUint64FromUint(n uint) *types.ArgUint64 {
-	tmp := types.ArgUint64(n)
-	return &tmp
-}
+	cfg := getSequencerDefaultConfig()
+	cfg.MaxInlineReceiptTxs = 1
+	s, m, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
 
-func ptrArgUint64FromUint64(n uint64) *types.ArgUint64 {
-	tmp := types.ArgUint64(n)
-	return &tmp
+	expectedError := types.NewRPCError(types.InvalidParamsErrorCode, "blocks with receipts inline are limited to 1 transactions")
+
+	m.DbTx.
+		On("Rollback", context.Background()).
+		Return(nil).
+		Twice()
+
+	m.State.
+		On("BeginStateTransaction", context.Background()).
+		Return(m.DbTx, nil).
+		Twice()
+
+	m.State.
+		On("GetL2BlockByNumber", context.Background(), l2Block.NumberU64(), m.DbTx).
+		Return(l2Block, nil).
+		Once()
+
+	m.State.
+		On("GetL2BlockByHash", context.Background(), l2Block.Hash(), m.DbTx).
+		Return(l2Block, nil).
+		Once()
+
+	res, err := s.JSONRPCCall("zkevm_getFullBlockByNumber", hex.EncodeUint64(l2Block.NumberU64()), true)
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, expectedError.ErrorCode(), res.Error.Code)
+	assert.Equal(t, expectedError.Error(), res.Error.Message)
+
+	res, err = s.JSONRPCCall("zkevm_getFullBlockByHash", l2Block.Hash().String(), true)
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, expectedError.ErrorCode(), res.Error.Code)
+	assert.Equal(t, expectedError.Error(), res.Error.Message)
 }
 
-func ptrHash(h common.Hash) *common.Hash {
-	return &h
-}
+func TestGetForcedBatchesByRange(t *testing.T) {
+	forcedAt := time.Unix(1678980245, 0)
 
-func ptr[T any](v T) *T {
-	return &v
+	type testCase struct {
+		Name           string
+		FromL1Block    uint64
+		ToL1Block      uint64
+		ExpectedResult []types.ForcedBatch
+		ExpectedError  types.Error
+		SetupMocks     func(*mockedServer, *mocksWrapper, *testCase)
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "invalid block range",
+			FromL1Block:    10,
+			ToL1Block:      9,
+			ExpectedResult: nil,
+			ExpectedError:  types.NewRPCError(types.InvalidParamsErrorCode, "invalid block range"),
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Rollback", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+			},
+		},
+		{
+			Name:           "block range limit exceeded",
+			FromL1Block:    1,
+			ToL1Block:      10002,
+			ExpectedResult: nil,
+			ExpectedError:  types.NewRPCError(types.InvalidParamsErrorCode, "forced batches are limited to a 10000 L1 block range"),
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Rollback", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+			},
+		},
+		{
+			Name:           "failed to load forced batches from state",
+			FromL1Block:    1,
+			ToL1Block:      10,
+			ExpectedResult: nil,
+			ExpectedError:  types.NewRPCError(types.DefaultErrorCode, "failed to get forced batches from state"),
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Rollback", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetForcedBatchesByL1BlockRange", context.Background(), tc.FromL1Block, tc.ToL1Block, m.DbTx).
+					Return(nil, fmt.Errorf("failed to load forced batches from state")).
+					Once()
+			},
+		},
+		{
+			Name:           "no forced batches in range",
+			FromL1Block:    1,
+			ToL1Block:      10,
+			ExpectedResult: []types.ForcedBatch{},
+			ExpectedError:  nil,
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetForcedBatchesByL1BlockRange", context.Background(), tc.FromL1Block, tc.ToL1Block, m.DbTx).
+					Return([]state.ForcedBatch{}, nil).
+					Once()
+			},
+		},
+		{
+			Name:        "get forced batches successfully",
+			FromL1Block: 1,
+			ToL1Block:   10001,
+			ExpectedResult: []types.ForcedBatch{
+				{
+					ForcedBatchNumber: 1,
+					GlobalExitRoot:    common.HexToHash("0x1"),
+					RawTxData:         types.ArgBytes{0x0b},
+					Timestamp:         types.ArgUint64(forcedAt.Unix()),
+					L1BlockNumber:     2,
+					Coinbase:          common.HexToAddress("0x3"),
+				},
+				{
+					ForcedBatchNumber: 2,
+					GlobalExitRoot:    common.HexToHash("0x4"),
+					RawTxData:         types.ArgBytes{0x0b, 0x0c},
+					Timestamp:         types.ArgUint64(forcedAt.Unix()),
+					L1BlockNumber:     10001,
+					Coinbase:          common.HexToAddress("0x3"),
+				},
+			},
+			ExpectedError: nil,
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				forcedBatches := make([]state.ForcedBatch, 0, len(tc.ExpectedResult))
+				for _, fb := range tc.ExpectedResult {
+					forcedBatches = append(forcedBatches, state.ForcedBatch{
+						BlockNumber:       uint64(fb.L1BlockNumber),
+						ForcedBatchNumber: uint64(fb.ForcedBatchNumber),
+						Sequencer:         fb.Coinbase,
+						GlobalExitRoot:    fb.GlobalExitRoot,
+						RawTxsData:        fb.RawTxData,
+						ForcedAt:          forcedAt,
+					})
+				}
+
+				m.State.
+					On("GetForcedBatchesByL1BlockRange", context.Background(), tc.FromL1Block, tc.ToL1Block, m.DbTx).
+					Return(forcedBatches, nil).
+					Once()
+			},
+		},
+	}
+
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	c := client.NewClient(s.ServerURL)
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			testCase.SetupMocks(s, m, &tc)
+
+			forcedBatches, err := c.ForcedBatchesByRange(context.Background(), tc.FromL1Block, tc.ToL1Block)
+			assert.Equal(t, tc.ExpectedResult, forcedBatches)
+
+			if err != nil || tc.ExpectedError != nil {
+				rpcErr := err.(types.RPCError)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), rpcErr.ErrorCode())
+				assert.Equal(t, tc.ExpectedError.Error(), rpcErr.Error())
+			}
+		})
+	}
 }
 
-func signTx(tx *ethTypes.Transaction, chainID uint64) *ethTypes.Transaction {
-	privateKey, _ := crypto.GenerateKey()
-	auth, _ := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(0).SetUint64(chainID))
-	signedTx, _ := auth.Signer(auth.From, tx)
-	return signedTx
+func TestGetLatestGlobalExitRoot(t *testing.T) {
+	timestamp := time.Unix(1678980245, 0)
+
+	type testCase struct {
+		Name           string
+		ExpectedResult *types.GlobalExitRoot
+		ExpectedError  types.Error
+		SetupMocks     func(*mockedServer, *mocksWrapper, *testCase)
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "global exit root not found",
+			ExpectedResult: nil,
+			ExpectedError:  nil,
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetLastGlobalExitRoot", context.Background(), m.DbTx).
+					Return(nil, state.ErrNotFound).
+					Once()
+			},
+		},
+		{
+			Name:           "get latest global exit root fails to load last batch number from state",
+			ExpectedResult: nil,
+			ExpectedError:  types.NewRPCError(types.DefaultErrorCode, "failed to get the last batch number from state"),
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Rollback", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetLastGlobalExitRoot", context.Background(), m.DbTx).
+					Return(&state.GlobalExitRoot{GlobalExitRoot: common.HexToHash("0x1")}, nil).
+					Once()
+
+				m.State.
+					On("GetLastBatchNumber", context.Background(), m.DbTx).
+					Return(uint64(0), fmt.Errorf("failed to load last batch number")).
+					Once()
+			},
+		},
+		{
+			Name: "get latest global exit root successfully",
+			ExpectedResult: &types.GlobalExitRoot{
+				GlobalExitRoot:  common.HexToHash("0x1"),
+				MainnetExitRoot: common.HexToHash("0x2"),
+				RollupExitRoot:  common.HexToHash("0x3"),
+				Timestamp:       types.ArgUint64(timestamp.Unix()),
+				BatchNumber:     4,
+			},
+			ExpectedError: nil,
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetLastGlobalExitRoot", context.Background(), m.DbTx).
+					Return(&state.GlobalExitRoot{
+						BlockNumber:     5,
+						Timestamp:       timestamp,
+						MainnetExitRoot: tc.ExpectedResult.MainnetExitRoot,
+						RollupExitRoot:  tc.ExpectedResult.RollupExitRoot,
+						GlobalExitRoot:  tc.ExpectedResult.GlobalExitRoot,
+					}, nil).
+					Once()
+
+				m.State.
+					On("GetLastBatchNumber", context.Background(), m.DbTx).
+					Return(uint64(tc.ExpectedResult.BatchNumber), nil).
+					Once()
+			},
+		},
+	}
+
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	c := client.NewClient(s.ServerURL)
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			testCase.SetupMocks(s, m, &tc)
+
+			ger, err := c.LatestGlobalExitRoot(context.Background())
+			assert.Equal(t, tc.ExpectedResult, ger)
+
+			if err != nil || tc.ExpectedError != nil {
+				rpcErr := err.(types.RPCError)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), rpcErr.ErrorCode())
+				assert.Equal(t, tc.ExpectedError.Error(), rpcErr.Error())
+			}
+		})
+	}
+}
+
+func TestGetVirtualBatch(t *testing.T) {
+	sequencedAt := time.Unix(1678980245, 0)
+
+	type testCase struct {
+		Name           string
+		Number         uint64
+		ExpectedResult *types.VirtualBatch
+		ExpectedError  types.Error
+		SetupMocks     func(*mockedServer, *mocksWrapper, *testCase)
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "virtual batch not found",
+			Number:         1,
+			ExpectedResult: nil,
+			ExpectedError:  nil,
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetVirtualBatch", context.Background(), tc.Number, m.DbTx).
+					Return(nil, state.ErrNotFound).
+					Once()
+			},
+		},
+		{
+			Name:           "get virtual batch fails to load virtual batch from state",
+			Number:         2,
+			ExpectedResult: nil,
+			ExpectedError:  types.NewRPCError(types.DefaultErrorCode, "couldn't load virtual batch from state by number 2"),
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Rollback", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetVirtualBatch", context.Background(), tc.Number, m.DbTx).
+					Return(nil, fmt.Errorf("failed to load virtual batch from state")).
+					Once()
+			},
+		},
+		{
+			Name:   "get virtual batch successfully",
+			Number: 3,
+			ExpectedResult: &types.VirtualBatch{
+				BatchNumber:      3,
+				SequencerAddress: common.HexToAddress("0x1"),
+				SequencedTxHash:  common.HexToHash("0x2"),
+				SequencedAt:      types.ArgUint64Ptr(types.ArgUint64(sequencedAt.Unix())),
+				L1BlockNumber:    4,
+			},
+			ExpectedError: nil,
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetVirtualBatch", context.Background(), tc.Number, m.DbTx).
+					Return(&state.VirtualBatch{
+						BatchNumber:         tc.Number,
+						TxHash:              tc.ExpectedResult.SequencedTxHash,
+						Coinbase:            common.HexToAddress("0x5"),
+						SequencerAddr:       tc.ExpectedResult.SequencerAddress,
+						BlockNumber:         uint64(tc.ExpectedResult.L1BlockNumber),
+						TimestampBatchEtrog: &sequencedAt,
+					}, nil).
+					Once()
+			},
+		},
+		{
+			Name:   "get virtual batch sequenced before etrog",
+			Number: 4,
+			ExpectedResult: &types.VirtualBatch{
+				BatchNumber:      4,
+				SequencerAddress: common.HexToAddress("0x1"),
+				SequencedTxHash:  common.HexToHash("0x2"),
+				L1BlockNumber:    4,
+			},
+			ExpectedError: nil,
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetVirtualBatch", context.Background(), tc.Number, m.DbTx).
+					Return(&state.VirtualBatch{
+						BatchNumber:   tc.Number,
+						TxHash:        tc.ExpectedResult.SequencedTxHash,
+						SequencerAddr: tc.ExpectedResult.SequencerAddress,
+						BlockNumber:   uint64(tc.ExpectedResult.L1BlockNumber),
+					}, nil).
+					Once()
+			},
+		},
+	}
+
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	c := client.NewClient(s.ServerURL)
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			testCase.SetupMocks(s, m, &tc)
+
+			virtualBatch, err := c.VirtualBatch(context.Background(), tc.Number)
+			assert.Equal(t, tc.ExpectedResult, virtualBatch)
+
+			if err != nil || tc.ExpectedError != nil {
+				rpcErr := err.(types.RPCError)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), rpcErr.ErrorCode())
+				assert.Equal(t, tc.ExpectedError.Error(), rpcErr.Error())
+			}
+		})
+	}
+}
+
+func TestGetVerifiedBatch(t *testing.T) {
+	verifiedAt := time.Unix(1678980245, 0)
+
+	type testCase struct {
+		Name           string
+		Number         uint64
+		ExpectedResult *types.VerifiedBatch
+		ExpectedError  types.Error
+		SetupMocks     func(*mockedServer, *mocksWrapper, *testCase)
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "verified batch not found",
+			Number:         1,
+			ExpectedResult: nil,
+			ExpectedError:  nil,
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetVerifiedBatch", context.Background(), tc.Number, m.DbTx).
+					Return(nil, state.ErrNotFound).
+					Once()
+			},
+		},
+		{
+			Name:           "get verified batch fails to load verified batch from state",
+			Number:         2,
+			ExpectedResult: nil,
+			ExpectedError:  types.NewRPCError(types.DefaultErrorCode, "couldn't load verified batch from state by number 2"),
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Rollback", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetVerifiedBatch", context.Background(), tc.Number, m.DbTx).
+					Return(nil, fmt.Errorf("failed to load verified batch from state")).
+					Once()
+			},
+		},
+		{
+			Name:           "get verified batch fails to load L1 block from state",
+			Number:         3,
+			ExpectedResult: nil,
+			ExpectedError:  types.NewRPCError(types.DefaultErrorCode, "couldn't load L1 block 4 where the batch 3 was verified"),
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Rollback", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetVerifiedBatch", context.Background(), tc.Number, m.DbTx).
+					Return(&state.VerifiedBatch{BatchNumber: tc.Number, BlockNumber: 4}, nil).
+					Once()
+
+				m.State.
+					On("GetBlockByNumber", context.Background(), uint64(4), m.DbTx).
+					Return(nil, fmt.Errorf("failed to load L1 block from state")).
+					Once()
+			},
+		},
+		{
+			Name:   "get verified batch successfully",
+			Number: 4,
+			ExpectedResult: &types.VerifiedBatch{
+				BatchNumber:     4,
+				VerifierAddress: common.HexToAddress("0x1"),
+				ProofTxHash:     common.HexToHash("0x2"),
+				VerifiedAt:      types.ArgUint64(verifiedAt.Unix()),
+				L1BlockNumber:   5,
+				NewStateRoot:    common.HexToHash("0x3"),
+			},
+			ExpectedError: nil,
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetVerifiedBatch", context.Background(), tc.Number, m.DbTx).
+					Return(&state.VerifiedBatch{
+						BlockNumber: uint64(tc.ExpectedResult.L1BlockNumber),
+						BatchNumber: tc.Number,
+						Aggregator:  tc.ExpectedResult.VerifierAddress,
+						TxHash:      tc.ExpectedResult.ProofTxHash,
+						StateRoot:   tc.ExpectedResult.NewStateRoot,
+						IsTrusted:   true,
+					}, nil).
+					Once()
+
+				m.State.
+					On("GetBlockByNumber", context.Background(), uint64(tc.ExpectedResult.L1BlockNumber), m.DbTx).
+					Return(&state.Block{BlockNumber: uint64(tc.ExpectedResult.L1BlockNumber), ReceivedAt: verifiedAt}, nil).
+					Once()
+			},
+		},
+	}
+
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	c := client.NewClient(s.ServerURL)
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			testCase.SetupMocks(s, m, &tc)
+
+			verifiedBatch, err := c.VerifiedBatch(context.Background(), tc.Number)
+			assert.Equal(t, tc.ExpectedResult, verifiedBatch)
+
+			if err != nil || tc.ExpectedError != nil {
+				rpcErr := err.(types.RPCError)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), rpcErr.ErrorCode())
+				assert.Equal(t, tc.ExpectedError.Error(), rpcErr.Error())
+			}
+		})
+	}
+}
+
+func TestGetBatchStats(t *testing.T) {
+	type testCase struct {
+		Name           string
+		Number         uint64
+		ExpectedResult *types.BatchStats
+		ExpectedError  types.Error
+		SetupMocks     func(*mockedServer, *mocksWrapper, *testCase)
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "batch not found",
+			Number:         1,
+			ExpectedResult: nil,
+			ExpectedError:  nil,
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetBatchByNumber", context.Background(), tc.Number, m.DbTx).
+					Return(nil, state.ErrNotFound).
+					Once()
+			},
+		},
+		{
+			Name:           "get batch stats fails to load the gas used by the txs",
+			Number:         2,
+			ExpectedResult: nil,
+			ExpectedError:  types.NewRPCError(types.DefaultErrorCode, "couldn't load the gas used by the batch txs from state by number 2"),
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Rollback", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetBatchByNumber", context.Background(), tc.Number, m.DbTx).
+					Return(&state.Batch{BatchNumber: tc.Number}, nil).
+					Once()
+
+				m.State.
+					On("GetTxsGasUsedByBatchNumber", context.Background(), tc.Number, m.DbTx).
+					Return(nil, fmt.Errorf("failed to load the gas used")).
+					Once()
+			},
+		},
+		{
+			Name:   "get batch stats successfully",
+			Number: 3,
+			ExpectedResult: &types.BatchStats{
+				BatchNumber: 3,
+				NumberOfTxs: 2,
+				MinGas:      21000,
+				MaxGas:      60000,
+				AvgGas:      40500,
+				TotalGas:    81000,
+			},
+			ExpectedError: nil,
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetBatchByNumber", context.Background(), tc.Number, m.DbTx).
+					Return(&state.Batch{BatchNumber: tc.Number}, nil).
+					Once()
+
+				m.State.
+					On("GetTxsGasUsedByBatchNumber", context.Background(), tc.Number, m.DbTx).
+					Return([]uint64{21000, 60000}, nil).
+					Once()
+			},
+		},
+	}
+
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	c := client.NewClient(s.ServerURL)
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			testCase.SetupMocks(s, m, &tc)
+
+			batchStats, err := c.BatchStats(context.Background(), tc.Number)
+			assert.Equal(t, tc.ExpectedResult, batchStats)
+
+			if err != nil || tc.ExpectedError != nil {
+				rpcErr := err.(types.RPCError)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), rpcErr.ErrorCode())
+				assert.Equal(t, tc.ExpectedError.Error(), rpcErr.Error())
+			}
+		})
+	}
+}
+
+func TestGetBatchWitness(t *testing.T) {
+	batchTimestamp := time.Unix(1678980245, 0)
+	forcedBatchNum := uint64(1)
+	previousBatch := func(number uint64) *state.Batch {
+		return &state.Batch{BatchNumber: number - 1, StateRoot: common.HexToHash("0x9"), AccInputHash: common.HexToHash("0xa")}
+	}
+	forcedBlockHashL1 := common.HexToHash("0xb")
+
+	// L1 info tree with two leaves, the batch uses the second one
+	l1InfoTreeLeaves := []state.L1InfoTreeExitRootStorageEntry{
+		{L1InfoTreeLeaf: state.L1InfoTreeLeaf{GlobalExitRoot: state.GlobalExitRoot{GlobalExitRoot: common.HexToHash("0x6"), Timestamp: batchTimestamp}, PreviousBlockHash: common.HexToHash("0x7")}},
+		{L1InfoTreeLeaf: state.L1InfoTreeLeaf{GlobalExitRoot: state.GlobalExitRoot{GlobalExitRoot: common.HexToHash("0x3"), Timestamp: batchTimestamp}, PreviousBlockHash: common.HexToHash("0x4")}, L1InfoTreeIndex: 1},
+	}
+	tree, err := l1infotree.NewL1InfoTree(32, [][32]byte{})
+	require.NoError(t, err)
+	leavesHashes := [][32]byte{l1InfoTreeLeaves[0].Hash(), l1InfoTreeLeaves[1].Hash()}
+	proof, l1InfoRoot, err := tree.ComputeMerkleProof(1, leavesHashes)
+	require.NoError(t, err)
+	smtProof := make([]common.Hash, 0, len(proof))
+	for _, p := range proof {
+		smtProof = append(smtProof, p)
+	}
+
+	type testCase struct {
+		Name           string
+		Number         uint64
+		ExpectedResult *types.BatchWitness
+		ExpectedError  types.Error
+		SetupMocks     func(*mockedServer, *mocksWrapper, *testCase)
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "batch before etrog",
+			Number:         1,
+			ExpectedResult: nil,
+			ExpectedError:  types.NewRPCError(types.InvalidParamsErrorCode, "batch witness is not available for batches before fork id 7"),
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Rollback", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetForkIDByBatchNumber", tc.Number).
+					Return(uint64(state.FORKID_DRAGONFRUIT)).
+					Once()
+			},
+		},
+		{
+			Name:           "batch not found",
+			Number:         2,
+			ExpectedResult: nil,
+			ExpectedError:  nil,
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetForkIDByBatchNumber", tc.Number).
+					Return(uint64(state.FORKID_ETROG)).
+					Once()
+
+				m.State.
+					On("GetBatchByNumber", context.Background(), tc.Number, m.DbTx).
+					Return(nil, state.ErrNotFound).
+					Once()
+			},
+		},
+		{
+			Name:   "get initial batch witness successfully",
+			Number: 1,
+			ExpectedResult: &types.BatchWitness{
+				BatchL2Data:       types.ArgBytes{0x1},
+				OldStateRoot:      previousBatch(1).StateRoot,
+				OldAccInputHash:   previousBatch(1).AccInputHash,
+				L1InfoTreeData:    map[uint32]types.L1InfoTreeData{},
+				Coinbase:          common.HexToAddress("0x1"),
+				TimestampLimit:    types.ArgUint64(batchTimestamp.Unix()),
+				ChainID:           types.ArgUint64(chainID),
+				ForkID:            types.ArgUint64(state.FORKID_ETROG),
+				ForcedBlockHashL1: forcedBlockHashL1,
+			},
+			ExpectedError: nil,
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetForkIDByBatchNumber", tc.Number).
+					Return(uint64(state.FORKID_ETROG)).
+					Once()
+
+				m.State.
+					On("GetBatchByNumber", context.Background(), tc.Number, m.DbTx).
+					Return(&state.Batch{
+						BatchNumber: tc.Number,
+						BatchL2Data: tc.ExpectedResult.BatchL2Data,
+						Coinbase:    tc.ExpectedResult.Coinbase,
+						Timestamp:   batchTimestamp,
+					}, nil).
+					Once()
+
+				m.State.
+					On("GetBatchByNumber", context.Background(), tc.Number-1, m.DbTx).
+					Return(previousBatch(tc.Number), nil).
+					Once()
+
+				m.State.
+					On("GetVirtualBatchParentHash", context.Background(), tc.Number, m.DbTx).
+					Return(forcedBlockHashL1, nil).
+					Once()
+			},
+		},
+		{
+			Name:   "get forced batch witness successfully",
+			Number: 3,
+			ExpectedResult: &types.BatchWitness{
+				BatchL2Data:       types.ArgBytes{0x1},
+				OldStateRoot:      previousBatch(3).StateRoot,
+				OldAccInputHash:   previousBatch(3).AccInputHash,
+				L1InfoTreeData:    map[uint32]types.L1InfoTreeData{},
+				Coinbase:          common.HexToAddress("0x1"),
+				TimestampLimit:    types.ArgUint64(batchTimestamp.Unix()),
+				ChainID:           types.ArgUint64(chainID),
+				ForkID:            types.ArgUint64(state.FORKID_ETROG),
+				L1InfoRoot:        common.HexToHash("0x2"),
+				ForcedBlockHashL1: forcedBlockHashL1,
+			},
+			ExpectedError: nil,
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetForkIDByBatchNumber", tc.Number).
+					Return(uint64(state.FORKID_ETROG)).
+					Once()
+
+				m.State.
+					On("GetBatchByNumber", context.Background(), tc.Number, m.DbTx).
+					Return(&state.Batch{
+						BatchNumber:    tc.Number,
+						BatchL2Data:    tc.ExpectedResult.BatchL2Data,
+						Coinbase:       tc.ExpectedResult.Coinbase,
+						Timestamp:      batchTimestamp,
+						GlobalExitRoot: tc.ExpectedResult.L1InfoRoot,
+						ForcedBatchNum: &forcedBatchNum,
+					}, nil).
+					Once()
+
+				m.State.
+					On("GetBatchByNumber", context.Background(), tc.Number-1, m.DbTx).
+					Return(previousBatch(tc.Number), nil).
+					Once()
+
+				m.State.
+					On("GetForcedBatchParentHash", context.Background(), forcedBatchNum, m.DbTx).
+					Return(forcedBlockHashL1, nil).
+					Once()
+			},
+		},
+		{
+			Name:   "get batch witness successfully",
+			Number: 4,
+			ExpectedResult: &types.BatchWitness{
+				BatchL2Data:     types.ArgBytes{0x2},
+				OldStateRoot:    previousBatch(4).StateRoot,
+				OldAccInputHash: previousBatch(4).AccInputHash,
+				L1InfoTreeData: map[uint32]types.L1InfoTreeData{
+					1: {
+						GlobalExitRoot: common.HexToHash("0x3"),
+						BlockHashL1:    common.HexToHash("0x4"),
+						MinTimestamp:   types.ArgUint64(batchTimestamp.Unix()),
+						SmtProof:       smtProof,
+					},
+				},
+				Coinbase:       common.HexToAddress("0x1"),
+				TimestampLimit: types.ArgUint64(batchTimestamp.Unix()),
+				ChainID:        types.ArgUint64(chainID),
+				ForkID:         types.ArgUint64(state.FORKID_ETROG),
+				L1InfoRoot:     l1InfoRoot,
+			},
+			ExpectedError: nil,
+			SetupMocks: func(s *mockedServer, m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetForkIDByBatchNumber", tc.Number).
+					Return(uint64(state.FORKID_ETROG)).
+					Once()
+
+				m.State.
+					On("GetBatchByNumber", context.Background(), tc.Number, m.DbTx).
+					Return(&state.Batch{
+						BatchNumber: tc.Number,
+						BatchL2Data: tc.ExpectedResult.BatchL2Data,
+						Coinbase:    tc.ExpectedResult.Coinbase,
+						Timestamp:   batchTimestamp,
+					}, nil).
+					Once()
+
+				m.State.
+					On("GetBatchByNumber", context.Background(), tc.Number-1, m.DbTx).
+					Return(previousBatch(tc.Number), nil).
+					Once()
+
+				m.State.
+					On("GetLeafsByL1InfoRoot", context.Background(), l1InfoRoot, m.DbTx).
+					Return(l1InfoTreeLeaves, nil).
+					Once()
+
+				m.State.
+					On("GetL1InfoTreeDataFromBatchL2Data", context.Background(), []byte(tc.ExpectedResult.BatchL2Data), m.DbTx).
+					Return(map[uint32]state.L1DataV2{
+						1: {
+							GlobalExitRoot: common.HexToHash("0x3"),
+							BlockHashL1:    common.HexToHash("0x4"),
+							MinTimestamp:   uint64(batchTimestamp.Unix()),
+						},
+					}, l1InfoRoot, nil).
+					Once()
+			},
+		},
+	}
+
+	cfg := getSequencerDefaultConfig()
+	cfg.AdminAPIEnabled = true
+	s, m, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	c := client.NewClient(s.ServerURL)
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			testCase.SetupMocks(s, m, &tc)
+
+			batchWitness, err := c.BatchWitness(context.Background(), tc.Number)
+			assert.Equal(t, tc.ExpectedResult, batchWitness)
+
+			if err != nil || tc.ExpectedError != nil {
+				rpcErr := err.(types.RPCError)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), rpcErr.ErrorCode())
+				assert.Equal(t, tc.ExpectedError.Error(), rpcErr.Error())
+			}
+		})
+	}
+}
+
+func TestGetBatchWitnessAdminAPIDisabled(t *testing.T) {
+	s, _, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	c := client.NewClient(s.ServerURL)
+
+	batchWitness, err := c.BatchWitness(context.Background(), 1)
+	assert.Nil(t, batchWitness)
+	require.Error(t, err)
+	rpcErr := err.(types.RPCError)
+	assert.Equal(t, types.DefaultErrorCode, rpcErr.ErrorCode())
+	assert.Equal(t, "admin API is disabled", rpcErr.Error())
+}
+
+func TestGetBatchesByStateRoot(t *testing.T) {
+	stateRoot := common.HexToHash("0x5")
+	accInputHash := common.HexToHash("0x6")
+
+	type testCase struct {
+		Name           string
+		ExpectedResult []types.BatchByStateRoot
+		ExpectedError  types.Error
+		SetupMocks     func(m *mocksWrapper, tc *testCase)
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "no batches",
+			ExpectedResult: []types.BatchByStateRoot{},
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetBatchesByStateRoot", context.Background(), stateRoot, m.DbTx).
+					Return([]*state.Batch{}, nil).
+					Once()
+			},
+		},
+		{
+			Name: "state root and initial state root",
+			ExpectedResult: []types.BatchByStateRoot{
+				{Number: 3, StateRoot: stateRoot, AccInputHash: accInputHash, Closed: true, IsStateRoot: true},
+				{Number: 4, StateRoot: stateRoot, AccInputHash: accInputHash, Closed: true, IsStateRoot: true, IsInitialStateRoot: true},
+				{Number: 5, StateRoot: common.HexToHash("0x7"), AccInputHash: accInputHash, Closed: false, IsInitialStateRoot: true},
+			},
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetBatchesByStateRoot", context.Background(), stateRoot, m.DbTx).
+					Return([]*state.Batch{
+						{BatchNumber: 3, StateRoot: stateRoot, AccInputHash: accInputHash},
+						{BatchNumber: 4, StateRoot: stateRoot, AccInputHash: accInputHash},
+						{BatchNumber: 5, StateRoot: common.HexToHash("0x7"), AccInputHash: accInputHash, WIP: true},
+					}, nil).
+					Once()
+			},
+		},
+		{
+			Name:          "failed to get batches",
+			ExpectedError: types.NewRPCError(types.DefaultErrorCode, "couldn't load batches from state by state root "+stateRoot.String()),
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Rollback", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				m.State.
+					On("GetBatchesByStateRoot", context.Background(), stateRoot, m.DbTx).
+					Return(nil, errors.New("failed to get batches")).
+					Once()
+			},
+		},
+	}
+
+	cfg := getSequencerDefaultConfig()
+	cfg.AdminAPIEnabled = true
+	s, m, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	c := client.NewClient(s.ServerURL)
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			testCase.SetupMocks(m, &tc)
+
+			batches, err := c.BatchesByStateRoot(context.Background(), stateRoot)
+			assert.Equal(t, tc.ExpectedResult, batches)
+
+			if err != nil || tc.ExpectedError != nil {
+				rpcErr := err.(types.RPCError)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), rpcErr.ErrorCode())
+				assert.Equal(t, tc.ExpectedError.Error(), rpcErr.Error())
+			}
+		})
+	}
+}
+
+func TestGetBatchesByStateRootAdminAPIDisabled(t *testing.T) {
+	s, _, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	c := client.NewClient(s.ServerURL)
+
+	batches, err := c.BatchesByStateRoot(context.Background(), common.HexToHash("0x5"))
+	assert.Nil(t, batches)
+	require.Error(t, err)
+	rpcErr := err.(types.RPCError)
+	assert.Equal(t, types.DefaultErrorCode, rpcErr.ErrorCode())
+	assert.Equal(t, "admin API is disabled", rpcErr.Error())
+}
+
+func ptrUint64(n uint64) *uint64 {
+	return &n
+}
+
+func ptrArgUint64FromUint(n uint) *types.ArgUint64 {
+	tmp := types.ArgUint64(n)
+	return &tmp
+}
+
+func ptrArgUint64FromUint64(n uint64) *types.ArgUint64 {
+	tmp := types.ArgUint64(n)
+	return &tmp
+}
+
+func ptrArgBig(n *big.Int) *types.ArgBig {
+	tmp := types.ArgBig(*n)
+	return &tmp
+}
+
+func ptrHash(h common.Hash) *common.Hash {
+	return &h
+}
+
+func ptr[T any](v T) *T {
+	return &v
+}
+
+func signTx(tx *ethTypes.Transaction, chainID uint64) *ethTypes.Transaction {
+	privateKey, _ := crypto.GenerateKey()
+	auth, _ := bind.NewKeyedTransactorWithChainID(privateKey, big.NewInt(0).SetUint64(chainID))
+	signedTx, _ := auth.Signer(auth.From, tx)
+	return signedTx
+}
+
+func TestGetSequencerBatchStatus(t *testing.T) {
+	openedAt := time.Unix(1678980245, 0)
+
+	type testCase struct {
+		Name           string
+		ExpectedResult *types.SequencerBatchStatus
+		ExpectedError  types.Error
+		SetupMocks     func(m *mocksWrapper)
+	}
+
+	testCases := []testCase{
+		{
+			Name: "get sequencer batch status successfully",
+			ExpectedResult: &types.SequencerBatchStatus{
+				WIPBatchNumber: 5,
+				OpenedAt:       types.ArgUint64(openedAt.Unix()),
+				CountOfTxs:     1,
+				FillPercent:    types.BatchFillPercent{Bytes: 10, Gas: 50},
+			},
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastBatchNumber", context.Background(), m.DbTx).Return(uint64(5), nil).Once()
+				m.State.On("GetBatchByNumber", context.Background(), uint64(5), m.DbTx).Return(&state.Batch{
+					BatchNumber: 5,
+					Timestamp:   openedAt,
+					WIP:         true,
+					Resources:   state.BatchResources{Bytes: 100, ZKCounters: state.ZKCounters{GasUsed: 500}},
+				}, nil).Once()
+				tx := ethTypes.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), []byte{})
+				m.State.On("GetTransactionsByBatchNumber", context.Background(), uint64(5), m.DbTx).Return([]ethTypes.Transaction{*tx}, []uint8{255}, nil).Once()
+			},
+		},
+		{
+			Name:           "last batch is not wip",
+			ExpectedResult: nil,
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Commit", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastBatchNumber", context.Background(), m.DbTx).Return(uint64(5), nil).Once()
+				m.State.On("GetBatchByNumber", context.Background(), uint64(5), m.DbTx).Return(&state.Batch{BatchNumber: 5}, nil).Once()
+			},
+		},
+		{
+			Name:           "failed to get last batch number",
+			ExpectedResult: nil,
+			ExpectedError:  types.NewRPCError(types.DefaultErrorCode, "failed to get the last batch number from state"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Once()
+				m.State.On("GetLastBatchNumber", context.Background(), m.DbTx).Return(uint64(0), errors.New("failed to get last batch number")).Once()
+			},
+		},
+	}
+
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m)
+
+			res, err := s.JSONRPCCall("zkevm_getSequencerBatchStatus")
+			require.NoError(t, err)
+
+			var result *types.SequencerBatchStatus
+			if res.Result != nil {
+				err = json.Unmarshal(res.Result, &result)
+				require.NoError(t, err)
+			}
+			if result != nil {
+				// the estimation depends on the current time
+				require.NotNil(t, result.EstimatedCloseIn)
+				result.EstimatedCloseIn = nil
+			}
+			assert.Equal(t, tc.ExpectedResult, result)
+
+			if res.Error != nil || tc.ExpectedError != nil {
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+			}
+		})
+	}
+}
+
+func TestGetSequencerBatchStatusNonSequencer(t *testing.T) {
+	s, _, _ := newNonSequencerMockedServer(t, "http://localhost:9999")
+	defer s.Stop()
+
+	res, err := s.JSONRPCCall("zkevm_getSequencerBatchStatus")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.Equal(t, "null", string(res.Result))
+}
+
+func TestSubscribeToVerifiedBatches(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	type testCase struct {
+		Name           string
+		ExpectedResult string
+		ExpectedError  *types.RPCError
+		SetupMocks     func(m *mocksWrapper)
+	}
+
+	testCases := []testCase{
+		{
+			Name:           "Subscribe to verified batches successfully",
+			ExpectedResult: "0x1",
+			SetupMocks: func(m *mocksWrapper) {
+				m.Storage.
+					On("NewVerifiedBatchFilter", mock.IsType(&concurrentWsConn{})).
+					Return("0x1", nil).
+					Once()
+			},
+		},
+		{
+			Name:          "Subscribe fails to add filter to storage",
+			ExpectedError: types.NewRPCError(types.DefaultErrorCode, "failed to create new verified batch filter"),
+			SetupMocks: func(m *mocksWrapper) {
+				m.Storage.
+					On("NewVerifiedBatchFilter", mock.IsType(&concurrentWsConn{})).
+					Return("", fmt.Errorf("failed to add filter to storage")).
+					Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m)
+
+			c := s.GetWSClient()
+
+			var id string
+			err := c.Client().CallContext(context.Background(), &id, "zkevm_subscribeToVerifiedBatches")
+			if tc.ExpectedError != nil {
+				rpcErr := err.(rpc.Error)
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), rpcErr.ErrorCode())
+				assert.Equal(t, tc.ExpectedError.Error(), rpcErr.Error())
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tc.ExpectedResult, id)
+		})
+	}
+
+	t.Run("Subscribe over http is not supported", func(t *testing.T) {
+		res, err := s.JSONRPCCall("zkevm_subscribeToVerifiedBatches")
+		require.NoError(t, err)
+		require.NotNil(t, res.Error)
+		assert.Equal(t, types.DefaultErrorCode, res.Error.Code)
+		assert.Equal(t, "notifications not supported", res.Error.Message)
+	})
+}
+
+func TestOnNewVerifiedBatch(t *testing.T) {
+	storage := newStorageMock(t)
+	z := &ZKEVMEndpoints{storage: storage}
+
+	filter := &Filter{
+		ID:            "0x1",
+		Type:          FilterTypeVerifiedBatch,
+		wsQueue:       state.NewQueue[[]byte](),
+		wsQueueSignal: sync.NewCond(&sync.Mutex{}),
+	}
+	storage.On("GetAllVerifiedBatchFiltersWithWSConn").Return([]*Filter{filter}).Once()
+
+	z.onNewVerifiedBatch(state.NewVerifiedBatchEvent{
+		VerifiedBatch: state.VerifiedBatch{
+			BlockNumber: 100,
+			BatchNumber: 5,
+			TxHash:      common.HexToHash("0x1"),
+			StateRoot:   common.HexToHash("0x2"),
+		},
+	})
+
+	data, err := filter.wsQueue.Pop()
+	require.NoError(t, err)
+
+	var notification types.VerifiedBatchNotification
+	require.NoError(t, json.Unmarshal(data, &notification))
+	assert.Equal(t, types.VerifiedBatchNotification{
+		BatchNumber:   5,
+		VerifyTxHash:  common.HexToHash("0x1"),
+		NewStateRoot:  common.HexToHash("0x2"),
+		L1BlockNumber: 100,
+	}, notification)
+}
+
+func TestGetTransactionEffectiveGasPrice(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	txHash := common.HexToHash("0x1")
+	preEtrogTxHash := common.HexToHash("0x2")
+	pendingTxHash := common.HexToHash("0x3")
+
+	m.DbTx.
+		On("Commit", context.Background()).
+		Return(nil).
+		Times(3)
+
+	m.State.
+		On("BeginStateTransaction", context.Background()).
+		Return(m.DbTx, nil).
+		Times(3)
+
+	m.State.
+		On("GetTransactionEffectiveGasPrice", context.Background(), txHash, m.DbTx).
+		Return(big.NewInt(1000), nil).
+		Once()
+
+	m.State.
+		On("GetTransactionEffectiveGasPrice", context.Background(), preEtrogTxHash, m.DbTx).
+		Return(nil, nil).
+		Once()
+
+	m.State.
+		On("GetTransactionEffectiveGasPrice", context.Background(), pendingTxHash, m.DbTx).
+		Return(nil, state.ErrNotFound).
+		Once()
+
+	res, err := s.JSONRPCCall("zkevm_getTransactionEffectiveGasPrice", txHash.String())
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	var result types.TransactionEffectiveGasPrice
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Equal(t, txHash, result.TxHash)
+	assert.Equal(t, uint64(1000), (*big.Int)(&result.EffectiveGasPrice).Uint64())
+
+	for _, hash := range []common.Hash{preEtrogTxHash, pendingTxHash} {
+		res, err = s.JSONRPCCall("zkevm_getTransactionEffectiveGasPrice", hash.String())
+		require.NoError(t, err)
+		require.Nil(t, res.Error)
+		assert.Equal(t, "null", string(res.Result))
+	}
+}
+
+func TestGetGasStationPrice(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	m.Pool.
+		On("GetGasPrices", context.Background()).
+		Return(pool.GasPrices{L2GasPrice: 1500000000, L1GasPrice: 10000000000}, nil).
+		Once()
+	m.Pool.
+		On("GetDefaultMinGasPriceAllowed").
+		Return(uint64(1000000000)).
+		Once()
+
+	res, err := s.JSONRPCCall("zkevm_getGasStationPrice")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	var result types.GasStationPrice
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Equal(t, types.GasStationPrice{
+		MinGasPrice:       1000000000,
+		SuggestedGasPrice: 1500000000,
+		L1GasPrice:        10000000000,
+		L1GasPriceFactor:  testGasPriceCfg.Factor,
+	}, result)
+
+	m.Pool.
+		On("GetGasPrices", context.Background()).
+		Return(pool.GasPrices{}, errors.New("failed to get gas prices")).
+		Once()
+
+	res, err = s.JSONRPCCall("zkevm_getGasStationPrice")
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.DefaultErrorCode, res.Error.Code)
+	assert.Equal(t, "failed to get gas prices from pool", res.Error.Message)
+}
+
+func TestGetBlockInfoRoot(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	l2Header := state.NewL2Header(&ethTypes.Header{Number: big.NewInt(5)})
+	l2Header.GlobalExitRoot = common.HexToHash("0x1")
+	l2Header.BlockInfoRoot = common.HexToHash("0x2")
+
+	preEtrogL2Header := state.NewL2Header(&ethTypes.Header{Number: big.NewInt(3)})
+	preEtrogL2Header.GlobalExitRoot = common.HexToHash("0x3")
+	preEtrogL2Header.BlockInfoRoot = common.HexToHash("0x4")
+
+	m.DbTx.
+		On("Commit", context.Background()).
+		Return(nil).
+		Times(3)
+
+	m.State.
+		On("BeginStateTransaction", context.Background()).
+		Return(m.DbTx, nil).
+		Times(3)
+
+	m.State.
+		On("GetL2BlockHeaderByNumber", context.Background(), uint64(5), m.DbTx).
+		Return(l2Header, nil).
+		Once()
+	m.State.
+		On("BatchNumberByL2BlockNumber", context.Background(), uint64(5), m.DbTx).
+		Return(uint64(2), nil).
+		Once()
+	m.State.
+		On("GetForkIDByBatchNumber", uint64(2)).
+		Return(uint64(state.FORKID_ETROG)).
+		Once()
+
+	m.State.
+		On("GetL2BlockHeaderByNumber", context.Background(), uint64(3), m.DbTx).
+		Return(preEtrogL2Header, nil).
+		Once()
+	m.State.
+		On("BatchNumberByL2BlockNumber", context.Background(), uint64(3), m.DbTx).
+		Return(uint64(1), nil).
+		Once()
+	m.State.
+		On("GetForkIDByBatchNumber", uint64(1)).
+		Return(uint64(state.FORKID_INCABERRY)).
+		Once()
+
+	m.State.
+		On("GetL2BlockHeaderByNumber", context.Background(), uint64(6), m.DbTx).
+		Return(nil, state.ErrNotFound).
+		Once()
+
+	res, err := s.JSONRPCCall("zkevm_getBlockInfoRoot", "0x5")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	var result types.BlockInfoRoot
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Equal(t, types.BlockInfoRoot{
+		BlockNumber:    5,
+		BlockInfoRoot:  l2Header.BlockInfoRoot,
+		GlobalExitRoot: l2Header.GlobalExitRoot,
+	}, result)
+
+	// the roots of the blocks before etrog are zero
+	res, err = s.JSONRPCCall("zkevm_getBlockInfoRoot", "0x3")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Equal(t, types.BlockInfoRoot{BlockNumber: 3}, result)
+
+	res, err = s.JSONRPCCall("zkevm_getBlockInfoRoot", "0x6")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.Equal(t, "null", string(res.Result))
+}
+
+func TestGetTransactionByBatchNumberAndIndex(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	tx := signTx(ethTypes.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), []byte{}), s.ChainID())
+	receipt := ethTypes.NewReceipt([]byte{}, false, 21000)
+	receipt.TxHash = tx.Hash()
+	receipt.BlockNumber = big.NewInt(10)
+	receipt.BlockHash = common.HexToHash("0x10")
+	receipt.TransactionIndex = 1
+
+	m.DbTx.On("Commit", context.Background()).Return(nil).Twice()
+	m.DbTx.On("Rollback", context.Background()).Return(nil).Once()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Times(3)
+	m.State.On("GetTransactionByBatchNumberAndIndex", context.Background(), uint64(5), uint64(2), m.DbTx).Return(tx, nil).Once()
+	m.State.On("GetTransactionReceipt", context.Background(), tx.Hash(), m.DbTx).Return(receipt, nil).Once()
+	m.State.On("GetTransactionByBatchNumberAndIndex", context.Background(), uint64(5), uint64(3), m.DbTx).Return(nil, state.ErrNotFound).Once()
+	m.State.On("GetLastClosedBatchNumber", context.Background(), m.DbTx).Return(uint64(6), nil).Once()
+	m.State.On("GetTransactionByBatchNumberAndIndex", context.Background(), uint64(6), uint64(0), m.DbTx).Return(nil, errors.New("failed to get tx")).Once()
+
+	res, err := s.JSONRPCCall("zkevm_getTransactionByBatchNumberAndIndex", "0x5", "0x2")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	var result types.Transaction
+	require.NoError(t, json.Unmarshal(res.Result, &result))
+	assert.Equal(t, tx.Hash(), result.Hash)
+	assert.Equal(t, types.ArgUint64(10), *result.BlockNumber)
+	assert.Equal(t, receipt.BlockHash, *result.BlockHash)
+	assert.Equal(t, types.ArgUint64(1), *result.TxIndex)
+	require.NotNil(t, result.Receipt)
+	assert.Equal(t, tx.Hash(), result.Receipt.TxHash)
+
+	// the batch has no tx at the given position
+	res, err = s.JSONRPCCall("zkevm_getTransactionByBatchNumberAndIndex", "0x5", "0x3")
+	require.NoError(t, err)
+	require.Nil(t, res.Error)
+	assert.Equal(t, "null", string(res.Result))
+
+	res, err = s.JSONRPCCall("zkevm_getTransactionByBatchNumberAndIndex", "latest", "0x0")
+	require.NoError(t, err)
+	require.NotNil(t, res.Error)
+	assert.Equal(t, types.DefaultErrorCode, res.Error.Code)
+	assert.Equal(t, "couldn't load transaction 0 from state by batch number 6", res.Error.Message)
+}
+
+func TestGetProof(t *testing.T) {
+	s, m, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	codeHash := common.HexToHash("0x6d8c0e4d0b4d8b6f5c1d7f3e4a2b9c8d7e6f5a4b3c2d1e0f9a8b7c6d5e4f3a2b")
+	sibling := func(b byte) []byte { return common.BytesToHash([]byte{b}).Bytes() }
+	accountProof := &merkletree.AccountProof{
+		Balance:    &merkletree.LeafProof{Value: big.NewInt(1000), Siblings: [][]byte{sibling(1), sibling(2)}},
+		Nonce:      &merkletree.LeafProof{Value: big.NewInt(3), Siblings: [][]byte{sibling(3)}},
+		CodeHash:   &merkletree.LeafProof{Value: codeHash.Big(), Siblings: [][]byte{sibling(4)}},
+		CodeLength: &merkletree.LeafProof{Value: big.NewInt(42), Siblings: [][]byte{sibling(5)}},
+		Storage: []*merkletree.LeafProof{
+			{Value: big.NewInt(123), Siblings: [][]byte{sibling(6), sibling(7)}},
+		},
+	}
+
+	type testCase struct {
+		Name           string
+		Params         []interface{}
+		ExpectedResult *types.AccountProof
+		ExpectedError  *types.RPCError
+
+		SetupMocks func(m *mocksWrapper, tc *testCase)
+	}
+
+	testCases := []testCase{
+		{
+			Name: "failed to get proof",
+			Params: []interface{}{
+				addressArg.String(),
+				[]string{keyArg.String()},
+				hex.EncodeBig(blockNumOne),
+			},
+			ExpectedResult: nil,
+			ExpectedError:  types.NewRPCError(types.DefaultErrorCode, "failed to get proof from state"),
+
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Rollback", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				header := state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot})
+				m.State.On("GetL2BlockHeaderByNumber", context.Background(), blockNumOne.Uint64(), m.DbTx).Return(header, nil).Once()
+
+				m.State.
+					On("GetAccountProof", context.Background(), addressArg, []*big.Int{keyArg.Big()}, blockRoot).
+					Return(nil, errors.New("failed to get proof")).
+					Once()
+			},
+		},
+		{
+			Name: "get proof of an account with storage successfully",
+			Params: []interface{}{
+				addressArg.String(),
+				[]string{keyArg.String()},
+				hex.EncodeBig(blockNumOne),
+			},
+			ExpectedResult: &types.AccountProof{
+				Address:         addressArg,
+				Balance:         types.ArgBig(*big.NewInt(1000)),
+				Nonce:           3,
+				CodeHash:        codeHash,
+				CodeLength:      42,
+				BalanceProof:    []types.ArgBytes{sibling(1), sibling(2)},
+				NonceProof:      []types.ArgBytes{sibling(3)},
+				CodeHashProof:   []types.ArgBytes{sibling(4)},
+				CodeLengthProof: []types.ArgBytes{sibling(5)},
+				StorageProof: []types.StorageProof{
+					{Key: keyArg, Value: types.ArgBig(*big.NewInt(123)), Proof: []types.ArgBytes{sibling(6), sibling(7)}},
+				},
+			},
+			ExpectedError: nil,
+
+			SetupMocks: func(m *mocksWrapper, tc *testCase) {
+				m.DbTx.
+					On("Commit", context.Background()).
+					Return(nil).
+					Once()
+
+				m.State.
+					On("BeginStateTransaction", context.Background()).
+					Return(m.DbTx, nil).
+					Once()
+
+				header := state.NewL2Header(&ethTypes.Header{Number: blockNumOne, Root: blockRoot})
+				m.State.On("GetL2BlockHeaderByNumber", context.Background(), blockNumOne.Uint64(), m.DbTx).Return(header, nil).Once()
+
+				m.State.
+					On("GetAccountProof", context.Background(), addressArg, []*big.Int{keyArg.Big()}, blockRoot).
+					Return(accountProof, nil).
+					Once()
+			},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.Name, func(t *testing.T) {
+			tc := testCase
+			tc.SetupMocks(m, &tc)
+			res, err := s.JSONRPCCall("zkevm_getProof", tc.Params...)
+			require.NoError(t, err)
+			if tc.ExpectedResult != nil {
+				require.NotNil(t, res.Result)
+				require.Nil(t, res.Error)
+
+				var proof types.AccountProof
+				err = json.Unmarshal(res.Result, &proof)
+				require.NoError(t, err)
+				assert.Equal(t, *tc.ExpectedResult, proof)
+			}
+
+			if tc.ExpectedError != nil {
+				assert.Equal(t, tc.ExpectedError.ErrorCode(), res.Error.Code)
+				assert.Equal(t, tc.ExpectedError.Error(), res.Error.Message)
+			}
+		})
+	}
 }
@@ -0,0 +1,90 @@
+package jsonrpc
+
+import (
+	"context"
+	"math"
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/jackc/pgx/v4"
+)
+
+// l2BlockRange is an inclusive range of L2 block numbers
+type l2BlockRange struct {
+	from uint64
+	to   uint64
+}
+
+// l2BlockForkIDCache resolves the fork id of the L2 blocks. The fork id never decreases with the
+// L2 block number, so all the L2 blocks between two L2 blocks of the same fork id belong to it.
+// The cache keeps the range of L2 blocks resolved for each fork id, so the batch of a L2 block is
+// only queried when the L2 block is out of the known ranges
+type l2BlockForkIDCache struct {
+	mu     sync.RWMutex
+	ranges map[uint64]l2BlockRange
+}
+
+func newL2BlockForkIDCache() *l2BlockForkIDCache {
+	return &l2BlockForkIDCache{
+		ranges: make(map[uint64]l2BlockRange),
+	}
+}
+
+// get returns the fork id of the batch the L2 block belongs to
+func (c *l2BlockForkIDCache) get(ctx context.Context, st types.StateInterface, l2BlockNumber uint64, dbTx pgx.Tx) (uint64, error) {
+	if forkID, found := c.find(st, l2BlockNumber); found {
+		return forkID, nil
+	}
+
+	batchNumber, err := st.BatchNumberByL2BlockNumber(ctx, l2BlockNumber, dbTx)
+	if err != nil {
+		return 0, err
+	}
+	forkID := st.GetForkIDByBatchNumber(batchNumber)
+	c.add(forkID, l2BlockNumber)
+
+	return forkID, nil
+}
+
+func (c *l2BlockForkIDCache) find(st types.StateInterface, l2BlockNumber uint64) (uint64, bool) {
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+
+	var highestForkID uint64
+	highestForkIDFound := false
+	for forkID, r := range c.ranges {
+		if l2BlockNumber >= r.from && l2BlockNumber <= r.to {
+			return forkID, true
+		}
+		if !highestForkIDFound || forkID > highestForkID {
+			highestForkID = forkID
+			highestForkIDFound = true
+		}
+	}
+
+	// The L2 blocks after the last one resolved of the latest fork id belong to it too
+	if highestForkIDFound && l2BlockNumber > c.ranges[highestForkID].to &&
+		st.GetForkIDByBatchNumber(math.MaxUint64) == highestForkID {
+		return highestForkID, true
+	}
+
+	return 0, false
+}
+
+func (c *l2BlockForkIDCache) add(forkID uint64, l2BlockNumber uint64) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	r, found := c.ranges[forkID]
+	if !found {
+		c.ranges[forkID] = l2BlockRange{from: l2BlockNumber, to: l2BlockNumber}
+		return
+	}
+	if l2BlockNumber < r.from {
+		r.from = l2BlockNumber
+	}
+	if l2BlockNumber > r.to {
+		r.to = l2BlockNumber
+	}
+	c.ranges[forkID] = r
+}
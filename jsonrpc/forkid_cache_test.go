@@ -0,0 +1,58 @@
+package jsonrpc
+
+import (
+	"context"
+	"errors"
+	"math"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/mocks"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestL2BlockForkIDCache(t *testing.T) {
+	ctx := context.Background()
+	st := mocks.NewStateMock(t)
+	dbTx := mocks.NewDBTxMock(t)
+	c := newL2BlockForkIDCache()
+
+	// L2 blocks 10 and 20 are resolved querying their batches
+	st.On("BatchNumberByL2BlockNumber", ctx, uint64(10), dbTx).Return(uint64(5), nil).Once()
+	st.On("GetForkIDByBatchNumber", uint64(5)).Return(uint64(state.FORKID_INCABERRY)).Once()
+	forkID, err := c.get(ctx, st, 10, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(state.FORKID_INCABERRY), forkID)
+
+	st.On("GetForkIDByBatchNumber", uint64(math.MaxUint64)).Return(uint64(state.FORKID_ETROG)).Once()
+	st.On("BatchNumberByL2BlockNumber", ctx, uint64(20), dbTx).Return(uint64(8), nil).Once()
+	st.On("GetForkIDByBatchNumber", uint64(8)).Return(uint64(state.FORKID_INCABERRY)).Once()
+	forkID, err = c.get(ctx, st, 20, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(state.FORKID_INCABERRY), forkID)
+
+	// L2 blocks inside the known range don't query the state
+	forkID, err = c.get(ctx, st, 15, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(state.FORKID_INCABERRY), forkID)
+
+	// L2 blocks after the known range of a fork id that is not the latest one query their batch
+	st.On("GetForkIDByBatchNumber", uint64(math.MaxUint64)).Return(uint64(state.FORKID_ETROG)).Once()
+	st.On("BatchNumberByL2BlockNumber", ctx, uint64(30), dbTx).Return(uint64(12), nil).Once()
+	st.On("GetForkIDByBatchNumber", uint64(12)).Return(uint64(state.FORKID_ETROG)).Once()
+	forkID, err = c.get(ctx, st, 30, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(state.FORKID_ETROG), forkID)
+
+	// L2 blocks after the known range of the latest fork id belong to it
+	st.On("GetForkIDByBatchNumber", uint64(math.MaxUint64)).Return(uint64(state.FORKID_ETROG)).Once()
+	forkID, err = c.get(ctx, st, 40, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(state.FORKID_ETROG), forkID)
+
+	// L2 blocks between the known ranges query their batch
+	st.On("BatchNumberByL2BlockNumber", ctx, uint64(25), dbTx).Return(uint64(0), errors.New("failed to get batch number")).Once()
+	_, err = c.get(ctx, st, 25, dbTx)
+	require.Error(t, err)
+}
@@ -77,6 +77,12 @@ func (h *Handler) Handle(req handleRequest) types.Response {
 	log := log.WithFields("method", req.Method, "requestId", req.ID)
 	log.Debugf("request params %v", string(req.Params))
 
+	// Requests without a valid jsonrpc version are rejected before being dispatched,
+	// their id is not echoed back since the request can't be trusted to be well formed
+	if req.JSONRPC != types.JSONRPCVersion {
+		return types.NewResponse(types.Request{JSONRPC: types.JSONRPCVersion}, nil, types.NewRPCError(types.InvalidRequestErrorCode, "Invalid Request: missing jsonrpc version"))
+	}
+
 	service, fd, err := h.getFnHandler(req.Request)
 	if err != nil {
 		return types.NewResponse(req.Request, nil, err)
@@ -118,7 +124,7 @@ func (h *Handler) Handle(req handleRequest) types.Response {
 		inArgs[i+1] = val.Elem()
 	}
 
-	if fd.numParams() > 0 {
+	if len(inputs) > 0 {
 		if err := json.Unmarshal(req.Params, &inputs); err != nil {
 			return types.NewResponse(req.Request, nil, types.NewRPCError(types.InvalidParamsErrorCode, "Invalid Params"))
 		}
@@ -49,6 +49,26 @@ func (_m *storageMock) GetAllLogFiltersWithWSConn() []*Filter {
 	return r0
 }
 
+// GetAllVerifiedBatchFiltersWithWSConn provides a mock function with given fields:
+func (_m *storageMock) GetAllVerifiedBatchFiltersWithWSConn() []*Filter {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAllVerifiedBatchFiltersWithWSConn")
+	}
+
+	var r0 []*Filter
+	if rf, ok := ret.Get(0).(func() []*Filter); ok {
+		r0 = rf()
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*Filter)
+		}
+	}
+
+	return r0
+}
+
 // GetFilter provides a mock function with given fields: filterID
 func (_m *storageMock) GetFilter(filterID string) (*Filter, error) {
 	ret := _m.Called(filterID)
@@ -163,6 +183,34 @@ func (_m *storageMock) NewPendingTransactionFilter(wsConn *concurrentWsConn) (st
 	return r0, r1
 }
 
+// NewVerifiedBatchFilter provides a mock function with given fields: wsConn
+func (_m *storageMock) NewVerifiedBatchFilter(wsConn *concurrentWsConn) (string, error) {
+	ret := _m.Called(wsConn)
+
+	if len(ret) == 0 {
+		panic("no return value specified for NewVerifiedBatchFilter")
+	}
+
+	var r0 string
+	var r1 error
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn) (string, error)); ok {
+		return rf(wsConn)
+	}
+	if rf, ok := ret.Get(0).(func(*concurrentWsConn) string); ok {
+		r0 = rf(wsConn)
+	} else {
+		r0 = ret.Get(0).(string)
+	}
+
+	if rf, ok := ret.Get(1).(func(*concurrentWsConn) error); ok {
+		r1 = rf(wsConn)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // UninstallFilter provides a mock function with given fields: filterID
 func (_m *storageMock) UninstallFilter(filterID string) error {
 	ret := _m.Called(filterID)
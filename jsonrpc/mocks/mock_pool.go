@@ -67,6 +67,24 @@ func (_m *PoolMock) CountPendingTransactions(ctx context.Context) (uint64, error
 	return r0, r1
 }
 
+// GetDefaultMinGasPriceAllowed provides a mock function with given fields:
+func (_m *PoolMock) GetDefaultMinGasPriceAllowed() uint64 {
+	ret := _m.Called()
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetDefaultMinGasPriceAllowed")
+	}
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func() uint64); ok {
+		r0 = rf()
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	return r0
+}
+
 // GetGasPrices provides a mock function with given fields: ctx
 func (_m *PoolMock) GetGasPrices(ctx context.Context) (pool.GasPrices, error) {
 	ret := _m.Called(ctx)
@@ -10,6 +10,8 @@ import (
 
 	coretypes "github.com/ethereum/go-ethereum/core/types"
 
+	merkletree "github.com/0xPolygonHermez/zkevm-node/merkletree"
+
 	mock "github.com/stretchr/testify/mock"
 
 	pgx "github.com/jackc/pgx/v4"
@@ -151,6 +153,36 @@ func (_m *StateMock) EstimateGas(transaction *coretypes.Transaction, senderAddre
 	return r0, r1, r2
 }
 
+// GetAccountProof provides a mock function with given fields: ctx, address, storagePositions, root
+func (_m *StateMock) GetAccountProof(ctx context.Context, address common.Address, storagePositions []*big.Int, root common.Hash) (*merkletree.AccountProof, error) {
+	ret := _m.Called(ctx, address, storagePositions, root)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetAccountProof")
+	}
+
+	var r0 *merkletree.AccountProof
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, []*big.Int, common.Hash) (*merkletree.AccountProof, error)); ok {
+		return rf(ctx, address, storagePositions, root)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Address, []*big.Int, common.Hash) *merkletree.AccountProof); ok {
+		r0 = rf(ctx, address, storagePositions, root)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*merkletree.AccountProof)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Address, []*big.Int, common.Hash) error); ok {
+		r1 = rf(ctx, address, storagePositions, root)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetBalance provides a mock function with given fields: ctx, address, root
 func (_m *StateMock) GetBalance(ctx context.Context, address common.Address, root common.Hash) (*big.Int, error) {
 	ret := _m.Called(ctx, address, root)
@@ -241,6 +273,66 @@ func (_m *StateMock) GetBatchTimestamp(ctx context.Context, batchNumber uint64,
 	return r0, r1
 }
 
+// GetBatchesByStateRoot provides a mock function with given fields: ctx, stateRoot, dbTx
+func (_m *StateMock) GetBatchesByStateRoot(ctx context.Context, stateRoot common.Hash, dbTx pgx.Tx) ([]*state.Batch, error) {
+	ret := _m.Called(ctx, stateRoot, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBatchesByStateRoot")
+	}
+
+	var r0 []*state.Batch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pgx.Tx) ([]*state.Batch, error)); ok {
+		return rf(ctx, stateRoot, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pgx.Tx) []*state.Batch); ok {
+		r0 = rf(ctx, stateRoot, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]*state.Batch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Hash, pgx.Tx) error); ok {
+		r1 = rf(ctx, stateRoot, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetBlockByNumber provides a mock function with given fields: ctx, blockNumber, dbTx
+func (_m *StateMock) GetBlockByNumber(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (*state.Block, error) {
+	ret := _m.Called(ctx, blockNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetBlockByNumber")
+	}
+
+	var r0 *state.Block
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (*state.Block, error)); ok {
+		return rf(ctx, blockNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) *state.Block); ok {
+		r0 = rf(ctx, blockNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.Block)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, blockNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetCode provides a mock function with given fields: ctx, address, root
 func (_m *StateMock) GetCode(ctx context.Context, address common.Address, root common.Hash) ([]byte, error) {
 	ret := _m.Called(ctx, address, root)
@@ -301,6 +393,123 @@ func (_m *StateMock) GetExitRootByGlobalExitRoot(ctx context.Context, ger common
 	return r0, r1
 }
 
+// GetForcedBatchParentHash provides a mock function with given fields: ctx, forcedBatchNumber, dbTx
+func (_m *StateMock) GetForcedBatchParentHash(ctx context.Context, forcedBatchNumber uint64, dbTx pgx.Tx) (common.Hash, error) {
+	ret := _m.Called(ctx, forcedBatchNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForcedBatchParentHash")
+	}
+
+	var r0 common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (common.Hash, error)); ok {
+		return rf(ctx, forcedBatchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) common.Hash); ok {
+		r0 = rf(ctx, forcedBatchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(common.Hash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, forcedBatchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetForcedBatchesByL1BlockRange provides a mock function with given fields: ctx, fromBlock, toBlock, dbTx
+func (_m *StateMock) GetForcedBatchesByL1BlockRange(ctx context.Context, fromBlock uint64, toBlock uint64, dbTx pgx.Tx) ([]state.ForcedBatch, error) {
+	ret := _m.Called(ctx, fromBlock, toBlock, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForcedBatchesByL1BlockRange")
+	}
+
+	var r0 []state.ForcedBatch
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) ([]state.ForcedBatch, error)); ok {
+		return rf(ctx, fromBlock, toBlock, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) []state.ForcedBatch); ok {
+		r0 = rf(ctx, fromBlock, toBlock, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]state.ForcedBatch)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, fromBlock, toBlock, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
+// GetForkIDByBatchNumber provides a mock function with given fields: batchNumber
+func (_m *StateMock) GetForkIDByBatchNumber(batchNumber uint64) uint64 {
+	ret := _m.Called(batchNumber)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetForkIDByBatchNumber")
+	}
+
+	var r0 uint64
+	if rf, ok := ret.Get(0).(func(uint64) uint64); ok {
+		r0 = rf(batchNumber)
+	} else {
+		r0 = ret.Get(0).(uint64)
+	}
+
+	return r0
+}
+
+// GetL1InfoTreeDataFromBatchL2Data provides a mock function with given fields: ctx, batchL2Data, dbTx
+func (_m *StateMock) GetL1InfoTreeDataFromBatchL2Data(ctx context.Context, batchL2Data []byte, dbTx pgx.Tx) (map[uint32]state.L1DataV2, common.Hash, error) {
+	ret := _m.Called(ctx, batchL2Data, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetL1InfoTreeDataFromBatchL2Data")
+	}
+
+	var r0 map[uint32]state.L1DataV2
+	var r1 common.Hash
+	var r2 error
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, pgx.Tx) (map[uint32]state.L1DataV2, common.Hash, error)); ok {
+		return rf(ctx, batchL2Data, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, []byte, pgx.Tx) map[uint32]state.L1DataV2); ok {
+		r0 = rf(ctx, batchL2Data, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(map[uint32]state.L1DataV2)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, []byte, pgx.Tx) common.Hash); ok {
+		r1 = rf(ctx, batchL2Data, dbTx)
+	} else {
+		if ret.Get(1) != nil {
+			r1 = ret.Get(1).(common.Hash)
+		}
+	}
+
+	if rf, ok := ret.Get(2).(func(context.Context, []byte, pgx.Tx) error); ok {
+		r2 = rf(ctx, batchL2Data, dbTx)
+	} else {
+		r2 = ret.Error(2)
+	}
+
+	return r0, r1, r2
+}
+
 // GetL2BlockByHash provides a mock function with given fields: ctx, hash, dbTx
 func (_m *StateMock) GetL2BlockByHash(ctx context.Context, hash common.Hash, dbTx pgx.Tx) (*state.L2Block, error) {
 	ret := _m.Called(ctx, hash, dbTx)
@@ -591,6 +800,36 @@ func (_m *StateMock) GetLastConsolidatedL2BlockNumber(ctx context.Context, dbTx
 	return r0, r1
 }
 
+// GetLastGlobalExitRoot provides a mock function with given fields: ctx, dbTx
+func (_m *StateMock) GetLastGlobalExitRoot(ctx context.Context, dbTx pgx.Tx) (*state.GlobalExitRoot, error) {
+	ret := _m.Called(ctx, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLastGlobalExitRoot")
+	}
+
+	var r0 *state.GlobalExitRoot
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) (*state.GlobalExitRoot, error)); ok {
+		return rf(ctx, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, pgx.Tx) *state.GlobalExitRoot); ok {
+		r0 = rf(ctx, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*state.GlobalExitRoot)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, pgx.Tx) error); ok {
+		r1 = rf(ctx, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLastL2Block provides a mock function with given fields: ctx, dbTx
 func (_m *StateMock) GetLastL2Block(ctx context.Context, dbTx pgx.Tx) (*state.L2Block, error) {
 	ret := _m.Called(ctx, dbTx)
@@ -791,6 +1030,36 @@ func (_m *StateMock) GetLastVirtualizedL2BlockNumber(ctx context.Context, dbTx p
 	return r0, r1
 }
 
+// GetLeafsByL1InfoRoot provides a mock function with given fields: ctx, l1InfoRoot, dbTx
+func (_m *StateMock) GetLeafsByL1InfoRoot(ctx context.Context, l1InfoRoot common.Hash, dbTx pgx.Tx) ([]state.L1InfoTreeExitRootStorageEntry, error) {
+	ret := _m.Called(ctx, l1InfoRoot, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetLeafsByL1InfoRoot")
+	}
+
+	var r0 []state.L1InfoTreeExitRootStorageEntry
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pgx.Tx) ([]state.L1InfoTreeExitRootStorageEntry, error)); ok {
+		return rf(ctx, l1InfoRoot, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pgx.Tx) []state.L1InfoTreeExitRootStorageEntry); ok {
+		r0 = rf(ctx, l1InfoRoot, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]state.L1InfoTreeExitRootStorageEntry)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Hash, pgx.Tx) error); ok {
+		r1 = rf(ctx, l1InfoRoot, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetLogs provides a mock function with given fields: ctx, fromBlock, toBlock, addresses, topics, blockHash, since, dbTx
 func (_m *StateMock) GetLogs(ctx context.Context, fromBlock uint64, toBlock uint64, addresses []common.Address, topics [][]common.Hash, blockHash *common.Hash, since *time.Time, dbTx pgx.Tx) ([]*coretypes.Log, error) {
 	ret := _m.Called(ctx, fromBlock, toBlock, addresses, topics, blockHash, since, dbTx)
@@ -937,6 +1206,36 @@ func (_m *StateMock) GetSyncingInfo(ctx context.Context, dbTx pgx.Tx) (state.Syn
 	return r0, r1
 }
 
+// GetTransactionByBatchNumberAndIndex provides a mock function with given fields: ctx, batchNumber, index, dbTx
+func (_m *StateMock) GetTransactionByBatchNumberAndIndex(ctx context.Context, batchNumber uint64, index uint64, dbTx pgx.Tx) (*coretypes.Transaction, error) {
+	ret := _m.Called(ctx, batchNumber, index, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionByBatchNumberAndIndex")
+	}
+
+	var r0 *coretypes.Transaction
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) (*coretypes.Transaction, error)); ok {
+		return rf(ctx, batchNumber, index, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, uint64, pgx.Tx) *coretypes.Transaction); ok {
+		r0 = rf(ctx, batchNumber, index, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*coretypes.Transaction)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, index, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTransactionByHash provides a mock function with given fields: ctx, transactionHash, dbTx
 func (_m *StateMock) GetTransactionByHash(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*coretypes.Transaction, error) {
 	ret := _m.Called(ctx, transactionHash, dbTx)
@@ -1027,6 +1326,36 @@ func (_m *StateMock) GetTransactionByL2BlockNumberAndIndex(ctx context.Context,
 	return r0, r1
 }
 
+// GetTransactionEffectiveGasPrice provides a mock function with given fields: ctx, transactionHash, dbTx
+func (_m *StateMock) GetTransactionEffectiveGasPrice(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*big.Int, error) {
+	ret := _m.Called(ctx, transactionHash, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTransactionEffectiveGasPrice")
+	}
+
+	var r0 *big.Int
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pgx.Tx) (*big.Int, error)); ok {
+		return rf(ctx, transactionHash, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, common.Hash, pgx.Tx) *big.Int); ok {
+		r0 = rf(ctx, transactionHash, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(*big.Int)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, common.Hash, pgx.Tx) error); ok {
+		r1 = rf(ctx, transactionHash, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetTransactionReceipt provides a mock function with given fields: ctx, transactionHash, dbTx
 func (_m *StateMock) GetTransactionReceipt(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*coretypes.Receipt, error) {
 	ret := _m.Called(ctx, transactionHash, dbTx)
@@ -1096,6 +1425,36 @@ func (_m *StateMock) GetTransactionsByBatchNumber(ctx context.Context, batchNumb
 	return r0, r1, r2
 }
 
+// GetTxsGasUsedByBatchNumber provides a mock function with given fields: ctx, batchNumber, dbTx
+func (_m *StateMock) GetTxsGasUsedByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]uint64, error) {
+	ret := _m.Called(ctx, batchNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetTxsGasUsedByBatchNumber")
+	}
+
+	var r0 []uint64
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) ([]uint64, error)); ok {
+		return rf(ctx, batchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) []uint64); ok {
+		r0 = rf(ctx, batchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).([]uint64)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // GetVerifiedBatch provides a mock function with given fields: ctx, batchNumber, dbTx
 func (_m *StateMock) GetVerifiedBatch(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.VerifiedBatch, error) {
 	ret := _m.Called(ctx, batchNumber, dbTx)
@@ -1156,6 +1515,36 @@ func (_m *StateMock) GetVirtualBatch(ctx context.Context, batchNumber uint64, db
 	return r0, r1
 }
 
+// GetVirtualBatchParentHash provides a mock function with given fields: ctx, batchNumber, dbTx
+func (_m *StateMock) GetVirtualBatchParentHash(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (common.Hash, error) {
+	ret := _m.Called(ctx, batchNumber, dbTx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for GetVirtualBatchParentHash")
+	}
+
+	var r0 common.Hash
+	var r1 error
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) (common.Hash, error)); ok {
+		return rf(ctx, batchNumber, dbTx)
+	}
+	if rf, ok := ret.Get(0).(func(context.Context, uint64, pgx.Tx) common.Hash); ok {
+		r0 = rf(ctx, batchNumber, dbTx)
+	} else {
+		if ret.Get(0) != nil {
+			r0 = ret.Get(0).(common.Hash)
+		}
+	}
+
+	if rf, ok := ret.Get(1).(func(context.Context, uint64, pgx.Tx) error); ok {
+		r1 = rf(ctx, batchNumber, dbTx)
+	} else {
+		r1 = ret.Error(1)
+	}
+
+	return r0, r1
+}
+
 // IsL2BlockConsolidated provides a mock function with given fields: ctx, blockNumber, dbTx
 func (_m *StateMock) IsL2BlockConsolidated(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) (bool, error) {
 	ret := _m.Called(ctx, blockNumber, dbTx)
@@ -1247,11 +1636,21 @@ func (_m *StateMock) RegisterNewL2BlockEventHandler(h state.NewL2BlockEventHandl
 	_m.Called(h)
 }
 
+// RegisterNewVerifiedBatchEventHandler provides a mock function with given fields: h
+func (_m *StateMock) RegisterNewVerifiedBatchEventHandler(h state.NewVerifiedBatchEventHandler) {
+	_m.Called(h)
+}
+
 // StartToMonitorNewL2Blocks provides a mock function with given fields:
 func (_m *StateMock) StartToMonitorNewL2Blocks() {
 	_m.Called()
 }
 
+// StartToMonitorNewVerifiedBatches provides a mock function with given fields:
+func (_m *StateMock) StartToMonitorNewVerifiedBatches() {
+	_m.Called()
+}
+
 // NewStateMock creates a new instance of StateMock. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewStateMock(t interface {
@@ -23,6 +23,8 @@ const (
 	FilterTypeBlock = "block"
 	// FilterTypePendingTx represent a filter of type pending Tx.
 	FilterTypePendingTx = "pendingTx"
+	// FilterTypeVerifiedBatch represents a filter of type verified batch.
+	FilterTypeVerifiedBatch = "verifiedBatch"
 )
 
 // Filter represents a filter.
@@ -18,6 +18,8 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/didip/tollbooth/v6"
 	"github.com/gorilla/websocket"
+	"golang.org/x/net/http2"
+	"golang.org/x/net/http2/h2c"
 )
 
 const (
@@ -76,6 +78,7 @@ func NewServer(
 ) *Server {
 	if cfg.WebSockets.Enabled {
 		s.StartToMonitorNewL2Blocks()
+		s.StartToMonitorNewVerifiedBatches()
 	}
 
 	handler := newJSONRpcHandler()
@@ -122,12 +125,27 @@ func (s *Server) startHTTP() error {
 	lmt := tollbooth.NewLimiter(s.config.MaxRequestsPerIPAndSecond, nil)
 	mux.Handle("/", tollbooth.LimitFuncHandler(lmt, s.handle))
 
+	var handler http.Handler = mux
+	var h2s *http2.Server
+	if s.config.HTTP2Enabled {
+		// h2c serves HTTP/2 over cleartext connections and keeps serving HTTP/1.1 requests on the same port
+		h2s = &http2.Server{}
+		handler = h2c.NewHandler(mux, h2s)
+	}
+
 	s.srv = &http.Server{
-		Handler:           mux,
+		Handler:           handler,
 		ReadHeaderTimeout: s.config.ReadTimeout.Duration,
 		ReadTimeout:       s.config.ReadTimeout.Duration,
 		WriteTimeout:      s.config.WriteTimeout.Duration,
 	}
+	if h2s != nil {
+		// Registers the HTTP/2 connections to be gracefully closed when the server is shut down
+		if err := http2.ConfigureServer(s.srv, h2s); err != nil {
+			log.Errorf("failed to configure http2 server: %v", err)
+			return err
+		}
+	}
 	log.Infof("http server started: %s", address)
 	if err := s.srv.Serve(lis); err != nil {
 		if err == http.ErrServerClosed {
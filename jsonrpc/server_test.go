@@ -3,15 +3,19 @@ package jsonrpc
 import (
 	"bytes"
 	"context"
+	"crypto/tls"
 	"fmt"
 	"io"
+	"math"
 	"math/big"
+	"net"
 	"net/http"
 	"sync"
 	"sync/atomic"
 	"testing"
 	"time"
 
+	"github.com/0xPolygonHermez/zkevm-node/gasprice"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/client"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/mocks"
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
@@ -23,6 +27,7 @@ import (
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
+	"golang.org/x/net/http2"
 )
 
 const (
@@ -30,6 +35,18 @@ const (
 	chainID                   uint64 = 1000
 )
 
+var testBatchConstraints = state.BatchConstraintsCfg{
+	MaxBatchBytesSize:    1000,
+	MaxCumulativeGasUsed: 1000,
+	MaxSteps:             1000,
+}
+
+var testGasPriceCfg = gasprice.Config{
+	Type:               gasprice.FollowerType,
+	DefaultGasPriceWei: 1000000000,
+	Factor:             0.15,
+}
+
 type mockedServer struct {
 	Config              Config
 	Server              *Server
@@ -45,7 +62,7 @@ type mocksWrapper struct {
 	DbTx     *mocks.DBTxMock
 }
 
-func newMockedServer(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *ethclient.Client) {
+func newMockedServer(t testing.TB, cfg Config) (*mockedServer, *mocksWrapper, *ethclient.Client) {
 	pool := mocks.NewPoolMock(t)
 	st := mocks.NewStateMock(t)
 	etherman := mocks.NewEthermanMock(t)
@@ -63,6 +80,9 @@ func newMockedServer(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *e
 	var newL2BlockEventHandler state.NewL2BlockEventHandler = func(e state.NewL2BlockEvent) {}
 	st.On("RegisterNewL2BlockEventHandler", mock.IsType(newL2BlockEventHandler)).Once()
 	st.On("StartToMonitorNewL2Blocks").Once()
+	var newVerifiedBatchEventHandler state.NewVerifiedBatchEventHandler = func(e state.NewVerifiedBatchEvent) {}
+	st.On("RegisterNewVerifiedBatchEventHandler", mock.IsType(newVerifiedBatchEventHandler)).Once()
+	st.On("StartToMonitorNewVerifiedBatches").Once()
 
 	services := []Service{}
 	if _, ok := apis[APIEth]; ok {
@@ -82,7 +102,7 @@ func newMockedServer(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *e
 	if _, ok := apis[APIZKEVM]; ok {
 		services = append(services, Service{
 			Name:    APIZKEVM,
-			Service: NewZKEVMEndpoints(cfg, st, etherman),
+			Service: NewZKEVMEndpoints(cfg, chainID, pool, st, etherman, testBatchConstraints, testGasPriceCfg, storage),
 		})
 	}
 
@@ -159,6 +179,8 @@ func getSequencerDefaultConfig() Config {
 		MaxLogsCount:                 10000,
 		MaxLogsBlockRange:            10000,
 		MaxNativeBlockHashBlockRange: 60000,
+		MaxForcedBatchRangeSize:      10000,
+		MaxInlineReceiptTxs:          1000,
 		WebSockets: WebSocketsConfig{
 			Enabled:   true,
 			Host:      "0.0.0.0",
@@ -181,7 +203,7 @@ func newSequencerMockedServer(t *testing.T) (*mockedServer, *mocksWrapper, *ethc
 	return newMockedServer(t, cfg)
 }
 
-func newMockedServerWithCustomConfig(t *testing.T, cfg Config) (*mockedServer, *mocksWrapper, *ethclient.Client) {
+func newMockedServerWithCustomConfig(t testing.TB, cfg Config) (*mockedServer, *mocksWrapper, *ethclient.Client) {
 	return newMockedServer(t, cfg)
 }
 
@@ -265,6 +287,9 @@ func TestBatchRequests(t *testing.T) {
 				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Times(tc.NumberOfRequests)
 				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(block.Number().Uint64(), nil).Times(tc.NumberOfRequests)
 				m.State.On("GetL2BlockByNumber", context.Background(), block.Number().Uint64(), m.DbTx).Return(block, nil).Times(tc.NumberOfRequests)
+				// the fork id of the block is cached after the first request
+				m.State.On("BatchNumberByL2BlockNumber", context.Background(), block.Number().Uint64(), m.DbTx).Return(uint64(1), nil).Once()
+				m.State.On("GetForkIDByBatchNumber", uint64(1)).Return(uint64(state.FORKID_ETROG)).Once()
 				m.State.On("GetTransactionReceipt", context.Background(), mock.Anything, m.DbTx).Return(ethTypes.NewReceipt([]byte{}, false, uint64(0)), nil)
 			},
 		},
@@ -279,6 +304,9 @@ func TestBatchRequests(t *testing.T) {
 				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Times(tc.NumberOfRequests)
 				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(block.Number().Uint64(), nil).Times(tc.NumberOfRequests)
 				m.State.On("GetL2BlockByNumber", context.Background(), block.Number().Uint64(), m.DbTx).Return(block, nil).Times(tc.NumberOfRequests)
+				// the fork id of the block is cached after the first request
+				m.State.On("BatchNumberByL2BlockNumber", context.Background(), block.Number().Uint64(), m.DbTx).Return(uint64(1), nil).Once()
+				m.State.On("GetForkIDByBatchNumber", uint64(1)).Return(uint64(state.FORKID_ETROG)).Once()
 				m.State.On("GetTransactionReceipt", context.Background(), mock.Anything, m.DbTx).Return(ethTypes.NewReceipt([]byte{}, false, uint64(0)), nil)
 			},
 		},
@@ -293,6 +321,9 @@ func TestBatchRequests(t *testing.T) {
 				m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Times(tc.NumberOfRequests)
 				m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(block.Number().Uint64(), nil).Times(tc.NumberOfRequests)
 				m.State.On("GetL2BlockByNumber", context.Background(), block.Number().Uint64(), m.DbTx).Return(block, nil).Times(tc.NumberOfRequests)
+				// the fork id of the block is cached after the first request
+				m.State.On("BatchNumberByL2BlockNumber", context.Background(), block.Number().Uint64(), m.DbTx).Return(uint64(1), nil).Once()
+				m.State.On("GetForkIDByBatchNumber", uint64(1)).Return(uint64(state.FORKID_ETROG)).Once()
 				m.State.On("GetTransactionReceipt", context.Background(), mock.Anything, m.DbTx).Return(ethTypes.NewReceipt([]byte{}, false, uint64(0)), nil)
 			},
 		},
@@ -518,6 +549,39 @@ func TestRequestValidation(t *testing.T) {
 			},
 			ExpectedMessage: "invalid json array request body\n",
 		},
+		{
+			Name:               "Missing jsonrpc version",
+			Method:             http.MethodPost,
+			ContentType:        contentType,
+			Content:            []byte(`{"method":"eth_blockNumber","params":[],"id":1}`),
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponseHeaders: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+			ExpectedMessage: `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"Invalid Request: missing jsonrpc version"}}`,
+		},
+		{
+			Name:               "Invalid jsonrpc version",
+			Method:             http.MethodPost,
+			ContentType:        contentType,
+			Content:            []byte(`{"jsonrpc":"1.0","method":"eth_blockNumber","params":[],"id":1}`),
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponseHeaders: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+			ExpectedMessage: `{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"Invalid Request: missing jsonrpc version"}}`,
+		},
+		{
+			Name:               "Missing jsonrpc version in batch request",
+			Method:             http.MethodPost,
+			ContentType:        contentType,
+			Content:            []byte(`[{"method":"eth_blockNumber","params":[],"id":1}]`),
+			ExpectedStatusCode: http.StatusOK,
+			ExpectedResponseHeaders: map[string][]string{
+				"Content-Type": {"application/json"},
+			},
+			ExpectedMessage: `[{"jsonrpc":"2.0","id":null,"error":{"code":-32600,"message":"Invalid Request: missing jsonrpc version"}}]`,
+		},
 	}
 
 	s, _, _ := newSequencerMockedServer(t)
@@ -638,3 +702,114 @@ func TestMaxRequestPerIPPerSec(t *testing.T) {
 	// connection abruptly
 	time.Sleep(time.Second)
 }
+
+// newH2CClient returns a http client that talks HTTP/2 over cleartext connections
+func newH2CClient() *http.Client {
+	return &http.Client{
+		Transport: &http2.Transport{
+			AllowHTTP: true,
+			DialTLSContext: func(ctx context.Context, network, addr string, _ *tls.Config) (net.Conn, error) {
+				var d net.Dialer
+				return d.DialContext(ctx, network, addr)
+			},
+		},
+	}
+}
+
+func postBlockNumber(httpClient *http.Client, serverURL string) (*http.Response, error) {
+	body := []byte(`{"jsonrpc":"2.0","method":"eth_blockNumber","params":[],"id":1}`)
+	res, err := httpClient.Post(serverURL, contentType, bytes.NewReader(body))
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+
+	if _, err := io.Copy(io.Discard, res.Body); err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+	return res, nil
+}
+
+func TestHTTP2(t *testing.T) {
+	cfg := getSequencerDefaultConfig()
+	cfg.HTTP2Enabled = true
+	s, m, _ := newMockedServerWithCustomConfig(t, cfg)
+	defer s.Stop()
+
+	m.DbTx.On("Commit", context.Background()).Return(nil).Twice()
+	m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil).Twice()
+	m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(uint64(1), nil).Twice()
+
+	res, err := postBlockNumber(newH2CClient(), s.ServerURL)
+	require.NoError(t, err)
+	assert.Equal(t, 2, res.ProtoMajor)
+
+	// HTTP/1.1 requests are still served
+	res, err = postBlockNumber(http.DefaultClient, s.ServerURL)
+	require.NoError(t, err)
+	assert.Equal(t, 1, res.ProtoMajor)
+}
+
+func TestHTTP2Disabled(t *testing.T) {
+	s, _, _ := newSequencerMockedServer(t)
+	defer s.Stop()
+
+	_, err := postBlockNumber(newH2CClient(), s.ServerURL)
+	require.Error(t, err)
+}
+
+// BenchmarkConcurrentBlockNumber compares the throughput of the server under
+// concurrent eth_blockNumber calls served over HTTP/1.1 and over HTTP/2
+func BenchmarkConcurrentBlockNumber(b *testing.B) {
+	const concurrentCalls = 1000
+
+	benchmarks := []struct {
+		name         string
+		http2Enabled bool
+		httpClient   *http.Client
+	}{
+		{
+			name:       "HTTP/1.1",
+			httpClient: &http.Client{Transport: &http.Transport{MaxIdleConnsPerHost: concurrentCalls}},
+		},
+		{
+			name:         "HTTP/2",
+			http2Enabled: true,
+			httpClient:   newH2CClient(),
+		},
+	}
+
+	for _, bm := range benchmarks {
+		b.Run(bm.name, func(b *testing.B) {
+			cfg := getSequencerDefaultConfig()
+			cfg.MaxRequestsPerIPAndSecond = math.MaxFloat64
+			cfg.HTTP2Enabled = bm.http2Enabled
+			s, m, _ := newMockedServerWithCustomConfig(b, cfg)
+			defer s.Stop()
+
+			m.DbTx.On("Commit", context.Background()).Return(nil)
+			m.State.On("BeginStateTransaction", context.Background()).Return(m.DbTx, nil)
+			m.State.On("GetLastL2BlockNumber", context.Background(), m.DbTx).Return(uint64(1), nil)
+
+			b.ResetTimer()
+			for i := 0; i < b.N; i++ {
+				wg := sync.WaitGroup{}
+				wg.Add(concurrentCalls)
+				for j := 0; j < concurrentCalls; j++ {
+					go func() {
+						defer wg.Done()
+						if _, err := postBlockNumber(bm.httpClient, s.ServerURL); err != nil {
+							b.Error(err)
+						}
+					}()
+				}
+				wg.Wait()
+			}
+			b.StopTimer()
+			bm.httpClient.CloseIdleConnections()
+		})
+	}
+}
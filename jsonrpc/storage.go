@@ -25,23 +25,28 @@ type Storage struct {
 	blockFiltersWithWSConn     map[string]*Filter
 	logFiltersWithWSConn       map[string]*Filter
 	pendingTxFiltersWithWSConn map[string]*Filter
+	// verifiedBatchFiltersWithWSConn are the subscriptions to zkevm_subscribeToVerifiedBatches
+	verifiedBatchFiltersWithWSConn map[string]*Filter
 
-	blockMutex     *sync.Mutex
-	logMutex       *sync.Mutex
-	pendingTxMutex *sync.Mutex
+	blockMutex         *sync.Mutex
+	logMutex           *sync.Mutex
+	pendingTxMutex     *sync.Mutex
+	verifiedBatchMutex *sync.Mutex
 }
 
 // NewStorage creates and initializes an instance of Storage
 func NewStorage() *Storage {
 	return &Storage{
-		allFilters:                 make(map[string]*Filter),
-		allFiltersWithWSConn:       make(map[*concurrentWsConn]map[string]*Filter),
-		blockFiltersWithWSConn:     make(map[string]*Filter),
-		logFiltersWithWSConn:       make(map[string]*Filter),
-		pendingTxFiltersWithWSConn: make(map[string]*Filter),
-		blockMutex:                 &sync.Mutex{},
-		logMutex:                   &sync.Mutex{},
-		pendingTxMutex:             &sync.Mutex{},
+		allFilters:                     make(map[string]*Filter),
+		allFiltersWithWSConn:           make(map[*concurrentWsConn]map[string]*Filter),
+		blockFiltersWithWSConn:         make(map[string]*Filter),
+		logFiltersWithWSConn:           make(map[string]*Filter),
+		pendingTxFiltersWithWSConn:     make(map[string]*Filter),
+		verifiedBatchFiltersWithWSConn: make(map[string]*Filter),
+		blockMutex:                     &sync.Mutex{},
+		logMutex:                       &sync.Mutex{},
+		pendingTxMutex:                 &sync.Mutex{},
+		verifiedBatchMutex:             &sync.Mutex{},
 	}
 }
 
@@ -64,6 +69,11 @@ func (s *Storage) NewPendingTransactionFilter(wsConn *concurrentWsConn) (string,
 	return s.createFilter(FilterTypePendingTx, nil, wsConn)
 }
 
+// NewVerifiedBatchFilter persists a new verified batch filter
+func (s *Storage) NewVerifiedBatchFilter(wsConn *concurrentWsConn) (string, error) {
+	return s.createFilter(FilterTypeVerifiedBatch, nil, wsConn)
+}
+
 // create persists the filter to the memory and provides the filter id
 func (s *Storage) createFilter(t FilterType, parameters interface{}, wsConn *concurrentWsConn) (string, error) {
 	lastPoll := time.Now().UTC()
@@ -75,9 +85,11 @@ func (s *Storage) createFilter(t FilterType, parameters interface{}, wsConn *con
 	s.blockMutex.Lock()
 	s.logMutex.Lock()
 	s.pendingTxMutex.Lock()
+	s.verifiedBatchMutex.Lock()
 	defer s.blockMutex.Unlock()
 	defer s.logMutex.Unlock()
 	defer s.pendingTxMutex.Unlock()
+	defer s.verifiedBatchMutex.Unlock()
 
 	f := &Filter{
 		ID:            id,
@@ -104,6 +116,8 @@ func (s *Storage) createFilter(t FilterType, parameters interface{}, wsConn *con
 			s.logFiltersWithWSConn[id] = f
 		} else if t == FilterTypePendingTx {
 			s.pendingTxFiltersWithWSConn[id] = f
+		} else if t == FilterTypeVerifiedBatch {
+			s.verifiedBatchFiltersWithWSConn[id] = f
 		}
 	}
 	return id, nil
@@ -152,14 +166,30 @@ func (s *Storage) GetAllLogFiltersWithWSConn() []*Filter {
 	return filters
 }
 
+// GetAllVerifiedBatchFiltersWithWSConn returns an array with all filter that have
+// a web socket connection and are filtering by new verified batches
+func (s *Storage) GetAllVerifiedBatchFiltersWithWSConn() []*Filter {
+	s.verifiedBatchMutex.Lock()
+	defer s.verifiedBatchMutex.Unlock()
+
+	filters := []*Filter{}
+	for _, filter := range s.verifiedBatchFiltersWithWSConn {
+		f := filter
+		filters = append(filters, f)
+	}
+	return filters
+}
+
 // GetFilter gets a filter by its id
 func (s *Storage) GetFilter(filterID string) (*Filter, error) {
 	s.blockMutex.Lock()
 	s.logMutex.Lock()
 	s.pendingTxMutex.Lock()
+	s.verifiedBatchMutex.Lock()
 	defer s.blockMutex.Unlock()
 	defer s.logMutex.Unlock()
 	defer s.pendingTxMutex.Unlock()
+	defer s.verifiedBatchMutex.Unlock()
 
 	filter, found := s.allFilters[filterID]
 	if !found {
@@ -174,9 +204,11 @@ func (s *Storage) UpdateFilterLastPoll(filterID string) error {
 	s.blockMutex.Lock()
 	s.logMutex.Lock()
 	s.pendingTxMutex.Lock()
+	s.verifiedBatchMutex.Lock()
 	defer s.blockMutex.Unlock()
 	defer s.logMutex.Unlock()
 	defer s.pendingTxMutex.Unlock()
+	defer s.verifiedBatchMutex.Unlock()
 
 	filter, found := s.allFilters[filterID]
 	if !found {
@@ -192,9 +224,11 @@ func (s *Storage) UninstallFilter(filterID string) error {
 	s.blockMutex.Lock()
 	s.logMutex.Lock()
 	s.pendingTxMutex.Lock()
+	s.verifiedBatchMutex.Lock()
 	defer s.blockMutex.Unlock()
 	defer s.logMutex.Unlock()
 	defer s.pendingTxMutex.Unlock()
+	defer s.verifiedBatchMutex.Unlock()
 
 	filter, found := s.allFilters[filterID]
 	if !found {
@@ -210,9 +244,11 @@ func (s *Storage) UninstallFilterByWSConn(wsConn *concurrentWsConn) error {
 	s.blockMutex.Lock()
 	s.logMutex.Lock()
 	s.pendingTxMutex.Lock()
+	s.verifiedBatchMutex.Lock()
 	defer s.blockMutex.Unlock()
 	defer s.logMutex.Unlock()
 	defer s.pendingTxMutex.Unlock()
+	defer s.verifiedBatchMutex.Unlock()
 
 	filters, found := s.allFiltersWithWSConn[wsConn]
 	if !found {
@@ -234,6 +270,8 @@ func (s *Storage) deleteFilter(filter *Filter) {
 		delete(s.logFiltersWithWSConn, filter.ID)
 	} else if filter.Type == FilterTypePendingTx {
 		delete(s.pendingTxFiltersWithWSConn, filter.ID)
+	} else if filter.Type == FilterTypeVerifiedBatch {
+		delete(s.verifiedBatchFiltersWithWSConn, filter.ID)
 	}
 
 	if filter.WsConn != nil {
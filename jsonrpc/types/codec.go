@@ -57,6 +57,9 @@ const (
 	RequireCanonicalKey = "requireCanonical"
 )
 
+// JSONRPCVersion is the version of the JSON-RPC protocol the requests must declare
+const JSONRPCVersion = "2.0"
+
 // Request is a jsonrpc request
 type Request struct {
 	JSONRPC string          `json:"jsonrpc"`
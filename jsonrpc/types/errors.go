@@ -25,6 +25,25 @@ var (
 	// ErrBatchRequestsLimitExceeded returned by the server when a batch request
 	// is detected and the number of requests are greater than the configured limit.
 	ErrBatchRequestsLimitExceeded = fmt.Errorf("batch requests limit exceeded")
+
+	// ErrInvalidExitRoots returned when the mainnet or rollup exit root
+	// related to a global exit root is the zero hash
+	ErrInvalidExitRoots = fmt.Errorf("invalid exit roots")
+
+	// ErrMalformedL2Block returned when a L2 block can't be converted to
+	// its RPC representation because it has no header
+	ErrMalformedL2Block = fmt.Errorf("malformed L2 block")
+
+	// ErrEmptyHash returned when a hash argument has no hexadecimal digits
+	ErrEmptyHash = fmt.Errorf("invalid hash, it can't be empty")
+
+	// ErrRemovedL2Log returned when a L2 log is flagged as removed, which
+	// can't happen in L2 because there are no uncle blocks
+	ErrRemovedL2Log = fmt.Errorf("L2 log can't be removed")
+
+	// ErrInvalidStoragePosition returned when a storage position is longer
+	// than the 32 bytes of a storage slot
+	ErrInvalidStoragePosition = fmt.Errorf("invalid storage position, it can't be longer than 32 bytes")
 )
 
 // Error interface
@@ -7,8 +7,11 @@ import (
 	"math/big"
 	"strconv"
 	"strings"
+	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/merkletree"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -43,6 +46,16 @@ func (b ArgUint64) Hex() string {
 	return string(bb)
 }
 
+// String returns the decimal representation, as block numbers are shown in logs
+func (b ArgUint64) String() string {
+	return strconv.FormatUint(uint64(b), 10) //nolint:gomnd
+}
+
+// GoString returns the hexadecimal representation, used by the %#v format
+func (b ArgUint64) GoString() string {
+	return b.Hex()
+}
+
 // ArgUint64Ptr returns the pointer of the provided ArgUint64
 func ArgUint64Ptr(a ArgUint64) *ArgUint64 {
 	return &a
@@ -111,6 +124,16 @@ func (b ArgBig) Hex() string {
 	return string(bb)
 }
 
+// String returns the decimal representation
+func (b ArgBig) String() string {
+	return (*big.Int)(&b).String()
+}
+
+// GoString returns the hexadecimal representation, used by the %#v format
+func (b ArgBig) GoString() string {
+	return "0x" + (*big.Int)(&b).Text(hex.Base)
+}
+
 func decodeToHex(b []byte) ([]byte, error) {
 	str := string(b)
 	str = strings.TrimPrefix(str, "0x")
@@ -132,13 +155,21 @@ func encodeToHex(b []byte) []byte {
 // shorter than 64 bytes, like 0x00
 type ArgHash common.Hash
 
-// UnmarshalText unmarshals from text
+// UnmarshalText unmarshals from text. The 0x prefix is optional, an empty
+// value returns ErrEmptyHash and values shorter than 64 hex characters
+// are left padded with zeros.
 func (arg *ArgHash) UnmarshalText(input []byte) error {
 	if !hex.IsValid(string(input)) {
 		return fmt.Errorf("invalid hash, it needs to be a hexadecimal value")
 	}
 
 	str := strings.TrimPrefix(string(input), "0x")
+	if len(str) == 0 {
+		return ErrEmptyHash
+	}
+	if len(str) < 2*common.HashLength {
+		log.Debugf("hash %s is shorter than %d bytes, padding it with leading zeros", string(input), common.HashLength)
+	}
 	*arg = ArgHash(common.HexToHash(str))
 	return nil
 }
@@ -152,6 +183,23 @@ func (arg *ArgHash) Hash() common.Hash {
 	return result
 }
 
+// DecodeStoragePosition decodes a storage position, any hexadecimal value up to 32 bytes is
+// accepted and it's left padded with zeros to 32 bytes
+func DecodeStoragePosition(input string) (common.Hash, error) {
+	if !hex.IsValid(input) {
+		return common.Hash{}, fmt.Errorf("invalid storage position, it needs to be a hexadecimal value")
+	}
+
+	str := strings.TrimPrefix(input, "0x")
+	if len(str) == 0 {
+		return common.Hash{}, ErrEmptyHash
+	}
+	if len(str) > 2*common.HashLength {
+		return common.Hash{}, ErrInvalidStoragePosition
+	}
+	return common.HexToHash(str), nil
+}
+
 // ArgAddress represents a common.Address that accepts strings
 // shorter than 32 bytes, like 0x00
 type ArgAddress common.Address
@@ -194,11 +242,16 @@ func (args *TxArgs) ToTransaction(ctx context.Context, st StateInterface, maxCum
 	nonce := uint64(0)
 	if args.From != nil && *args.From != state.ZeroAddress {
 		sender = *args.From
-		n, err := st.GetNonce(ctx, sender, root)
-		if err != nil {
-			return common.Address{}, nil, err
+		if args.Nonce == nil {
+			n, err := st.GetNonce(ctx, sender, root)
+			if err != nil {
+				return common.Address{}, nil, err
+			}
+			nonce = n
 		}
-		nonce = n
+	}
+	if args.Nonce != nil {
+		nonce = uint64(*args.Nonce)
 	}
 
 	value := big.NewInt(0)
@@ -259,13 +312,28 @@ type Block struct {
 	Hash            *common.Hash        `json:"hash"`
 	Transactions    []TransactionOrHash `json:"transactions"`
 	Uncles          []common.Hash       `json:"uncles"`
+	Withdrawals     []Withdrawal        `json:"withdrawals"`
 	GlobalExitRoot  common.Hash         `json:"globalExitRoot"`
 	BlockInfoRoot   common.Hash         `json:"blockInfoRoot"`
 }
 
-// NewBlock creates a Block instance
-func NewBlock(hash *common.Hash, b *state.L2Block, receipts []types.Receipt, fullTx, includeReceipts bool) (*Block, error) {
+// Withdrawal represents a validator withdrawal from the consensus layer (EIP-4895).
+// L2 blocks don't have withdrawals, it's included to be compatible with the clients
+// that expect the withdrawals field in post-Shanghai blocks
+type Withdrawal struct {
+	Index     ArgUint64      `json:"index"`
+	Validator ArgUint64      `json:"validatorIndex"`
+	Address   common.Address `json:"address"`
+	Amount    ArgUint64      `json:"amount"`
+}
+
+// NewBlock creates a Block instance. The global exit root and the block info root aren't part of
+// the L2 blocks before the etrog fork, so they are returned as zero for them
+func NewBlock(hash *common.Hash, b *state.L2Block, receipts []types.Receipt, fullTx, includeReceipts bool, forkID uint64) (*Block, error) {
 	h := b.Header()
+	if h == nil {
+		return nil, ErrMalformedL2Block
+	}
 
 	var miner *common.Address
 	if h.Coinbase.String() != state.ZeroAddress.String() {
@@ -273,13 +341,8 @@ func NewBlock(hash *common.Hash, b *state.L2Block, receipts []types.Receipt, ful
 		miner = &cb
 	}
 
-	var nonce *ArgBytes
-	if h.Nonce.Uint64() > 0 {
-		nBig := big.NewInt(0).SetUint64(h.Nonce.Uint64())
-		nBytes := common.LeftPadBytes(nBig.Bytes(), 8) //nolint:gomnd
-		n := ArgBytes(nBytes)
-		nonce = &n
-	}
+	nonceBytes := ArgBytes(h.Nonce[:])
+	nonce := &nonceBytes
 
 	difficulty := ArgUint64(0)
 	var totalDifficulty *ArgUint64
@@ -309,8 +372,11 @@ func NewBlock(hash *common.Hash, b *state.L2Block, receipts []types.Receipt, ful
 		Hash:            hash,
 		Transactions:    []TransactionOrHash{},
 		Uncles:          []common.Hash{},
-		GlobalExitRoot:  h.GlobalExitRoot,
-		BlockInfoRoot:   h.BlockInfoRoot,
+		Withdrawals:     []Withdrawal{},
+	}
+	if forkID >= state.FORKID_ETROG {
+		res.GlobalExitRoot = h.GlobalExitRoot
+		res.BlockInfoRoot = h.BlockInfoRoot
 	}
 
 	receiptsMap := make(map[common.Hash]types.Receipt, len(receipts))
@@ -351,40 +417,72 @@ func NewBlock(hash *common.Hash, b *state.L2Block, receipts []types.Receipt, ful
 
 // Batch structure
 type Batch struct {
-	Number              ArgUint64           `json:"number"`
-	ForcedBatchNumber   *ArgUint64          `json:"forcedBatchNumber,omitempty"`
-	Coinbase            common.Address      `json:"coinbase"`
-	StateRoot           common.Hash         `json:"stateRoot"`
-	GlobalExitRoot      common.Hash         `json:"globalExitRoot"`
-	MainnetExitRoot     common.Hash         `json:"mainnetExitRoot"`
-	RollupExitRoot      common.Hash         `json:"rollupExitRoot"`
-	LocalExitRoot       common.Hash         `json:"localExitRoot"`
-	AccInputHash        common.Hash         `json:"accInputHash"`
-	Timestamp           ArgUint64           `json:"timestamp"`
-	SendSequencesTxHash *common.Hash        `json:"sendSequencesTxHash"`
-	VerifyBatchTxHash   *common.Hash        `json:"verifyBatchTxHash"`
-	Closed              bool                `json:"closed"`
-	Blocks              []BlockOrHash       `json:"blocks"`
-	Transactions        []TransactionOrHash `json:"transactions"`
-	BatchL2Data         ArgBytes            `json:"batchL2Data"`
-}
-
-// NewBatch creates a Batch instance
-func NewBatch(batch *state.Batch, virtualBatch *state.VirtualBatch, verifiedBatch *state.VerifiedBatch, blocks []state.L2Block, receipts []types.Receipt, fullTx, includeReceipts bool, ger *state.GlobalExitRoot) (*Batch, error) {
-	batchL2Data := batch.BatchL2Data
+	Number                   ArgUint64           `json:"number"`
+	ForcedBatchNumber        *ArgUint64          `json:"forcedBatchNumber,omitempty"`
+	Coinbase                 common.Address      `json:"coinbase"`
+	StateRoot                common.Hash         `json:"stateRoot"`
+	GlobalExitRoot           common.Hash         `json:"globalExitRoot"`
+	MainnetExitRoot          common.Hash         `json:"mainnetExitRoot"`
+	RollupExitRoot           common.Hash         `json:"rollupExitRoot"`
+	LocalExitRoot            common.Hash         `json:"localExitRoot"`
+	AccInputHash             common.Hash         `json:"accInputHash"`
+	Timestamp                ArgUint64           `json:"timestamp"`
+	L1BlockNumber            *ArgUint64          `json:"l1BlockNumber,omitempty"`
+	AverageEffectiveGasPrice *ArgBig             `json:"averageEffectiveGasPrice,omitempty"`
+	SendSequencesTxHash      *common.Hash        `json:"sendSequencesTxHash"`
+	VerifyBatchTxHash        *common.Hash        `json:"verifyBatchTxHash"`
+	ProofStatus              string              `json:"proofStatus"`
+	Closed                   bool                `json:"closed"`
+	Blocks                   []BlockOrHash       `json:"blocks"`
+	Transactions             []TransactionOrHash `json:"transactions"`
+	BatchL2Data              ArgBytes            `json:"batchL2Data"`
+	BatchL2DataTruncated     bool                `json:"batchL2DataTruncated,omitempty"`
+}
+
+const (
+	// BatchProofStatusPending indicates the batch has not been sequenced to L1 yet
+	BatchProofStatusPending = "pending"
+	// BatchProofStatusVirtual indicates the batch has been sequenced to L1 but not verified yet
+	BatchProofStatusVirtual = "virtual"
+	// BatchProofStatusVerified indicates the batch has been verified on L1
+	BatchProofStatusVerified = "verified"
+)
+
+// NewBatch creates a Batch instance. When includeL2Data is false the
+// BatchL2Data is omitted and the batch is flagged as truncated. The forkID
+// is the fork of the batch, used to build its blocks. If the exit roots of
+// the batch are invalid the batch is returned along with an ErrInvalidExitRoots
+// error, so the caller can surface it as a warning.
+func NewBatch(batch *state.Batch, virtualBatch *state.VirtualBatch, verifiedBatch *state.VerifiedBatch, blocks []state.L2Block, receipts []types.Receipt, fullTx, includeReceipts, includeL2Data bool, ger *state.GlobalExitRoot, forkID uint64) (*Batch, error) {
+	// The exit roots are only checked when the batch has a global exit root, otherwise
+	// it's expected they are zero
+	var exitRootsErr error
+	if batch.GlobalExitRoot != (common.Hash{}) {
+		exitRoots := ExitRoots{MainnetExitRoot: ger.MainnetExitRoot, RollupExitRoot: ger.RollupExitRoot}
+		if err := exitRoots.Validate(); err != nil {
+			exitRootsErr = fmt.Errorf("batch %d, global exit root %s: %w", batch.BatchNumber, batch.GlobalExitRoot.String(), err)
+		}
+	}
+
+	var batchL2Data []byte
+	if includeL2Data {
+		batchL2Data = batch.BatchL2Data
+	}
 	closed := !batch.WIP
 	res := &Batch{
-		Number:          ArgUint64(batch.BatchNumber),
-		GlobalExitRoot:  batch.GlobalExitRoot,
-		MainnetExitRoot: ger.MainnetExitRoot,
-		RollupExitRoot:  ger.RollupExitRoot,
-		AccInputHash:    batch.AccInputHash,
-		Timestamp:       ArgUint64(batch.Timestamp.Unix()),
-		StateRoot:       batch.StateRoot,
-		Coinbase:        batch.Coinbase,
-		LocalExitRoot:   batch.LocalExitRoot,
-		BatchL2Data:     ArgBytes(batchL2Data),
-		Closed:          closed,
+		Number:               ArgUint64(batch.BatchNumber),
+		GlobalExitRoot:       batch.GlobalExitRoot,
+		MainnetExitRoot:      ger.MainnetExitRoot,
+		RollupExitRoot:       ger.RollupExitRoot,
+		AccInputHash:         batch.AccInputHash,
+		Timestamp:            ArgUint64(batch.Timestamp.Unix()),
+		StateRoot:            batch.StateRoot,
+		Coinbase:             batch.Coinbase,
+		LocalExitRoot:        batch.LocalExitRoot,
+		BatchL2Data:          ArgBytes(batchL2Data),
+		BatchL2DataTruncated: !includeL2Data,
+		Closed:               closed,
+		ProofStatus:          BatchProofStatusPending,
 	}
 
 	if batch.ForcedBatchNum != nil {
@@ -392,12 +490,24 @@ func NewBatch(batch *state.Batch, virtualBatch *state.VirtualBatch, verifiedBatc
 		res.ForcedBatchNumber = &fb
 	}
 
+	if batch.L1BlockNumber != 0 {
+		l1BlockNumber := ArgUint64(batch.L1BlockNumber)
+		res.L1BlockNumber = &l1BlockNumber
+	}
+
+	if batch.EffectiveGasPrice != nil {
+		averageEffectiveGasPrice := ArgBig(*batch.EffectiveGasPrice)
+		res.AverageEffectiveGasPrice = &averageEffectiveGasPrice
+	}
+
 	if virtualBatch != nil {
 		res.SendSequencesTxHash = &virtualBatch.TxHash
+		res.ProofStatus = BatchProofStatusVirtual
 	}
 
 	if verifiedBatch != nil {
 		res.VerifyBatchTxHash = &verifiedBatch.TxHash
+		res.ProofStatus = BatchProofStatusVerified
 	}
 
 	receiptsMap := make(map[common.Hash]types.Receipt, len(receipts))
@@ -425,7 +535,7 @@ func NewBatch(batch *state.Batch, virtualBatch *state.VirtualBatch, verifiedBatc
 	for _, b := range blocks {
 		b := b
 		if fullTx {
-			block, err := NewBlock(state.HashPtr(b.Hash()), &b, nil, false, false)
+			block, err := NewBlock(state.HashPtr(b.Hash()), &b, nil, false, false, forkID)
 			if err != nil {
 				return nil, err
 			}
@@ -436,7 +546,7 @@ func NewBatch(batch *state.Batch, virtualBatch *state.VirtualBatch, verifiedBatc
 		}
 	}
 
-	return res, nil
+	return res, exitRootsErr
 }
 
 // TransactionOrHash for union type of transaction and types.Hash
@@ -455,6 +565,15 @@ func (th TransactionOrHash) MarshalJSON() ([]byte, error) {
 
 // UnmarshalJSON unmarshals from json
 func (th *TransactionOrHash) UnmarshalJSON(input []byte) error {
+	// The hash can be received quoted twice ("\"0x...\""), in this case one layer
+	// of JSON string quoting is stripped before checking the 0x prefix
+	if strings.HasPrefix(string(input), "\"\\\"") {
+		var unquoted string
+		if err := json.Unmarshal(input, &unquoted); err != nil {
+			return err
+		}
+		input = []byte(unquoted)
+	}
 	v := string(input)
 	if strings.HasPrefix(v, "0x") || strings.HasPrefix(v, "\"0x") {
 		var h common.Hash
@@ -513,27 +632,55 @@ func (bh *BlockOrHash) UnmarshalJSON(input []byte) error {
 
 // Transaction structure
 type Transaction struct {
-	Nonce       ArgUint64       `json:"nonce"`
-	GasPrice    ArgBig          `json:"gasPrice"`
-	Gas         ArgUint64       `json:"gas"`
-	To          *common.Address `json:"to"`
-	Value       ArgBig          `json:"value"`
-	Input       ArgBytes        `json:"input"`
-	V           ArgBig          `json:"v"`
-	R           ArgBig          `json:"r"`
-	S           ArgBig          `json:"s"`
-	Hash        common.Hash     `json:"hash"`
-	From        common.Address  `json:"from"`
-	BlockHash   *common.Hash    `json:"blockHash"`
-	BlockNumber *ArgUint64      `json:"blockNumber"`
-	TxIndex     *ArgUint64      `json:"transactionIndex"`
-	ChainID     ArgBig          `json:"chainId"`
-	Type        ArgUint64       `json:"type"`
-	Receipt     *Receipt        `json:"receipt,omitempty"`
-}
-
-// CoreTx returns a geth core type Transaction
+	Nonce                ArgUint64       `json:"nonce"`
+	GasPrice             ArgBig          `json:"gasPrice"`
+	MaxFeePerGas         *ArgBig         `json:"maxFeePerGas,omitempty"`
+	MaxPriorityFeePerGas *ArgBig         `json:"maxPriorityFeePerGas,omitempty"`
+	Gas                  ArgUint64       `json:"gas"`
+	To                   *common.Address `json:"to"`
+	Value                ArgBig          `json:"value"`
+	Input                ArgBytes        `json:"input"`
+	V                    ArgBig          `json:"v"`
+	R                    ArgBig          `json:"r"`
+	S                    ArgBig          `json:"s"`
+	Hash                 common.Hash     `json:"hash"`
+	From                 common.Address  `json:"from"`
+	BlockHash            *common.Hash    `json:"blockHash"`
+	BlockNumber          *ArgUint64      `json:"blockNumber"`
+	TxIndex              *ArgUint64      `json:"transactionIndex"`
+	ChainID              ArgBig          `json:"chainId"`
+	Type                 ArgUint64       `json:"type"`
+	Receipt              *Receipt        `json:"receipt,omitempty"`
+}
+
+// CoreTx returns a geth core type Transaction, a DynamicFeeTx for EIP-1559 txs
+// and a LegacyTx for the rest of them
 func (t Transaction) CoreTx() *types.Transaction {
+	if uint8(t.Type) == types.DynamicFeeTxType {
+		// the fee fields are not available in txs returned by nodes that don't populate
+		// them, in that case the gas price is used for both
+		gasFeeCap, gasTipCap := (*big.Int)(&t.GasPrice), (*big.Int)(&t.GasPrice)
+		if t.MaxFeePerGas != nil {
+			gasFeeCap = (*big.Int)(t.MaxFeePerGas)
+		}
+		if t.MaxPriorityFeePerGas != nil {
+			gasTipCap = (*big.Int)(t.MaxPriorityFeePerGas)
+		}
+		return types.NewTx(&types.DynamicFeeTx{
+			ChainID:   (*big.Int)(&t.ChainID),
+			Nonce:     uint64(t.Nonce),
+			GasTipCap: gasTipCap,
+			GasFeeCap: gasFeeCap,
+			Gas:       uint64(t.Gas),
+			To:        t.To,
+			Value:     (*big.Int)(&t.Value),
+			Data:      t.Input,
+			V:         (*big.Int)(&t.V),
+			R:         (*big.Int)(&t.R),
+			S:         (*big.Int)(&t.S),
+		})
+	}
+
 	return types.NewTx(&types.LegacyTx{
 		Nonce:    uint64(t.Nonce),
 		GasPrice: (*big.Int)(&t.GasPrice),
@@ -547,6 +694,15 @@ func (t Transaction) CoreTx() *types.Transaction {
 	})
 }
 
+// getSender returns the sender of the tx, state.GetSender only supports legacy txs
+// so the latest signer is used for the rest of the tx types
+func getSender(tx types.Transaction) (common.Address, error) {
+	if tx.Type() == types.LegacyTxType {
+		return state.GetSender(tx)
+	}
+	return types.Sender(types.LatestSignerForChainID(tx.ChainId()), &tx)
+}
+
 // NewTransaction creates a transaction instance
 func NewTransaction(
 	tx types.Transaction,
@@ -555,7 +711,11 @@ func NewTransaction(
 ) (*Transaction, error) {
 	v, r, s := tx.RawSignatureValues()
 
-	from, _ := state.GetSender(tx)
+	from, err := getSender(tx)
+	if err != nil {
+		log.Warnf("failed to get sender of tx %s: %v", tx.Hash().String(), err)
+		return nil, err
+	}
 
 	res := &Transaction{
 		Nonce:    ArgUint64(tx.Nonce()),
@@ -573,6 +733,12 @@ func NewTransaction(
 		Type:     ArgUint64(tx.Type()),
 	}
 
+	if tx.Type() == types.DynamicFeeTxType {
+		maxFeePerGas, maxPriorityFeePerGas := ArgBig(*tx.GasFeeCap()), ArgBig(*tx.GasTipCap())
+		res.MaxFeePerGas = &maxFeePerGas
+		res.MaxPriorityFeePerGas = &maxPriorityFeePerGas
+	}
+
 	if receipt != nil {
 		bn := ArgUint64(receipt.BlockNumber.Uint64())
 		res.BlockNumber = &bn
@@ -599,6 +765,7 @@ type Receipt struct {
 	Logs              []*types.Log    `json:"logs"`
 	Status            ArgUint64       `json:"status"`
 	TxHash            common.Hash     `json:"transactionHash"`
+	L2TxHash          common.Hash     `json:"l2TxHash"`
 	TxIndex           ArgUint64       `json:"transactionIndex"`
 	BlockHash         common.Hash     `json:"blockHash"`
 	BlockNumber       ArgUint64       `json:"blockNumber"`
@@ -608,6 +775,24 @@ type Receipt struct {
 	ContractAddress   *common.Address `json:"contractAddress"`
 	Type              ArgUint64       `json:"type"`
 	EffectiveGasPrice *ArgBig         `json:"effectiveGasPrice,omitempty"`
+
+	// isPending is true when the receipt has no block number
+	isPending bool
+}
+
+// MarshalJSON marshals the receipt, omitting blockNumber, blockHash and
+// transactionIndex for pending receipts as defined in the Ethereum RPC spec
+func (r Receipt) MarshalJSON() ([]byte, error) {
+	type receipt Receipt
+	if !r.isPending {
+		return json.Marshal(receipt(r))
+	}
+	return json.Marshal(struct {
+		receipt
+		TxIndex     *ArgUint64   `json:"transactionIndex,omitempty"`
+		BlockHash   *common.Hash `json:"blockHash,omitempty"`
+		BlockNumber *ArgUint64   `json:"blockNumber,omitempty"`
+	}{receipt: receipt(r)})
 }
 
 // NewReceipt creates a new Receipt instance
@@ -629,7 +814,11 @@ func NewReceipt(tx types.Transaction, r *types.Receipt) (Receipt, error) {
 		blockNumber = ArgUint64(r.BlockNumber.Uint64())
 	}
 
-	from, err := state.GetSender(tx)
+	from, err := getSender(tx)
+	if err != nil {
+		return Receipt{}, err
+	}
+	l2TxHash, err := state.GetL2Hash(tx)
 	if err != nil {
 		return Receipt{}, err
 	}
@@ -640,6 +829,7 @@ func NewReceipt(tx types.Transaction, r *types.Receipt) (Receipt, error) {
 		Logs:              logs,
 		Status:            ArgUint64(r.Status),
 		TxHash:            r.TxHash,
+		L2TxHash:          l2TxHash,
 		TxIndex:           ArgUint64(r.TransactionIndex),
 		BlockHash:         r.BlockHash,
 		BlockNumber:       blockNumber,
@@ -648,6 +838,7 @@ func NewReceipt(tx types.Transaction, r *types.Receipt) (Receipt, error) {
 		FromAddr:          from,
 		ToAddr:            to,
 		Type:              ArgUint64(r.Type),
+		isPending:         r.BlockNumber == nil,
 	}
 	if r.EffectiveGasPrice != nil {
 		egp := ArgBig(*r.EffectiveGasPrice)
@@ -670,7 +861,10 @@ type Log struct {
 }
 
 // NewLog creates a new instance of Log
-func NewLog(l types.Log) Log {
+func NewLog(l types.Log) (Log, error) {
+	if err := ValidateL2Log(l); err != nil {
+		return Log{}, err
+	}
 	return Log{
 		Address:     l.Address,
 		Topics:      l.Topics,
@@ -681,6 +875,65 @@ func NewLog(l types.Log) Log {
 		BlockHash:   l.BlockHash,
 		LogIndex:    ArgUint64(l.Index),
 		Removed:     l.Removed,
+	}, nil
+}
+
+// ValidateL2Log returns an error if the log can't be a L2 log. L2 logs are never
+// removed because there are no uncle blocks in L2
+func ValidateL2Log(l types.Log) error {
+	if l.Removed {
+		return fmt.Errorf("%w: log %d of tx %s", ErrRemovedL2Log, l.Index, l.TxHash.String())
+	}
+	return nil
+}
+
+// VirtualBatch represents the sequencing information of a batch on L1
+type VirtualBatch struct {
+	BatchNumber      ArgUint64      `json:"batchNumber"`
+	SequencerAddress common.Address `json:"sequencerAddress"`
+	SequencedTxHash  common.Hash    `json:"sequencedTxHash"`
+	SequencedAt      *ArgUint64     `json:"sequencedAt"`
+	L1BlockNumber    ArgUint64      `json:"l1BlockNumber"`
+}
+
+// NewVirtualBatch creates a VirtualBatch instance
+func NewVirtualBatch(virtualBatch *state.VirtualBatch) *VirtualBatch {
+	res := &VirtualBatch{
+		BatchNumber:      ArgUint64(virtualBatch.BatchNumber),
+		SequencerAddress: virtualBatch.SequencerAddr,
+		SequencedTxHash:  virtualBatch.TxHash,
+		L1BlockNumber:    ArgUint64(virtualBatch.BlockNumber),
+	}
+
+	// The sequencing timestamp is only stored for the batches sequenced since etrog
+	if virtualBatch.TimestampBatchEtrog != nil {
+		sequencedAt := ArgUint64(virtualBatch.TimestampBatchEtrog.Unix())
+		res.SequencedAt = &sequencedAt
+	}
+
+	return res
+}
+
+// VerifiedBatch represents the verification information of a batch on L1
+type VerifiedBatch struct {
+	BatchNumber     ArgUint64      `json:"batchNumber"`
+	VerifierAddress common.Address `json:"verifierAddress"`
+	ProofTxHash     common.Hash    `json:"proofTxHash"`
+	VerifiedAt      ArgUint64      `json:"verifiedAt"`
+	L1BlockNumber   ArgUint64      `json:"l1BlockNumber"`
+	NewStateRoot    common.Hash    `json:"newStateRoot"`
+}
+
+// NewVerifiedBatch creates a VerifiedBatch instance, the verification time is the
+// time of the L1 block where the batch was verified
+func NewVerifiedBatch(verifiedBatch *state.VerifiedBatch, l1Block *state.Block) *VerifiedBatch {
+	return &VerifiedBatch{
+		BatchNumber:     ArgUint64(verifiedBatch.BatchNumber),
+		VerifierAddress: verifiedBatch.Aggregator,
+		ProofTxHash:     verifiedBatch.TxHash,
+		VerifiedAt:      ArgUint64(l1Block.ReceivedAt.Unix()),
+		L1BlockNumber:   ArgUint64(verifiedBatch.BlockNumber),
+		NewStateRoot:    verifiedBatch.StateRoot,
 	}
 }
 
@@ -689,3 +942,341 @@ type ExitRoots struct {
 	MainnetExitRoot common.Hash `json:"mainnetExitRoot"`
 	RollupExitRoot  common.Hash `json:"rollupExitRoot"`
 }
+
+// Validate returns an ErrInvalidExitRoots error if any of the exit roots is the zero hash
+func (e ExitRoots) Validate() error {
+	if e.MainnetExitRoot == (common.Hash{}) {
+		return fmt.Errorf("%w: mainnet exit root is zero", ErrInvalidExitRoots)
+	}
+	if e.RollupExitRoot == (common.Hash{}) {
+		return fmt.Errorf("%w: rollup exit root is zero", ErrInvalidExitRoots)
+	}
+	return nil
+}
+
+// ForcedBatch represents a forced batch sent to L1
+type ForcedBatch struct {
+	ForcedBatchNumber ArgUint64      `json:"forcedBatchNumber"`
+	GlobalExitRoot    common.Hash    `json:"globalExitRoot"`
+	RawTxData         ArgBytes       `json:"rawTxData"`
+	Timestamp         ArgUint64      `json:"timestamp"`
+	L1BlockNumber     ArgUint64      `json:"l1BlockNumber"`
+	Coinbase          common.Address `json:"coinbase"`
+}
+
+// NewForcedBatch creates a ForcedBatch instance
+func NewForcedBatch(forcedBatch state.ForcedBatch) ForcedBatch {
+	return ForcedBatch{
+		ForcedBatchNumber: ArgUint64(forcedBatch.ForcedBatchNumber),
+		GlobalExitRoot:    forcedBatch.GlobalExitRoot,
+		RawTxData:         ArgBytes(forcedBatch.RawTxsData),
+		Timestamp:         ArgUint64(forcedBatch.ForcedAt.Unix()),
+		L1BlockNumber:     ArgUint64(forcedBatch.BlockNumber),
+		Coinbase:          forcedBatch.Sequencer,
+	}
+}
+
+// TransactionEffectiveGasPrice is the effective gas price a tx was charged with
+type TransactionEffectiveGasPrice struct {
+	TxHash            common.Hash `json:"txHash"`
+	EffectiveGasPrice ArgBig      `json:"effectiveGasPrice"`
+}
+
+// BlockInfoRoot contains the roots of a L2 block needed to build L2 to L1 proofs
+type BlockInfoRoot struct {
+	BlockNumber    ArgUint64   `json:"blockNumber"`
+	BlockInfoRoot  common.Hash `json:"blockInfoRoot"`
+	GlobalExitRoot common.Hash `json:"globalExitRoot"`
+}
+
+// GasStationPrice contains the gas prices used by the sequencer to accept txs
+type GasStationPrice struct {
+	MinGasPrice       ArgUint64 `json:"minGasPrice"`
+	SuggestedGasPrice ArgUint64 `json:"suggestedGasPrice"`
+	L1GasPrice        ArgUint64 `json:"l1GasPrice"`
+	L1GasPriceFactor  float64   `json:"l1GasPriceFactor"`
+}
+
+// GlobalExitRoot represents a global exit root along with its exit roots
+type GlobalExitRoot struct {
+	GlobalExitRoot  common.Hash `json:"globalExitRoot"`
+	MainnetExitRoot common.Hash `json:"mainnetExitRoot"`
+	RollupExitRoot  common.Hash `json:"rollupExitRoot"`
+	Timestamp       ArgUint64   `json:"timestamp"`
+	BatchNumber     ArgUint64   `json:"batchNumber"`
+}
+
+// NewGlobalExitRoot creates a GlobalExitRoot instance, the batchNumber is the
+// last batch number known when the global exit root was read
+func NewGlobalExitRoot(ger *state.GlobalExitRoot, batchNumber uint64) *GlobalExitRoot {
+	return &GlobalExitRoot{
+		GlobalExitRoot:  ger.GlobalExitRoot,
+		MainnetExitRoot: ger.MainnetExitRoot,
+		RollupExitRoot:  ger.RollupExitRoot,
+		Timestamp:       ArgUint64(ger.Timestamp.Unix()),
+		BatchNumber:     ArgUint64(batchNumber),
+	}
+}
+
+// BatchStats represents the distribution of the gas used by the transactions of a batch
+type BatchStats struct {
+	BatchNumber ArgUint64 `json:"batchNumber"`
+	NumberOfTxs ArgUint64 `json:"numberOfTxs"`
+	MinGas      ArgUint64 `json:"minGas"`
+	MaxGas      ArgUint64 `json:"maxGas"`
+	AvgGas      ArgUint64 `json:"avgGas"`
+	TotalGas    ArgUint64 `json:"totalGas"`
+}
+
+// NewBatchStats creates a BatchStats instance
+func NewBatchStats(batchNumber uint64, numberOfTxs int, gasProfile state.BatchGasProfile) *BatchStats {
+	return &BatchStats{
+		BatchNumber: ArgUint64(batchNumber),
+		NumberOfTxs: ArgUint64(numberOfTxs),
+		MinGas:      ArgUint64(gasProfile.MinGas),
+		MaxGas:      ArgUint64(gasProfile.MaxGas),
+		AvgGas:      ArgUint64(gasProfile.AvgGas),
+		TotalGas:    ArgUint64(gasProfile.TotalGas),
+	}
+}
+
+// SequencerBatchStatus represents the status of the wip batch of the sequencer
+type SequencerBatchStatus struct {
+	WIPBatchNumber ArgUint64        `json:"wipBatchNumber"`
+	OpenedAt       ArgUint64        `json:"openedAt"`
+	CountOfTxs     ArgUint64        `json:"countOfTxs"`
+	FillPercent    BatchFillPercent `json:"fillPercent"`
+	// EstimatedCloseIn is the estimated number of seconds until one of the batch resources is
+	// exhausted, it is null if no resources have been used yet
+	EstimatedCloseIn *ArgUint64 `json:"estimatedCloseIn"`
+}
+
+// BatchFillPercent represents the percentage used of each one of the batch resources
+type BatchFillPercent struct {
+	Bytes            float64 `json:"bytes"`
+	Gas              float64 `json:"gas"`
+	Keccak           float64 `json:"keccak"`
+	PoseidonHashes   float64 `json:"poseidonHashes"`
+	PoseidonPaddings float64 `json:"poseidonPaddings"`
+	MemAligns        float64 `json:"memAligns"`
+	Arithmetics      float64 `json:"arithmetics"`
+	Binaries         float64 `json:"binaries"`
+	Steps            float64 `json:"steps"`
+	SHA256           float64 `json:"sha256"`
+}
+
+// max returns the highest percentage
+func (p BatchFillPercent) max() float64 {
+	return max(p.Bytes, p.Gas, p.Keccak, p.PoseidonHashes, p.PoseidonPaddings, p.MemAligns, p.Arithmetics, p.Binaries, p.Steps, p.SHA256)
+}
+
+// NewSequencerBatchStatus creates a SequencerBatchStatus instance for the wip batch. The time to close
+// the batch is estimated assuming the resources keep being used at the same rate since the batch was
+// opened (txs per second x average resources used by each tx)
+func NewSequencerBatchStatus(batch *state.Batch, countOfTxs int, constraints state.BatchConstraintsCfg, now time.Time) *SequencerBatchStatus {
+	percent := func(used, limit uint64) float64 {
+		if limit == 0 {
+			return 0
+		}
+		return float64(used) * 100 / float64(limit) //nolint:gomnd
+	}
+	zkCounters := batch.Resources.ZKCounters
+	fillPercent := BatchFillPercent{
+		Bytes:            percent(batch.Resources.Bytes, constraints.MaxBatchBytesSize),
+		Gas:              percent(zkCounters.GasUsed, constraints.MaxCumulativeGasUsed),
+		Keccak:           percent(uint64(zkCounters.UsedKeccakHashes), uint64(constraints.MaxKeccakHashes)),
+		PoseidonHashes:   percent(uint64(zkCounters.UsedPoseidonHashes), uint64(constraints.MaxPoseidonHashes)),
+		PoseidonPaddings: percent(uint64(zkCounters.UsedPoseidonPaddings), uint64(constraints.MaxPoseidonPaddings)),
+		MemAligns:        percent(uint64(zkCounters.UsedMemAligns), uint64(constraints.MaxMemAligns)),
+		Arithmetics:      percent(uint64(zkCounters.UsedArithmetics), uint64(constraints.MaxArithmetics)),
+		Binaries:         percent(uint64(zkCounters.UsedBinaries), uint64(constraints.MaxBinaries)),
+		Steps:            percent(uint64(zkCounters.UsedSteps), uint64(constraints.MaxSteps)),
+		SHA256:           percent(uint64(zkCounters.UsedSha256Hashes_V2), uint64(constraints.MaxSHA256Hashes)),
+	}
+
+	var estimatedCloseIn *ArgUint64
+	if maxPercent := fillPercent.max(); maxPercent >= 100 { //nolint:gomnd
+		estimatedCloseIn = ArgUint64Ptr(0)
+	} else if maxPercent > 0 && now.After(batch.Timestamp) {
+		elapsed := now.Sub(batch.Timestamp).Seconds()
+		estimatedCloseIn = ArgUint64Ptr(ArgUint64(elapsed * (100 - maxPercent) / maxPercent)) //nolint:gomnd
+	}
+
+	return &SequencerBatchStatus{
+		WIPBatchNumber:   ArgUint64(batch.BatchNumber),
+		OpenedAt:         ArgUint64(batch.Timestamp.Unix()),
+		CountOfTxs:       ArgUint64(countOfTxs),
+		FillPercent:      fillPercent,
+		EstimatedCloseIn: estimatedCloseIn,
+	}
+}
+
+// L1InfoTreeData represents the L1 info tree leaf data used by a batch
+type L1InfoTreeData struct {
+	GlobalExitRoot common.Hash   `json:"globalExitRoot"`
+	BlockHashL1    common.Hash   `json:"blockHashL1"`
+	MinTimestamp   ArgUint64     `json:"minTimestamp"`
+	SmtProof       []common.Hash `json:"smtProof"`
+}
+
+// BatchWitness represents the input the prover needs to prove a batch
+type BatchWitness struct {
+	BatchL2Data       ArgBytes                  `json:"batchL2Data"`
+	OldStateRoot      common.Hash               `json:"oldStateRoot"`
+	OldAccInputHash   common.Hash               `json:"oldAccInputHash"`
+	L1InfoTreeData    map[uint32]L1InfoTreeData `json:"l1InfoTreeData"`
+	Coinbase          common.Address            `json:"coinbase"`
+	TimestampLimit    ArgUint64                 `json:"timestampLimit"`
+	ChainID           ArgUint64                 `json:"chainId"`
+	ForkID            ArgUint64                 `json:"forkID"`
+	L1InfoRoot        common.Hash               `json:"l1InfoRoot"`
+	ForcedBlockHashL1 common.Hash               `json:"forcedBlockHashL1"`
+}
+
+// NewBatchWitness creates a BatchWitness instance
+func NewBatchWitness(batch *state.Batch, previousBatch *state.Batch, l1InfoTreeData map[uint32]state.L1DataV2, l1InfoRoot common.Hash, forcedBlockHashL1 common.Hash, chainID uint64, forkID uint64) *BatchWitness {
+	res := &BatchWitness{
+		BatchL2Data:       ArgBytes(batch.BatchL2Data),
+		OldStateRoot:      previousBatch.StateRoot,
+		OldAccInputHash:   previousBatch.AccInputHash,
+		L1InfoTreeData:    make(map[uint32]L1InfoTreeData, len(l1InfoTreeData)),
+		Coinbase:          batch.Coinbase,
+		TimestampLimit:    ArgUint64(batch.Timestamp.Unix()),
+		ChainID:           ArgUint64(chainID),
+		ForkID:            ArgUint64(forkID),
+		L1InfoRoot:        l1InfoRoot,
+		ForcedBlockHashL1: forcedBlockHashL1,
+	}
+
+	for index, data := range l1InfoTreeData {
+		smtProof := make([]common.Hash, 0, len(data.SmtProof))
+		for _, proof := range data.SmtProof {
+			smtProof = append(smtProof, common.BytesToHash(proof))
+		}
+		res.L1InfoTreeData[index] = L1InfoTreeData{
+			GlobalExitRoot: data.GlobalExitRoot,
+			BlockHashL1:    data.BlockHashL1,
+			MinTimestamp:   ArgUint64(data.MinTimestamp),
+			SmtProof:       smtProof,
+		}
+	}
+
+	return res
+}
+
+// BatchByStateRoot represents a batch that references a state root, either as the state root
+// after processing the batch or as the initial state root the batch was processed from
+type BatchByStateRoot struct {
+	Number             ArgUint64   `json:"number"`
+	StateRoot          common.Hash `json:"stateRoot"`
+	AccInputHash       common.Hash `json:"accInputHash"`
+	Closed             bool        `json:"closed"`
+	IsStateRoot        bool        `json:"isStateRoot"`
+	IsInitialStateRoot bool        `json:"isInitialStateRoot"`
+}
+
+// NewBatchesByStateRoot creates the BatchByStateRoot instances for the batches returned by
+// the state for the provided state root. The initial state root of a batch is the state root
+// of the previous one, so it's referenced when the previous batch is in the list with it
+func NewBatchesByStateRoot(batches []*state.Batch, stateRoot common.Hash) []BatchByStateRoot {
+	withStateRoot := make(map[uint64]bool, len(batches))
+	for _, batch := range batches {
+		if batch.StateRoot == stateRoot {
+			withStateRoot[batch.BatchNumber] = true
+		}
+	}
+
+	res := make([]BatchByStateRoot, 0, len(batches))
+	for _, batch := range batches {
+		res = append(res, BatchByStateRoot{
+			Number:             ArgUint64(batch.BatchNumber),
+			StateRoot:          batch.StateRoot,
+			AccInputHash:       batch.AccInputHash,
+			Closed:             !batch.WIP,
+			IsStateRoot:        batch.StateRoot == stateRoot,
+			IsInitialStateRoot: batch.BatchNumber > 0 && withStateRoot[batch.BatchNumber-1],
+		})
+	}
+	return res
+}
+
+// CallResult structure
+type CallResult struct {
+	Result ArgBytes `json:"result"`
+	Error  string   `json:"error,omitempty"`
+}
+
+// AccountProof is the response of zkevm_getProof. The zkEVM state is a sparse merkle tree
+// where each field of an account is a leaf, so there is a proof for each field instead of
+// the single account proof of eth_getProof
+type AccountProof struct {
+	Address         common.Address `json:"address"`
+	Balance         ArgBig         `json:"balance"`
+	Nonce           ArgUint64      `json:"nonce"`
+	CodeHash        common.Hash    `json:"codeHash"`
+	CodeLength      ArgUint64      `json:"codeLength"`
+	BalanceProof    []ArgBytes     `json:"balanceProof"`
+	NonceProof      []ArgBytes     `json:"nonceProof"`
+	CodeHashProof   []ArgBytes     `json:"codeHashProof"`
+	CodeLengthProof []ArgBytes     `json:"codeLengthProof"`
+	StorageProof    []StorageProof `json:"storageProof"`
+}
+
+// StorageProof is the proof of a storage position of an account
+type StorageProof struct {
+	Key   common.Hash `json:"key"`
+	Value ArgBig      `json:"value"`
+	Proof []ArgBytes  `json:"proof"`
+}
+
+// NewAccountProof creates an AccountProof from the merkle proofs of the account leafs,
+// storageKeys must be in the same order as the storage proofs
+func NewAccountProof(address common.Address, storageKeys []common.Hash, proof *merkletree.AccountProof) AccountProof {
+	res := AccountProof{
+		Address:         address,
+		Balance:         ArgBig(*proof.Balance.Value),
+		Nonce:           ArgUint64(proof.Nonce.Value.Uint64()),
+		CodeHash:        common.BigToHash(proof.CodeHash.Value),
+		CodeLength:      ArgUint64(proof.CodeLength.Value.Uint64()),
+		BalanceProof:    newSiblings(proof.Balance.Siblings),
+		NonceProof:      newSiblings(proof.Nonce.Siblings),
+		CodeHashProof:   newSiblings(proof.CodeHash.Siblings),
+		CodeLengthProof: newSiblings(proof.CodeLength.Siblings),
+		StorageProof:    make([]StorageProof, 0, len(proof.Storage)),
+	}
+	for i, storageProof := range proof.Storage {
+		res.StorageProof = append(res.StorageProof, StorageProof{
+			Key:   storageKeys[i],
+			Value: ArgBig(*storageProof.Value),
+			Proof: newSiblings(storageProof.Siblings),
+		})
+	}
+	return res
+}
+
+func newSiblings(siblings [][]byte) []ArgBytes {
+	res := make([]ArgBytes, 0, len(siblings))
+	for _, sibling := range siblings {
+		res = append(res, sibling)
+	}
+	return res
+}
+
+// VerifiedBatchNotification is sent to the zkevm_subscribeToVerifiedBatches
+// subscriptions when a batch is verified on L1
+type VerifiedBatchNotification struct {
+	BatchNumber   ArgUint64   `json:"batchNumber"`
+	VerifyTxHash  common.Hash `json:"verifyTxHash"`
+	NewStateRoot  common.Hash `json:"newStateRoot"`
+	L1BlockNumber ArgUint64   `json:"l1BlockNumber"`
+}
+
+// NewVerifiedBatchNotification creates a VerifiedBatchNotification from a state.VerifiedBatch
+func NewVerifiedBatchNotification(verifiedBatch state.VerifiedBatch) VerifiedBatchNotification {
+	return VerifiedBatchNotification{
+		BatchNumber:   ArgUint64(verifiedBatch.BatchNumber),
+		VerifyTxHash:  verifiedBatch.TxHash,
+		NewStateRoot:  verifiedBatch.StateRoot,
+		L1BlockNumber: ArgUint64(verifiedBatch.BlockNumber),
+	}
+}
@@ -3,11 +3,17 @@ package types
 import (
 	"encoding/json"
 	"fmt"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/rlp"
+	"github.com/ethereum/go-ethereum/trie"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -32,6 +38,24 @@ func TestArgHashUnmarshalFromShortString(t *testing.T) {
 			expectedResult: "0x0000000000000000000000000000000000000000000000000000000000000001",
 			expectedError:  nil,
 		},
+		{
+			name:           "single zero character",
+			input:          "0",
+			expectedResult: "0x0000000000000000000000000000000000000000000000000000000000000000",
+			expectedError:  nil,
+		},
+		{
+			name:           "empty string",
+			input:          "",
+			expectedResult: "0x0000000000000000000000000000000000000000000000000000000000000000",
+			expectedError:  ErrEmptyHash,
+		},
+		{
+			name:           "only 0x prefix",
+			input:          "0x",
+			expectedResult: "0x0000000000000000000000000000000000000000000000000000000000000000",
+			expectedError:  ErrEmptyHash,
+		},
 		{
 			name:           "valid full hash value",
 			input:          "0x05b21ee5f65c28a0af8e71290fc33625a1279a8b3d6357ce3ca60f22dbf59e63",
@@ -195,6 +219,54 @@ func TestBatchUnmarshal(t *testing.T) {
 				},
 			},
 		},
+		{
+			name: "pending proof status",
+			json: `{"number":"0x1","coinbase":"0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266","stateRoot":"0x49e7b7eb6bb34b07a1063cd2c7a9cac88845c1867e8a026d69fc00b862c2ca72","globalExitRoot":"0x0000000000000000000000000000000000000000000000000000000000000001","localExitRoot":"0x0000000000000000000000000000000000000000000000000000000000000002","accInputHash":"0x0000000000000000000000000000000000000000000000000000000000000003","timestamp":"0x64133495","sendSequencesTxHash":null,"verifyBatchTxHash":null,"proofStatus":"pending","batchL2Data":"0x04"}`,
+			expected: Batch{
+				Number:         1,
+				Coinbase:       common.HexToAddress("0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266"),
+				StateRoot:      common.HexToHash("0x49e7b7eb6bb34b07a1063cd2c7a9cac88845c1867e8a026d69fc00b862c2ca72"),
+				GlobalExitRoot: common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001"),
+				LocalExitRoot:  common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000002"),
+				AccInputHash:   common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000003"),
+				Timestamp:      ArgUint64(1678980245),
+				BatchL2Data:    ArgBytes(hexToBytes("0x04")),
+				ProofStatus:    BatchProofStatusPending,
+			},
+		},
+		{
+			name: "virtual proof status",
+			json: `{"number":"0x1","coinbase":"0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266","stateRoot":"0x49e7b7eb6bb34b07a1063cd2c7a9cac88845c1867e8a026d69fc00b862c2ca72","globalExitRoot":"0x0000000000000000000000000000000000000000000000000000000000000001","localExitRoot":"0x0000000000000000000000000000000000000000000000000000000000000002","accInputHash":"0x0000000000000000000000000000000000000000000000000000000000000003","timestamp":"0x64133495","sendSequencesTxHash":"0x0000000000000000000000000000000000000000000000000000000000000004","verifyBatchTxHash":null,"proofStatus":"virtual","batchL2Data":"0x04"}`,
+			expected: Batch{
+				Number:              1,
+				Coinbase:            common.HexToAddress("0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266"),
+				StateRoot:           common.HexToHash("0x49e7b7eb6bb34b07a1063cd2c7a9cac88845c1867e8a026d69fc00b862c2ca72"),
+				GlobalExitRoot:      common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001"),
+				LocalExitRoot:       common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000002"),
+				AccInputHash:        common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000003"),
+				Timestamp:           ArgUint64(1678980245),
+				BatchL2Data:         ArgBytes(hexToBytes("0x04")),
+				SendSequencesTxHash: state.HexToHashPtr("0x0000000000000000000000000000000000000000000000000000000000000004"),
+				ProofStatus:         BatchProofStatusVirtual,
+			},
+		},
+		{
+			name: "verified proof status",
+			json: `{"number":"0x1","coinbase":"0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266","stateRoot":"0x49e7b7eb6bb34b07a1063cd2c7a9cac88845c1867e8a026d69fc00b862c2ca72","globalExitRoot":"0x0000000000000000000000000000000000000000000000000000000000000001","localExitRoot":"0x0000000000000000000000000000000000000000000000000000000000000002","accInputHash":"0x0000000000000000000000000000000000000000000000000000000000000003","timestamp":"0x64133495","sendSequencesTxHash":"0x0000000000000000000000000000000000000000000000000000000000000004","verifyBatchTxHash":"0x0000000000000000000000000000000000000000000000000000000000000005","proofStatus":"verified","batchL2Data":"0x04"}`,
+			expected: Batch{
+				Number:              1,
+				Coinbase:            common.HexToAddress("0xf39fd6e51aad88f6f4ce6ab8827279cfffb92266"),
+				StateRoot:           common.HexToHash("0x49e7b7eb6bb34b07a1063cd2c7a9cac88845c1867e8a026d69fc00b862c2ca72"),
+				GlobalExitRoot:      common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000001"),
+				LocalExitRoot:       common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000002"),
+				AccInputHash:        common.HexToHash("0x0000000000000000000000000000000000000000000000000000000000000003"),
+				Timestamp:           ArgUint64(1678980245),
+				BatchL2Data:         ArgBytes(hexToBytes("0x04")),
+				SendSequencesTxHash: state.HexToHashPtr("0x0000000000000000000000000000000000000000000000000000000000000004"),
+				VerifyBatchTxHash:   state.HexToHashPtr("0x0000000000000000000000000000000000000000000000000000000000000005"),
+				ProofStatus:         BatchProofStatusVerified,
+			},
+		},
 	}
 
 	for _, testCase := range testCases {
@@ -213,6 +285,7 @@ func TestBatchUnmarshal(t *testing.T) {
 		assert.Equal(t, testCase.expected.Timestamp, result.Timestamp)
 		assert.Equal(t, testCase.expected.SendSequencesTxHash, result.SendSequencesTxHash)
 		assert.Equal(t, testCase.expected.VerifyBatchTxHash, result.VerifyBatchTxHash)
+		assert.Equal(t, testCase.expected.ProofStatus, result.ProofStatus)
 		assert.Equal(t, testCase.expected.BatchL2Data, result.BatchL2Data)
 		assert.Equal(t, len(testCase.expected.Transactions), len(result.Transactions))
 
@@ -244,7 +317,348 @@ func TestBatchUnmarshal(t *testing.T) {
 	}
 }
 
+func TestNewBlockFailsWhenSenderCantBeRecovered(t *testing.T) {
+	unsignedTx := ethTypes.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), []byte{})
+	l2Block := state.NewL2Block(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1)}), []*ethTypes.Transaction{unsignedTx}, nil, nil, &trie.StackTrie{})
+
+	_, err := NewBlock(state.HashPtr(l2Block.Hash()), l2Block, nil, true, false, state.FORKID_ETROG)
+	require.Error(t, err)
+
+	// The sender is not needed when only the tx hashes are returned
+	block, err := NewBlock(state.HashPtr(l2Block.Hash()), l2Block, nil, false, false, state.FORKID_ETROG)
+	require.NoError(t, err)
+	require.Equal(t, 1, len(block.Transactions))
+	assert.Equal(t, unsignedTx.Hash(), *block.Transactions[0].Hash)
+}
+
+func TestNewBlockSizeIsTheRLPEncodedSize(t *testing.T) {
+	header := &ethTypes.Header{Number: big.NewInt(1), GasLimit: 30000000, Time: 1678980245, Extra: []byte("extra")}
+	txs := []*ethTypes.Transaction{
+		ethTypes.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), []byte{}),
+		ethTypes.NewTransaction(2, common.HexToAddress("0x2"), big.NewInt(2), 50000, big.NewInt(1), []byte{0x01, 0x02, 0x03}),
+	}
+	l2Block := state.NewL2Block(state.NewL2Header(header), txs, nil, nil, &trie.StackTrie{})
+
+	encoded, err := rlp.EncodeToBytes(ethTypes.NewBlock(header, txs, nil, nil, &trie.StackTrie{}))
+	require.NoError(t, err)
+
+	block, err := NewBlock(state.HashPtr(l2Block.Hash()), l2Block, nil, false, false, state.FORKID_ETROG)
+	require.NoError(t, err)
+	assert.Equal(t, ArgUint64(len(encoded)), block.Size)
+}
+
+func TestNewBlockFailsWhenHeaderIsMissing(t *testing.T) {
+	block, err := NewBlock(nil, &state.L2Block{}, nil, true, true, state.FORKID_ETROG)
+	require.ErrorIs(t, err, ErrMalformedL2Block)
+	assert.Nil(t, block)
+}
+
+func TestNewBlockHasEmptyWithdrawals(t *testing.T) {
+	l2Block := state.NewL2Block(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1)}), nil, nil, nil, &trie.StackTrie{})
+
+	block, err := NewBlock(state.HashPtr(l2Block.Hash()), l2Block, nil, false, false, state.FORKID_ETROG)
+	require.NoError(t, err)
+	assert.NotNil(t, block.Withdrawals)
+	assert.Len(t, block.Withdrawals, 0)
+
+	b, err := json.Marshal(block)
+	require.NoError(t, err)
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(b, &fields))
+	assert.Equal(t, "[]", string(fields["withdrawals"]))
+}
+
+func TestNewReceiptL2TxHash(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx := ethTypes.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), []byte{})
+	signedTx, err := ethTypes.SignTx(tx, ethTypes.NewEIP155Signer(big.NewInt(1000)), privateKey)
+	require.NoError(t, err)
+
+	r := ethTypes.NewReceipt([]byte{}, false, 21000)
+	r.TxHash = signedTx.Hash()
+	r.BlockNumber = big.NewInt(1)
+
+	receipt, err := NewReceipt(*signedTx, r)
+	require.NoError(t, err)
+	assert.Equal(t, signedTx.Hash(), receipt.TxHash)
+	expectedL2TxHash, err := state.GetL2Hash(*signedTx)
+	require.NoError(t, err)
+	assert.Equal(t, expectedL2TxHash, receipt.L2TxHash)
+	assert.NotEqual(t, receipt.TxHash, receipt.L2TxHash)
+
+	b, err := json.Marshal(receipt)
+	require.NoError(t, err)
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(b, &fields))
+	assert.Equal(t, fmt.Sprintf("%q", expectedL2TxHash.String()), string(fields["l2TxHash"]))
+}
+
+func TestNewBlockHasZeroNonce(t *testing.T) {
+	l2Block := state.NewL2Block(state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1)}), nil, nil, nil, &trie.StackTrie{})
+
+	block, err := NewBlock(state.HashPtr(l2Block.Hash()), l2Block, nil, false, false, state.FORKID_ETROG)
+	require.NoError(t, err)
+
+	b, err := json.Marshal(block)
+	require.NoError(t, err)
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(b, &fields))
+	assert.Equal(t, `"0x0000000000000000"`, string(fields["nonce"]))
+}
+
+func TestNewBlockExitRootsByForkID(t *testing.T) {
+	header := state.NewL2Header(&ethTypes.Header{Number: big.NewInt(1)})
+	header.GlobalExitRoot = common.HexToHash("0x1")
+	header.BlockInfoRoot = common.HexToHash("0x2")
+	l2Block := state.NewL2Block(header, nil, nil, nil, &trie.StackTrie{})
+
+	block, err := NewBlock(state.HashPtr(l2Block.Hash()), l2Block, nil, false, false, state.FORKID_DRAGONFRUIT)
+	require.NoError(t, err)
+	assert.Equal(t, common.Hash{}, block.GlobalExitRoot)
+	assert.Equal(t, common.Hash{}, block.BlockInfoRoot)
+
+	block, err = NewBlock(state.HashPtr(l2Block.Hash()), l2Block, nil, false, false, state.FORKID_ETROG)
+	require.NoError(t, err)
+	assert.Equal(t, common.HexToHash("0x1"), block.GlobalExitRoot)
+	assert.Equal(t, common.HexToHash("0x2"), block.BlockInfoRoot)
+}
+
 func hexToBytes(str string) []byte {
 	bytes, _ := hex.DecodeHex(str)
 	return bytes
 }
+
+func TestExitRootsValidate(t *testing.T) {
+	testCases := []struct {
+		name          string
+		exitRoots     ExitRoots
+		expectedError bool
+	}{
+		{"valid", ExitRoots{MainnetExitRoot: common.HexToHash("0x1"), RollupExitRoot: common.HexToHash("0x2")}, false},
+		{"zero mainnet exit root", ExitRoots{RollupExitRoot: common.HexToHash("0x2")}, true},
+		{"zero rollup exit root", ExitRoots{MainnetExitRoot: common.HexToHash("0x1")}, true},
+		{"zero exit roots", ExitRoots{}, true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := tc.exitRoots.Validate()
+			if tc.expectedError {
+				assert.ErrorIs(t, err, ErrInvalidExitRoots)
+			} else {
+				assert.NoError(t, err)
+			}
+		})
+	}
+}
+
+func TestNewBatchInvalidExitRoots(t *testing.T) {
+	batch := &state.Batch{
+		BatchNumber:    1,
+		GlobalExitRoot: common.HexToHash("0x1"),
+	}
+
+	// The batch is still returned, with zero exit roots
+	rpcBatch, err := NewBatch(batch, nil, nil, nil, nil, false, false, true, &state.GlobalExitRoot{GlobalExitRoot: batch.GlobalExitRoot}, state.FORKID_ETROG)
+	assert.ErrorIs(t, err, ErrInvalidExitRoots)
+	require.NotNil(t, rpcBatch)
+	assert.Equal(t, batch.GlobalExitRoot, rpcBatch.GlobalExitRoot)
+	assert.Equal(t, common.Hash{}, rpcBatch.MainnetExitRoot)
+	assert.Equal(t, common.Hash{}, rpcBatch.RollupExitRoot)
+
+	// A batch without global exit root is expected to have zero exit roots
+	batch.GlobalExitRoot = common.Hash{}
+	rpcBatch, err = NewBatch(batch, nil, nil, nil, nil, false, false, true, &state.GlobalExitRoot{}, state.FORKID_ETROG)
+	require.NoError(t, err)
+	assert.Equal(t, BatchProofStatusPending, rpcBatch.ProofStatus)
+}
+
+func TestNewBatchWithoutL2Data(t *testing.T) {
+	batch := &state.Batch{
+		BatchNumber: 1,
+		BatchL2Data: []byte{0x1, 0x2, 0x3},
+	}
+
+	rpcBatch, err := NewBatch(batch, nil, nil, nil, nil, false, false, true, &state.GlobalExitRoot{}, state.FORKID_ETROG)
+	require.NoError(t, err)
+	assert.Equal(t, ArgBytes(batch.BatchL2Data), rpcBatch.BatchL2Data)
+	assert.False(t, rpcBatch.BatchL2DataTruncated)
+
+	rpcBatch, err = NewBatch(batch, nil, nil, nil, nil, false, false, false, &state.GlobalExitRoot{}, state.FORKID_ETROG)
+	require.NoError(t, err)
+	assert.Nil(t, rpcBatch.BatchL2Data)
+	assert.True(t, rpcBatch.BatchL2DataTruncated)
+
+	b, err := json.Marshal(rpcBatch)
+	require.NoError(t, err)
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(b, &fields))
+	assert.Equal(t, "true", string(fields["batchL2DataTruncated"]))
+}
+
+func TestTransactionOrHashUnmarshalQuotedHash(t *testing.T) {
+	hash := common.HexToHash("0x1100000000000000000000000000000000000000000000000000000000000022")
+
+	// round trip
+	b, err := json.Marshal(TransactionOrHash{Hash: &hash})
+	require.NoError(t, err)
+	var th TransactionOrHash
+	require.NoError(t, json.Unmarshal(b, &th))
+	require.Nil(t, th.Tx)
+	require.Equal(t, hash, *th.Hash)
+
+	// hash quoted twice
+	quoted, err := json.Marshal(string(b))
+	require.NoError(t, err)
+	assert.Equal(t, `"\"0x1100000000000000000000000000000000000000000000000000000000000022\""`, string(quoted))
+	th = TransactionOrHash{}
+	require.NoError(t, json.Unmarshal(quoted, &th))
+	require.Nil(t, th.Tx)
+	require.Equal(t, hash, *th.Hash)
+}
+
+func TestNewLogFailsWhenLogIsRemoved(t *testing.T) {
+	l := ethTypes.Log{
+		Address:     common.HexToAddress("0x1"),
+		BlockNumber: 10,
+		TxHash:      common.HexToHash("0x2"),
+		Index:       3,
+	}
+	rpcLog, err := NewLog(l)
+	require.NoError(t, err)
+	assert.False(t, rpcLog.Removed)
+	assert.Equal(t, ArgUint64(3), rpcLog.LogIndex)
+
+	l.Removed = true
+	_, err = NewLog(l)
+	require.ErrorIs(t, err, ErrRemovedL2Log)
+	require.ErrorIs(t, ValidateL2Log(l), ErrRemovedL2Log)
+}
+
+func TestArgUint64Format(t *testing.T) {
+	n := ArgUint64(255)
+	assert.Equal(t, "255", n.String())
+	assert.Equal(t, "255", fmt.Sprintf("%v", n))
+	assert.Equal(t, "0xff", fmt.Sprintf("%#v", n))
+	assert.Equal(t, "0xff", n.Hex())
+}
+
+func TestArgBigFormat(t *testing.T) {
+	n := ArgBig(*big.NewInt(255))
+	assert.Equal(t, "255", n.String())
+	assert.Equal(t, "255", fmt.Sprintf("%v", n))
+	assert.Equal(t, "0xff", fmt.Sprintf("%#v", n))
+	assert.Equal(t, "0xff", n.Hex())
+}
+
+func TestNewReceiptPendingOmitsBlockFields(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	tx := ethTypes.NewTransaction(1, common.HexToAddress("0x1"), big.NewInt(1), 21000, big.NewInt(1), []byte{})
+	signedTx, err := ethTypes.SignTx(tx, ethTypes.NewEIP155Signer(big.NewInt(1000)), privateKey)
+	require.NoError(t, err)
+
+	r := ethTypes.NewReceipt([]byte{}, false, 21000)
+	r.TxHash = signedTx.Hash()
+
+	receipt, err := NewReceipt(*signedTx, r)
+	require.NoError(t, err)
+	b, err := json.Marshal(receipt)
+	require.NoError(t, err)
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(b, &fields))
+	assert.NotContains(t, fields, "blockNumber")
+	assert.NotContains(t, fields, "blockHash")
+	assert.NotContains(t, fields, "transactionIndex")
+	assert.Equal(t, fmt.Sprintf("%q", signedTx.Hash().String()), string(fields["transactionHash"]))
+
+	r.BlockNumber = big.NewInt(0)
+	receipt, err = NewReceipt(*signedTx, r)
+	require.NoError(t, err)
+	b, err = json.Marshal(receipt)
+	require.NoError(t, err)
+	fields = map[string]json.RawMessage{}
+	require.NoError(t, json.Unmarshal(b, &fields))
+	assert.Equal(t, `"0x0"`, string(fields["blockNumber"]))
+	assert.Contains(t, fields, "blockHash")
+	assert.Contains(t, fields, "transactionIndex")
+}
+
+func TestNewSequencerBatchStatus(t *testing.T) {
+	openedAt := time.Unix(1678980245, 0)
+	constraints := state.BatchConstraintsCfg{MaxBatchBytesSize: 1000, MaxCumulativeGasUsed: 1000, MaxSteps: 1000}
+	batch := &state.Batch{
+		BatchNumber: 5,
+		Timestamp:   openedAt,
+		Resources:   state.BatchResources{Bytes: 100, ZKCounters: state.ZKCounters{GasUsed: 250, UsedSteps: 100}},
+	}
+
+	// 25% of the gas used in 10s, the gas is exhausted in 30s more
+	status := NewSequencerBatchStatus(batch, 3, constraints, openedAt.Add(10*time.Second))
+	assert.Equal(t, ArgUint64(5), status.WIPBatchNumber)
+	assert.Equal(t, ArgUint64(openedAt.Unix()), status.OpenedAt)
+	assert.Equal(t, ArgUint64(3), status.CountOfTxs)
+	assert.Equal(t, BatchFillPercent{Bytes: 10, Gas: 25, Steps: 10}, status.FillPercent)
+	require.NotNil(t, status.EstimatedCloseIn)
+	assert.Equal(t, ArgUint64(30), *status.EstimatedCloseIn)
+
+	batch.Resources.ZKCounters.GasUsed = 1000
+	status = NewSequencerBatchStatus(batch, 3, constraints, openedAt.Add(10*time.Second))
+	assert.Equal(t, ArgUint64(0), *status.EstimatedCloseIn)
+
+	batch.Resources = state.BatchResources{}
+	status = NewSequencerBatchStatus(batch, 0, constraints, openedAt.Add(10*time.Second))
+	assert.Nil(t, status.EstimatedCloseIn)
+}
+
+func TestNewTransactionDynamicFeeFields(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	to := common.HexToAddress("0x1")
+	chainID := big.NewInt(1000)
+
+	dynamicFeeTx := ethTypes.NewTx(&ethTypes.DynamicFeeTx{
+		ChainID:   chainID,
+		Nonce:     1,
+		GasTipCap: big.NewInt(2),
+		GasFeeCap: big.NewInt(10),
+		Gas:       21000,
+		To:        &to,
+		Value:     big.NewInt(1),
+		Data:      []byte{},
+	})
+	signedDynamicFeeTx, err := ethTypes.SignTx(dynamicFeeTx, ethTypes.NewLondonSigner(chainID), privateKey)
+	require.NoError(t, err)
+
+	tx, err := NewTransaction(*signedDynamicFeeTx, nil, false)
+	require.NoError(t, err)
+	require.NotNil(t, tx.MaxFeePerGas)
+	require.NotNil(t, tx.MaxPriorityFeePerGas)
+	assert.Equal(t, uint64(10), (*big.Int)(tx.MaxFeePerGas).Uint64())
+	assert.Equal(t, uint64(2), (*big.Int)(tx.MaxPriorityFeePerGas).Uint64())
+	assert.Equal(t, signedDynamicFeeTx.Hash(), tx.CoreTx().Hash())
+
+	b, err := json.Marshal(tx)
+	require.NoError(t, err)
+	var fields map[string]json.RawMessage
+	require.NoError(t, json.Unmarshal(b, &fields))
+	assert.Equal(t, `"0xa"`, string(fields["maxFeePerGas"]))
+	assert.Equal(t, `"0x2"`, string(fields["maxPriorityFeePerGas"]))
+
+	legacyTx := ethTypes.NewTransaction(1, to, big.NewInt(1), 21000, big.NewInt(1), []byte{})
+	signedLegacyTx, err := ethTypes.SignTx(legacyTx, ethTypes.NewEIP155Signer(chainID), privateKey)
+	require.NoError(t, err)
+
+	tx, err = NewTransaction(*signedLegacyTx, nil, false)
+	require.NoError(t, err)
+	assert.Nil(t, tx.MaxFeePerGas)
+	assert.Nil(t, tx.MaxPriorityFeePerGas)
+
+	b, err = json.Marshal(tx)
+	require.NoError(t, err)
+	fields = map[string]json.RawMessage{}
+	require.NoError(t, json.Unmarshal(b, &fields))
+	assert.NotContains(t, fields, "maxFeePerGas")
+	assert.NotContains(t, fields, "maxPriorityFeePerGas")
+}
@@ -114,6 +114,16 @@ func h4ToFilledByteSlice(h4 []uint64) []byte {
 	return ScalarToFilledByteSlice(h4ToScalar(h4))
 }
 
+// siblingToByteSlice converts the field elements of a sibling into a byte slice
+// with the big endian representation of each element.
+func siblingToByteSlice(sibling []uint64) []byte {
+	b := make([]byte, 0, len(sibling)*8) //nolint:gomnd
+	for _, fe := range sibling {
+		b = binary.BigEndian.AppendUint64(b, fe)
+	}
+	return b
+}
+
 // string2fea converts an string into an array of 32bit uint64 values.
 func string2fea(s string) ([]uint64, error) {
 	bi, ok := new(big.Int).SetString(s, hex.Base)
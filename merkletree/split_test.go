@@ -302,6 +302,35 @@ func Test_h4ToFilledByteSlice(t *testing.T) {
 	}
 }
 
+func Test_siblingToByteSlice(t *testing.T) {
+	tcs := []struct {
+		input    []uint64
+		expected string
+	}{
+		{
+			input:    []uint64{},
+			expected: "0x",
+		},
+		{
+			input:    []uint64{0, 1, 2, 3},
+			expected: "0x0000000000000000000000000000000100000000000000020000000000000003",
+		},
+		{
+			input:    []uint64{55345354959, 991992992929},
+			expected: "0x0000000ce2d718cf000000e6f763d4a1",
+		},
+	}
+
+	for i, tc := range tcs {
+		tc := tc
+		t.Run(fmt.Sprintf("test case %d", i), func(t *testing.T) {
+			actual := hex.EncodeToHex(siblingToByteSlice(tc.input))
+
+			require.Equal(t, tc.expected, actual)
+		})
+	}
+}
+
 func Test_string2fea(t *testing.T) {
 	tcs := []struct {
 		input            string
@@ -4,6 +4,7 @@ import (
 	"context"
 	"fmt"
 	"math/big"
+	"sort"
 	"strings"
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
@@ -123,6 +124,45 @@ func (tree *StateTree) GetStorageAt(ctx context.Context, address common.Address,
 	return fea2scalar(proof.Value), nil
 }
 
+// GetAccountProof returns the merkle proofs of the balance, nonce, code hash and code length leafs
+// of an account and of the given storage positions.
+func (tree *StateTree) GetAccountProof(ctx context.Context, address common.Address, storagePositions []*big.Int, root []byte) (*AccountProof, error) {
+	r := scalarToh4(new(big.Int).SetBytes(root))
+
+	keys := make([][]byte, 0, 4+len(storagePositions)) //nolint:gomnd
+	for _, keyFunc := range []func(common.Address) ([]byte, error){KeyEthAddrBalance, KeyEthAddrNonce, KeyContractCode, KeyCodeLength} {
+		key, err := keyFunc(address)
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+	for _, position := range storagePositions {
+		key, err := KeyContractStorage(address, position.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		keys = append(keys, key)
+	}
+
+	proofs := make([]*LeafProof, 0, len(keys))
+	for _, key := range keys {
+		proof, err := tree.getLeafProof(ctx, r, key)
+		if err != nil {
+			return nil, err
+		}
+		proofs = append(proofs, proof)
+	}
+
+	return &AccountProof{
+		Balance:    proofs[0],
+		Nonce:      proofs[1],
+		CodeHash:   proofs[2],
+		CodeLength: proofs[3],
+		Storage:    proofs[4:],
+	}, nil
+}
+
 // SetBalance sets balance.
 func (tree *StateTree) SetBalance(ctx context.Context, address common.Address, balance *big.Int, root []byte, uuid string) (newRoot []byte, proof *UpdateProof, err error) {
 	if balance.Cmp(big.NewInt(0)) == -1 {
@@ -260,6 +300,39 @@ func (tree *StateTree) get(ctx context.Context, root, key []uint64) (*Proof, err
 	}, nil
 }
 
+func (tree *StateTree) getLeafProof(ctx context.Context, root []uint64, key []byte) (*LeafProof, error) {
+	k := scalarToh4(new(big.Int).SetBytes(key))
+	result, err := tree.grpcClient.Get(ctx, &hashdb.GetRequest{
+		Root:    &hashdb.Fea{Fe0: root[0], Fe1: root[1], Fe2: root[2], Fe3: root[3]},
+		Key:     &hashdb.Fea{Fe0: k[0], Fe1: k[1], Fe2: k[2], Fe3: k[3]},
+		Details: true,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	value, err := string2fea(result.Value)
+	if err != nil {
+		return nil, err
+	}
+
+	levels := make([]uint64, 0, len(result.Siblings))
+	for level := range result.Siblings {
+		levels = append(levels, level)
+	}
+	sort.Slice(levels, func(i, j int) bool { return levels[i] < levels[j] })
+	siblings := make([][]byte, 0, len(levels))
+	for _, level := range levels {
+		siblings = append(siblings, siblingToByteSlice(result.Siblings[level].GetSibling()))
+	}
+
+	return &LeafProof{
+		Key:      key,
+		Value:    fea2scalar(value),
+		Siblings: siblings,
+	}, nil
+}
+
 func (tree *StateTree) getProgram(ctx context.Context, key []uint64) (*ProgramProof, error) {
 	result, err := tree.grpcClient.GetProgram(ctx, &hashdb.GetProgramRequest{
 		Key: &hashdb.Fea{Fe0: key[0], Fe1: key[1], Fe2: key[2], Fe3: key[3]},
@@ -1,5 +1,7 @@
 package merkletree
 
+import "math/big"
+
 // ResultCode represents the result code.
 type ResultCode int64
 
@@ -43,3 +45,27 @@ type ProgramProof struct {
 	// Data is the program proof data.
 	Data []byte
 }
+
+// LeafProof is the merkle proof of a leaf of the state tree.
+type LeafProof struct {
+	// Key is the leaf key.
+	Key []byte
+	// Value is the leaf value.
+	Value *big.Int
+	// Siblings are the siblings of each level of the tree, from the root to the leaf.
+	Siblings [][]byte
+}
+
+// AccountProof contains the merkle proofs of the leafs of an account.
+type AccountProof struct {
+	// Balance is the proof of the balance leaf.
+	Balance *LeafProof
+	// Nonce is the proof of the nonce leaf.
+	Nonce *LeafProof
+	// CodeHash is the proof of the code hash leaf.
+	CodeHash *LeafProof
+	// CodeLength is the proof of the code length leaf.
+	CodeLength *LeafProof
+	// Storage are the proofs of the requested storage positions.
+	Storage []*LeafProof
+}
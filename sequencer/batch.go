@@ -2,8 +2,13 @@ package sequencer
 
 import (
 	"context"
+	"crypto/rand"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"math"
+	"math/big"
+	"runtime"
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/event"
@@ -14,51 +19,134 @@ import (
 	"github.com/ethereum/go-ethereum/common"
 )
 
+const (
+	// isSyncedPollingInterval is the base time to wait between isSynced checks after opening a batch
+	isSyncedPollingInterval = time.Second
+	// isSyncedPollingMaxJitter is the max random time added to isSyncedPollingInterval
+	isSyncedPollingMaxJitter = 500 * time.Millisecond
+)
+
 // Batch represents a wip or processed batch.
 type Batch struct {
-	batchNumber        uint64
-	coinbase           common.Address
-	timestamp          time.Time
-	initialStateRoot   common.Hash // initial stateRoot of the batch
-	imStateRoot        common.Hash // intermediate stateRoot that is updated each time a single tx is processed
-	finalStateRoot     common.Hash // final stateroot of the batch when a L2 block is processed
-	localExitRoot      common.Hash
-	countOfTxs         int
-	remainingResources state.BatchResources
-	closingReason      state.ClosingReason
+	batchNumber          uint64
+	coinbase             common.Address
+	timestamp            time.Time
+	initialStateRoot     common.Hash // initial stateRoot of the batch
+	imStateRoot          common.Hash // intermediate stateRoot that is updated each time a single tx is processed
+	finalStateRoot       common.Hash // final stateroot of the batch when a L2 block is processed
+	localExitRoot        common.Hash
+	countOfTxs           int
+	txsGasUsed           []uint64   // gas used by each tx processed since the batch was opened or resumed
+	txsEffectiveGasPrice []*big.Int // effective gas price of each tx processed since the batch was opened or resumed
+	l1BlockNumber        uint64     // latest L1 block number known when the batch was opened
+	remainingResources   state.BatchResources
+	closingReason        state.ClosingReason
+	closingReasonSetAt   string // caller location where the closingReason was set
 }
 
 func (w *Batch) isEmpty() bool {
 	return w.countOfTxs == 0
 }
 
-// getLastStateRoot gets the state root from the latest batch
-func (f *finalizer) getLastStateRoot(ctx context.Context) (common.Hash, error) {
-	var oldStateRoot common.Hash
+// SetClosingReason sets the reason to close the batch. The closing reason can be set only once per batch,
+// if it's already set the first reason is kept, as the batch can be checked again for the same condition
+// while it's being closed
+func (w *Batch) SetClosingReason(r state.ClosingReason) {
+	setAt := callerLocation()
 
-	batches, err := f.state.GetLastNBatches(ctx, 2, nil) //nolint:gomnd
-	if err != nil {
-		return common.Hash{}, fmt.Errorf("failed to get last %d batches, err: %w", 2, err) //nolint:gomnd
+	if w.closingReason != state.EmptyClosingReason {
+		log.Debugf("closing reason of batch %d already set to %q at %s, keeping it instead of %q set at %s", w.batchNumber, w.closingReason, w.closingReasonSetAt, r, setAt)
+		return
+	}
+
+	log.Debugf("closing reason of batch %d set to %q at %s", w.batchNumber, r, setAt)
+	w.closingReason = r
+	w.closingReasonSetAt = setAt
+}
+
+// callerLocation returns the file:line of the caller of the function that calls callerLocation
+func callerLocation() string {
+	pc := make([]uintptr, 1)
+	// skip runtime.Callers, callerLocation and the function calling callerLocation
+	if runtime.Callers(3, pc) == 0 { //nolint:gomnd
+		return "unknown"
+	}
+
+	frame, _ := runtime.CallersFrames(pc).Next()
+	return fmt.Sprintf("%s:%d", frame.File, frame.Line)
+}
+
+// checkBatchGap returns a BatchGapError if the batches, sorted by batch number in descending order, are not consecutive
+func checkBatchGap(batches []*state.Batch) error {
+	for i := 1; i < len(batches); i++ {
+		if batches[i-1].BatchNumber > batches[i].BatchNumber+1 {
+			return &BatchGapError{From: batches[i].BatchNumber + 1, To: batches[i-1].BatchNumber - 1}
+		}
 	}
+	return nil
+}
 
-	if len(batches) == 1 { //nolint:gomnd
-		oldStateRoot = batches[0].StateRoot
-	} else if len(batches) == 2 { //nolint:gomnd
-		oldStateRoot = batches[1].StateRoot
+// waitForMissingBatches waits until the batches in the range [from, to] are present in the state.
+// It returns the ctx error if ctx is done before
+func (f *finalizer) waitForMissingBatches(ctx context.Context, from uint64, to uint64) error {
+	for batchNumber := from; batchNumber <= to; {
+		_, err := f.state.GetBatchByNumber(ctx, batchNumber, nil)
+		if err == nil {
+			batchNumber++
+			continue
+		}
+		if err != state.ErrNotFound {
+			log.Errorf("failed to get missing batch %d. Error: %v", batchNumber, err)
+		} else {
+			log.Infof("wait for synchronizer to sync missing batch %d", batchNumber)
+		}
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(time.Second):
+		}
 	}
+	return nil
+}
 
-	return oldStateRoot, nil
+// initWIPBatchWaitingForGaps inits the wip batch. If there is a gap in the last batches it waits for the
+// missing ones to be synced and retries instead of failing
+func (f *finalizer) initWIPBatchWaitingForGaps(ctx context.Context) error {
+	for {
+		err := f.initWIPBatch(ctx)
+		var gapErr *BatchGapError
+		if !errors.As(err, &gapErr) {
+			return err
+		}
+		log.Warnf("%s, waiting for the missing batches", gapErr)
+		if err := f.waitForMissingBatches(ctx, gapErr.From, gapErr.To); err != nil {
+			return err
+		}
+	}
 }
 
 // GetWIPBatch returns ready WIP batch
-func (f *finalizer) setWIPBatch(ctx context.Context, wipStateBatch *state.Batch) (*Batch, error) {
+func (f *finalizer) setWIPBatch(ctx context.Context, wipStateBatch *state.Batch) (_ *Batch, err error) {
 	dbTx, err := f.state.BeginStateTransaction(ctx)
 	if err != nil {
 		return nil, err
 	}
+	// The dbTx holds the lock of the prevStateBatch row, so it must be ended before returning
+	defer func() {
+		if err != nil {
+			if rollbackErr := dbTx.Rollback(ctx); rollbackErr != nil {
+				log.Errorf("failed to rollback dbTx when setting wip batch %d. Error: %v", wipStateBatch.BatchNumber, rollbackErr)
+			}
+			return
+		}
+		if commitErr := dbTx.Commit(ctx); commitErr != nil {
+			err = fmt.Errorf("failed to commit dbTx when setting wip batch %d. Error: %w", wipStateBatch.BatchNumber, commitErr)
+		}
+	}()
 
-	// Retrieve prevStateBatch to init the initialStateRoot of the wip batch
-	prevStateBatch, err := f.state.GetBatchByNumber(ctx, wipStateBatch.BatchNumber-1, dbTx)
+	// Retrieve prevStateBatch to init the initialStateRoot of the wip batch. It's read for update so
+	// we wait for any concurrent db transaction that is closing it and read its committed version
+	prevStateBatch, err := f.state.GetBatchByNumberForUpdate(ctx, wipStateBatch.BatchNumber-1, dbTx)
 	if err != nil {
 		return nil, err
 	}
@@ -74,8 +162,29 @@ func (f *finalizer) setWIPBatch(ctx context.Context, wipStateBatch *state.Batch)
 		wipStateBatchCountOfTxs = wipStateBatchCountOfTxs + len(rawBlock.Transactions)
 	}
 
-	remainingResources := getMaxRemainingResources(f.batchConstraints)
-	err = remainingResources.Sub(wipStateBatch.Resources)
+	// Resume from the used resources stored when the last L2 block of the wip batch was stored, if they
+	// are unknown we use the resources of the wip batch. The remaining resources are computed from the
+	// current constraints, so they are applied even if they have changed since the batch was opened
+	storedUsedResources, err := f.state.GetWIPBatchUsedResources(ctx, wipStateBatch.BatchNumber, dbTx)
+	if err != nil && err != state.ErrNotFound {
+		return nil, err
+	}
+
+	var usedResources state.BatchResources
+	if storedUsedResources != nil {
+		usedResources = *storedUsedResources
+	} else {
+		usedResources = wipStateBatch.Resources
+		if wipStateBatchCountOfTxs > 0 && usedResources.ZKCounters.IsZero() {
+			log.Warnf("wip batch %d has %d txs but its used zk counters are zero, remaining resources could be inaccurate", wipStateBatch.BatchNumber, wipStateBatchCountOfTxs)
+		}
+		// A zero used gas means it's unknown, we don't estimate it as the gas limit of the txs overstates it
+		if wipStateBatchCountOfTxs > 0 && usedResources.ZKCounters.GasUsed == 0 {
+			log.Warnf("used gas of wip batch %d is unknown, remaining gas could be inaccurate", wipStateBatch.BatchNumber)
+		}
+	}
+
+	remainingResources, err := getMaxRemainingResources(f.batchConstraints).Sub(usedResources)
 	if err != nil {
 		return nil, err
 	}
@@ -89,33 +198,37 @@ func (f *finalizer) setWIPBatch(ctx context.Context, wipStateBatch *state.Batch)
 		localExitRoot:      wipStateBatch.LocalExitRoot,
 		timestamp:          wipStateBatch.Timestamp,
 		countOfTxs:         wipStateBatchCountOfTxs,
+		l1BlockNumber:      wipStateBatch.L1BlockNumber,
 		remainingResources: remainingResources,
 	}
 
 	return wipBatch, nil
 }
 
-// initWIPBatch inits the wip batch
-func (f *finalizer) initWIPBatch(ctx context.Context) {
+// initWIPBatch inits the wip batch. It returns a BatchGapError if the last batches in the state are not consecutive
+func (f *finalizer) initWIPBatch(ctx context.Context) error {
 	for !f.isSynced(ctx) {
 		log.Info("wait for synchronizer to sync last batch")
 		time.Sleep(time.Second)
 	}
 
-	lastBatchNum, err := f.state.GetLastBatchNumber(ctx, nil)
+	// Get the last batches in trusted state, the previous one is needed to check there is no gap before the last one
+	lastStateBatches, err := f.state.GetLastNBatches(ctx, 2, nil) //nolint:gomnd
 	if err != nil {
-		log.Fatalf("failed to get last batch number. Error: %s", err)
+		return fmt.Errorf("failed to get last batches. Error: %w", err)
+	}
+	if len(lastStateBatches) == 0 {
+		return state.ErrStateNotSynchronized
 	}
 
-	// Get the last batch in trusted state
-	lastStateBatch, err := f.state.GetBatchByNumber(ctx, lastBatchNum, nil)
-	if err != nil {
-		log.Fatalf("failed to get last batch. Error: %s", err)
+	if err := checkBatchGap(lastStateBatches); err != nil {
+		return err
 	}
 
+	lastStateBatch := lastStateBatches[0]
 	isClosed := !lastStateBatch.WIP
 
-	log.Infof("batch %d isClosed: %v", lastBatchNum, isClosed)
+	log.Infof("batch %d isClosed: %v", lastStateBatch.BatchNumber, isClosed)
 
 	if isClosed { //if the last batch is close then open a new wip batch
 		// Get las GlobalExitRoot
@@ -125,17 +238,19 @@ func (f *finalizer) initWIPBatch(ctx context.Context) {
 
 		f.wipBatch, err = f.openNewWIPBatch(ctx, lastStateBatch.BatchNumber+1, lastGER, lastStateBatch.StateRoot, lastStateBatch.LocalExitRoot)
 		if err != nil {
-			log.Fatalf("failed to open new wip batch. Error: %s", err)
+			return fmt.Errorf("failed to open new wip batch. Error: %w", err)
 		}
 	} else { /// if it's not closed, it is the wip state batch, set it as wip batch in the finalizer
 		f.wipBatch, err = f.setWIPBatch(ctx, lastStateBatch)
 		if err != nil {
-			log.Fatalf("failed to set wip batch. Error: %s", err)
+			return fmt.Errorf("failed to set wip batch. Error: %w", err)
 		}
 	}
 
 	log.Infof("initial batch: %d, initialStateRoot: %s, stateRoot: %s, coinbase: %s, LER: %s",
 		f.wipBatch.batchNumber, f.wipBatch.initialStateRoot, f.wipBatch.finalStateRoot, f.wipBatch.coinbase, f.wipBatch.localExitRoot)
+
+	return nil
 }
 
 // finalizeBatch retries until successful closes the current batch and opens a new one, potentially processing forced batches between the batch is closed and the resulting new empty batch
@@ -255,10 +370,11 @@ func (f *finalizer) closeAndOpenNewWIPBatch(ctx context.Context) (*Batch, error)
 	}
 
 	// Subtract the L2 block used resources to wip batch
-	err = f.wipBatch.remainingResources.Sub(l2BlockUsedResources)
+	remainingResources, err := f.wipBatch.remainingResources.Sub(l2BlockUsedResources)
 	if err != nil {
 		return nil, fmt.Errorf("failed to subtract L2 block used resources to wip batch %d. Error: %s", f.wipBatch.batchNumber, err)
 	}
+	f.wipBatch.remainingResources = remainingResources
 
 	return batch, nil
 }
@@ -280,6 +396,26 @@ func (f *finalizer) openNewWIPBatch(ctx context.Context, batchNumber uint64, ger
 		return nil, fmt.Errorf("failed to begin state transaction to open batch, err: %w", err)
 	}
 
+	// Stamp the batch with the latest L1 block known
+	lastL1Block, err := f.state.GetLastBlock(ctx, dbTx)
+	if err != nil {
+		if rollbackErr := dbTx.Rollback(ctx); rollbackErr != nil {
+			return nil, fmt.Errorf("failed to rollback dbTx: %s. Error: %w", rollbackErr.Error(), err)
+		}
+		return nil, fmt.Errorf("failed to get last L1 block. Error: %w", err)
+	}
+	newStateBatch.L1BlockNumber = lastL1Block.BlockNumber
+
+	// Lock the previous batch, if it's being closed by a concurrent db transaction we wait for it to finish
+	// so the check that it's closed done when opening the new wip batch reads its committed version
+	_, err = f.state.GetBatchByNumberForUpdate(ctx, batchNumber-1, dbTx)
+	if err != nil {
+		if rollbackErr := dbTx.Rollback(ctx); rollbackErr != nil {
+			return nil, fmt.Errorf("failed to rollback dbTx: %s. Error: %w", rollbackErr.Error(), err)
+		}
+		return nil, fmt.Errorf("failed to lock previous batch %d. Error: %w", batchNumber-1, err)
+	}
+
 	// OpenBatch opens a new wip batch in the state
 	err = f.state.OpenWIPBatch(ctx, newStateBatch, dbTx)
 	if err != nil {
@@ -293,10 +429,11 @@ func (f *finalizer) openNewWIPBatch(ctx context.Context, batchNumber uint64, ger
 		return nil, fmt.Errorf("failed to commit database transaction for opening a wip batch. Error: %w", err)
 	}
 
-	// Check if synchronizer is up-to-date
+	// Check if synchronizer is up-to-date. A random jitter is added to the polling interval so the
+	// sequencer instances that open a batch at the same time spread their queries to the DB
 	for !f.isSynced(ctx) {
 		log.Info("wait for synchronizer to sync last batch")
-		time.Sleep(time.Second)
+		time.Sleep(isSyncedPollingIntervalWithJitter())
 	}
 
 	return &Batch{
@@ -307,6 +444,7 @@ func (f *finalizer) openNewWIPBatch(ctx context.Context, batchNumber uint64, ger
 		finalStateRoot:     newStateBatch.StateRoot,
 		timestamp:          newStateBatch.Timestamp,
 		localExitRoot:      newStateBatch.LocalExitRoot,
+		l1BlockNumber:      newStateBatch.L1BlockNumber,
 		remainingResources: getMaxRemainingResources(f.batchConstraints),
 		closingReason:      state.EmptyClosingReason,
 	}, err
@@ -328,6 +466,12 @@ func (f *finalizer) closeWIPBatch(ctx context.Context) error {
 		ClosingReason:  f.wipBatch.closingReason,
 	}
 
+	// The average effective gas price is only known if all the txs of the batch have been processed since the batch was opened,
+	// for a batch resumed after a restart the effective gas price of the txs processed before the restart is unknown
+	if len(f.wipBatch.txsEffectiveGasPrice) == f.wipBatch.countOfTxs {
+		receipt.EffectiveGasPrice = state.ComputeAverageEffectiveGasPrice(f.wipBatch.txsEffectiveGasPrice)
+	}
+
 	dbTx, err := f.state.BeginStateTransaction(ctx)
 	if err != nil {
 		return err
@@ -348,6 +492,21 @@ func (f *finalizer) closeWIPBatch(ctx context.Context) error {
 		}
 	}
 
+	if f.resourceThreshold != nil {
+		percentage := f.resourceThreshold.AddClosedBatch(getBatchFillRate(f.batchConstraints, usedResources))
+		metrics.ResourcePercentageToCloseBatch(float64(percentage))
+	}
+
+	f.publishBatchClosingEvent(BatchClosingEvent{
+		BatchNumber:   f.wipBatch.batchNumber,
+		ClosingReason: f.wipBatch.closingReason,
+		CountOfTxs:    f.wipBatch.countOfTxs,
+		UsedBytes:     usedResources.Bytes,
+		UsedGas:       usedResources.ZKCounters.GasUsed,
+		Duration:      time.Since(f.wipBatch.timestamp),
+		GasProfile:    state.ComputeGasProfile(f.wipBatch.txsGasUsed),
+	})
+
 	return nil
 }
 
@@ -355,7 +514,7 @@ func (f *finalizer) closeWIPBatch(ctx context.Context) error {
 func (f *finalizer) maxTxsPerBatchReached() bool {
 	if f.wipBatch.countOfTxs >= int(f.batchConstraints.MaxTxsPerBatch) {
 		log.Infof("closing batch: %d, because it reached the maximum number of txs.", f.wipBatch.batchNumber)
-		f.wipBatch.closingReason = state.BatchFullClosingReason
+		f.wipBatch.SetClosingReason(state.BatchFullClosingReason)
 		return true
 	}
 	return false
@@ -470,12 +629,16 @@ func (f *finalizer) reprocessFullBatch(ctx context.Context, batchNum uint64, ini
 
 // checkRemainingResources checks if the transaction uses less resources than the remaining ones in the batch.
 func (f *finalizer) checkRemainingResources(result *state.ProcessBatchResponse, tx *TxTracker) error {
+	if result.UsedZkCounters.IsZero() {
+		log.Warnf("executor returned zero used zk counters for tx %s", tx.HashStr)
+	}
+
 	usedResources := state.BatchResources{
 		ZKCounters: result.UsedZkCounters,
 		Bytes:      uint64(len(tx.RawTx)),
 	}
 
-	err := f.wipBatch.remainingResources.Sub(usedResources)
+	remainingResources, err := f.wipBatch.remainingResources.Sub(usedResources)
 	if err != nil {
 		log.Infof("current transaction exceeds the remaining batch resources, updating metadata for tx in worker and continuing")
 		start := time.Now()
@@ -483,6 +646,7 @@ func (f *finalizer) checkRemainingResources(result *state.ProcessBatchResponse,
 		metrics.WorkerProcessingTime(time.Since(start))
 		return err
 	}
+	f.wipBatch.remainingResources = remainingResources
 
 	return nil
 }
@@ -491,87 +655,103 @@ func (f *finalizer) checkRemainingResources(result *state.ProcessBatchResponse,
 func (f *finalizer) isBatchResourcesExhausted() bool {
 	resources := f.wipBatch.remainingResources
 	zkCounters := resources.ZKCounters
+	threshold := f.batchConstraints.CloseThreshold(f.getResourcePercentageToCloseBatch())
 	result := false
 	resourceDesc := ""
-	if resources.Bytes <= f.getConstraintThresholdUint64(f.batchConstraints.MaxBatchBytesSize) {
+	if resources.Bytes <= threshold.Bytes {
 		resourceDesc = "MaxBatchBytesSize"
 		result = true
-	} else if zkCounters.UsedSteps <= f.getConstraintThresholdUint32(f.batchConstraints.MaxSteps) {
+	} else if zkCounters.UsedSteps <= threshold.ZKCounters.UsedSteps {
 		resourceDesc = "MaxSteps"
 		result = true
-	} else if zkCounters.UsedPoseidonPaddings <= f.getConstraintThresholdUint32(f.batchConstraints.MaxPoseidonPaddings) {
+	} else if zkCounters.UsedPoseidonPaddings <= threshold.ZKCounters.UsedPoseidonPaddings {
 		resourceDesc = "MaxPoseidonPaddings"
 		result = true
-	} else if zkCounters.UsedBinaries <= f.getConstraintThresholdUint32(f.batchConstraints.MaxBinaries) {
+	} else if zkCounters.UsedBinaries <= threshold.ZKCounters.UsedBinaries {
 		resourceDesc = "MaxBinaries"
 		result = true
-	} else if zkCounters.UsedKeccakHashes <= f.getConstraintThresholdUint32(f.batchConstraints.MaxKeccakHashes) {
+	} else if zkCounters.UsedKeccakHashes <= threshold.ZKCounters.UsedKeccakHashes {
 		resourceDesc = "MaxKeccakHashes"
 		result = true
-	} else if zkCounters.UsedArithmetics <= f.getConstraintThresholdUint32(f.batchConstraints.MaxArithmetics) {
+	} else if zkCounters.UsedArithmetics <= threshold.ZKCounters.UsedArithmetics {
 		resourceDesc = "MaxArithmetics"
 		result = true
-	} else if zkCounters.UsedMemAligns <= f.getConstraintThresholdUint32(f.batchConstraints.MaxMemAligns) {
+	} else if zkCounters.UsedMemAligns <= threshold.ZKCounters.UsedMemAligns {
 		resourceDesc = "MaxMemAligns"
 		result = true
-	} else if zkCounters.GasUsed <= f.getConstraintThresholdUint64(f.batchConstraints.MaxCumulativeGasUsed) {
+	} else if zkCounters.GasUsed <= threshold.ZKCounters.GasUsed {
 		resourceDesc = "MaxCumulativeGasUsed"
 		result = true
-	} else if zkCounters.UsedSha256Hashes_V2 <= f.getConstraintThresholdUint32(f.batchConstraints.MaxSHA256Hashes) {
+	} else if zkCounters.UsedSha256Hashes_V2 <= threshold.ZKCounters.UsedSha256Hashes_V2 {
 		resourceDesc = "MaxSHA256Hashes"
 		result = true
 	}
 
 	if result {
 		log.Infof("closing batch %d, because it reached %s limit", f.wipBatch.batchNumber, resourceDesc)
-		f.wipBatch.closingReason = state.BatchAlmostFullClosingReason
+		f.wipBatch.SetClosingReason(state.BatchAlmostFullClosingReason)
 	}
 
 	return result
 }
 
-// getConstraintThresholdUint64 returns the threshold for the given input
-func (f *finalizer) getConstraintThresholdUint64(input uint64) uint64 {
-	return input * uint64(f.cfg.ResourcePercentageToCloseBatch) / 100 //nolint:gomnd
+// getResourcePercentageToCloseBatch returns the percentage of the resources left out for the batch to be closed
+func (f *finalizer) getResourcePercentageToCloseBatch() uint32 {
+	if f.resourceThreshold != nil {
+		return f.resourceThreshold.Percentage()
+	}
+	return f.cfg.ResourcePercentageToCloseBatch
 }
 
-// getConstraintThresholdUint32 returns the threshold for the given input
-func (f *finalizer) getConstraintThresholdUint32(input uint32) uint32 {
-	return input * f.cfg.ResourcePercentageToCloseBatch / 100 //nolint:gomnd
+// getMaxRemainingResources returns the remaining resources of a new batch. If any of the constraints is zero
+// it panics, or returns unlimited resources flagged as InvalidConstraints in production builds
+func getMaxRemainingResources(constraints state.BatchConstraintsCfg) state.BatchResources {
+	if zeroConstraints := constraints.ZeroConstraints(); len(zeroConstraints) > 0 {
+		return invalidConstraintsResources(zeroConstraints)
+	}
+	return constraints.ToMaxResources()
 }
 
-// getUsedBatchResources returns the max resources that can be used in a batch
-func getUsedBatchResources(constraints state.BatchConstraintsCfg, remainingResources state.BatchResources) state.BatchResources {
+// unlimitedBatchResources returns the remaining resources of a new batch whose constraints are invalid
+func unlimitedBatchResources() state.BatchResources {
 	return state.BatchResources{
 		ZKCounters: state.ZKCounters{
-			GasUsed:              constraints.MaxCumulativeGasUsed - remainingResources.ZKCounters.GasUsed,
-			UsedKeccakHashes:     constraints.MaxKeccakHashes - remainingResources.ZKCounters.UsedKeccakHashes,
-			UsedPoseidonHashes:   constraints.MaxPoseidonHashes - remainingResources.ZKCounters.UsedPoseidonHashes,
-			UsedPoseidonPaddings: constraints.MaxPoseidonPaddings - remainingResources.ZKCounters.UsedPoseidonPaddings,
-			UsedMemAligns:        constraints.MaxMemAligns - remainingResources.ZKCounters.UsedMemAligns,
-			UsedArithmetics:      constraints.MaxArithmetics - remainingResources.ZKCounters.UsedArithmetics,
-			UsedBinaries:         constraints.MaxBinaries - remainingResources.ZKCounters.UsedBinaries,
-			UsedSteps:            constraints.MaxSteps - remainingResources.ZKCounters.UsedSteps,
-			UsedSha256Hashes_V2:  constraints.MaxSHA256Hashes - remainingResources.ZKCounters.UsedSha256Hashes_V2,
+			GasUsed:              math.MaxUint64,
+			UsedKeccakHashes:     math.MaxUint32,
+			UsedPoseidonHashes:   math.MaxUint32,
+			UsedPoseidonPaddings: math.MaxUint32,
+			UsedMemAligns:        math.MaxUint32,
+			UsedArithmetics:      math.MaxUint32,
+			UsedBinaries:         math.MaxUint32,
+			UsedSteps:            math.MaxUint32,
+			UsedSha256Hashes_V2:  math.MaxUint32,
 		},
-		Bytes: constraints.MaxBatchBytesSize - remainingResources.Bytes,
+		Bytes:              math.MaxUint64,
+		InvalidConstraints: true,
 	}
 }
 
-// getMaxRemainingResources returns the max zkcounters that can be used in a batch
-func getMaxRemainingResources(constraints state.BatchConstraintsCfg) state.BatchResources {
+// getUsedBatchResources returns the resources used by a batch from its remaining resources. If the remaining
+// resources are flagged as InvalidConstraints, they are relative to the unlimited resources instead of the constraints
+func getUsedBatchResources(constraints state.BatchConstraintsCfg, remainingResources state.BatchResources) state.BatchResources {
+	maxResources := constraints.ToMaxResources()
+	if remainingResources.InvalidConstraints {
+		maxResources = unlimitedBatchResources()
+	}
 	return state.BatchResources{
-		ZKCounters: state.ZKCounters{
-			GasUsed:              constraints.MaxCumulativeGasUsed,
-			UsedKeccakHashes:     constraints.MaxKeccakHashes,
-			UsedPoseidonHashes:   constraints.MaxPoseidonHashes,
-			UsedPoseidonPaddings: constraints.MaxPoseidonPaddings,
-			UsedMemAligns:        constraints.MaxMemAligns,
-			UsedArithmetics:      constraints.MaxArithmetics,
-			UsedBinaries:         constraints.MaxBinaries,
-			UsedSteps:            constraints.MaxSteps,
-			UsedSha256Hashes_V2:  constraints.MaxSHA256Hashes,
-		},
-		Bytes: constraints.MaxBatchBytesSize,
+		ZKCounters: maxResources.ZKCounters.Diff(remainingResources.ZKCounters),
+		Bytes:      maxResources.Bytes - min(maxResources.Bytes, remainingResources.Bytes),
 	}
 }
+
+// isSyncedPollingIntervalWithJitter returns isSyncedPollingInterval plus a random jitter in [0, isSyncedPollingMaxJitter),
+// the jitter is generated using crypto/rand. If the random jitter can't be generated, no jitter is added
+func isSyncedPollingIntervalWithJitter() time.Duration {
+	jitterMs, err := rand.Int(rand.Reader, big.NewInt(isSyncedPollingMaxJitter.Milliseconds()))
+	if err != nil {
+		log.Warnf("failed to generate isSynced polling jitter, err: %v", err)
+		return isSyncedPollingInterval
+	}
+
+	return isSyncedPollingInterval + time.Duration(jitterMs.Int64())*time.Millisecond
+}
@@ -0,0 +1,48 @@
+package sequencer
+
+import (
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+// batchClosingEventsBufferSize is the size of the channel buffer of each batch closing subscriber
+const batchClosingEventsBufferSize = 100
+
+// BatchClosingEvent contains the information of a batch closed by the finalizer
+type BatchClosingEvent struct {
+	BatchNumber   uint64
+	ClosingReason state.ClosingReason
+	CountOfTxs    int
+	UsedBytes     uint64
+	UsedGas       uint64
+	Duration      time.Duration
+	// GasProfile is computed only from the txs processed by the finalizer since the batch was opened or resumed
+	GasProfile state.BatchGasProfile
+}
+
+// SubscribeToBatchClosing returns a channel where a BatchClosingEvent is sent each time the finalizer closes a batch.
+// The events are discarded for a subscriber that doesn't consume them fast enough, to not block the finalizer
+func (f *finalizer) SubscribeToBatchClosing() <-chan BatchClosingEvent {
+	f.batchClosingSubscribersMux.Lock()
+	defer f.batchClosingSubscribersMux.Unlock()
+
+	subscriber := make(chan BatchClosingEvent, batchClosingEventsBufferSize)
+	f.batchClosingSubscribers = append(f.batchClosingSubscribers, subscriber)
+	return subscriber
+}
+
+// publishBatchClosingEvent sends the batch closing event to all the subscribers
+func (f *finalizer) publishBatchClosingEvent(event BatchClosingEvent) {
+	f.batchClosingSubscribersMux.Lock()
+	defer f.batchClosingSubscribersMux.Unlock()
+
+	for _, subscriber := range f.batchClosingSubscribers {
+		select {
+		case subscriber <- event:
+		default:
+			log.Warnf("batch closing event for batch %d discarded, subscriber channel is full", event.BatchNumber)
+		}
+	}
+}
@@ -29,6 +29,10 @@ type Config struct {
 	// L2ReorgRetrievalInterval is the time the sequencer waits to check if a state inconsistency has happened
 	L2ReorgRetrievalInterval types.Duration `mapstructure:"L2ReorgRetrievalInterval"`
 
+	// WorkerQueueDepthWarnThreshold is the number of txs ready to be processed in the worker
+	// from which a warning is logged, if zero the warning is disabled
+	WorkerQueueDepthWarnThreshold uint64 `mapstructure:"WorkerQueueDepthWarnThreshold"`
+
 	// Finalizer's specific config properties
 	Finalizer FinalizerCfg `mapstructure:"Finalizer"`
 
@@ -62,6 +66,9 @@ type FinalizerCfg struct {
 	// ResourcePercentageToCloseBatch is the percentage window of the resource left out for the batch to be closed
 	ResourcePercentageToCloseBatch uint32 `mapstructure:"ResourcePercentageToCloseBatch"`
 
+	// AdaptiveResourceThreshold contains the configuration to adjust dynamically the ResourcePercentageToCloseBatch
+	AdaptiveResourceThreshold AdaptiveResourceThresholdCfg `mapstructure:"AdaptiveResourceThreshold"`
+
 	// GERFinalityNumberOfBlocks is number of blocks to consider GER final
 	GERFinalityNumberOfBlocks uint64 `mapstructure:"GERFinalityNumberOfBlocks"`
 
@@ -89,10 +96,58 @@ type FinalizerCfg struct {
 	// L2BlockTime is the resolution of the timestamp used to close a L2 block
 	L2BlockTime types.Duration `mapstructure:"L2BlockTime"`
 
+	// BatchFillRateController contains the configuration to adjust dynamically the L2BlockTime
+	BatchFillRateController BatchFillRateControllerCfg `mapstructure:"BatchFillRateController"`
+
 	// StopSequencerOnBatchNum specifies the batch number where the Sequencer will stop to process more transactions and generate new batches. The Sequencer will halt after it closes the batch equal to this number
 	StopSequencerOnBatchNum uint64 `mapstructure:"StopSequencerOnBatchNum"`
 
 	// SequentialReprocessFullBatch indicates if the reprocess of a closed batch (sanity check) must be done in a
 	// sequential way (instead than in parallel)
 	SequentialReprocessFullBatch bool `mapstructure:"SequentialReprocessFullBatch"`
+
+	// HaltWebhookURL is the URL where the finalizer sends an HTTP POST with the halt event as JSON body
+	// before halting, if empty no request is sent
+	HaltWebhookURL string `mapstructure:"HaltWebhookURL"`
+
+	// ReplayProtectionWindowBatches is the number of last batches checked to avoid adding again to the wip batch
+	// a tx already included in one of them (regular or forced batch), 0 disables it
+	ReplayProtectionWindowBatches uint64 `mapstructure:"ReplayProtectionWindowBatches"`
+}
+
+// AdaptiveResourceThresholdCfg contains the configuration of the adaptive percentage of the resources left out to close a batch
+type AdaptiveResourceThresholdCfg struct {
+	// Enabled indicates if the ResourcePercentageToCloseBatch is adjusted dynamically based on the fill rate of the last closed batches
+	Enabled bool `mapstructure:"Enabled"`
+
+	// NumOfBatches is the number of last closed batches used to compute the average fill rate
+	NumOfBatches uint32 `mapstructure:"NumOfBatches"`
+
+	// Step is the amount the percentage is increased or decreased each time it is adjusted
+	Step uint32 `mapstructure:"Step"`
+
+	// MinResourcePercentage is the min value the percentage can be adjusted to
+	MinResourcePercentage uint32 `mapstructure:"MinResourcePercentage"`
+
+	// MaxResourcePercentage is the max value the percentage can be adjusted to
+	MaxResourcePercentage uint32 `mapstructure:"MaxResourcePercentage"`
+}
+
+// BatchFillRateControllerCfg contains the configuration of the dynamic time between L2 blocks based on the fill rate of the wip batch
+type BatchFillRateControllerCfg struct {
+	// Enabled indicates if the L2BlockTime is adjusted dynamically based on the fill rate of the wip batch
+	Enabled bool `mapstructure:"Enabled"`
+
+	// TargetFillRate is the fill rate (between 0 and 1) of the batches to target. The L2BlockTime is increased when the
+	// wip batch fill rate is below half of this value and decreased when it's above the midpoint between this value and 1
+	TargetFillRate float64 `mapstructure:"TargetFillRate"`
+
+	// Step is the amount the L2BlockTime is increased or decreased each time it is adjusted
+	Step types.Duration `mapstructure:"Step"`
+
+	// MinL2BlockTime is the min value the L2BlockTime can be adjusted to
+	MinL2BlockTime types.Duration `mapstructure:"MinL2BlockTime"`
+
+	// MaxL2BlockTime is the max value the L2BlockTime can be adjusted to
+	MaxL2BlockTime types.Duration `mapstructure:"MaxL2BlockTime"`
 }
@@ -0,0 +1,16 @@
+//go:build !production
+
+package sequencer
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+// invalidConstraintsResources panics so batch constraints built with missing fields (i.e. in tests) fail
+// where they are used instead of producing batches with no room for any tx
+func invalidConstraintsResources(zeroConstraints []string) state.BatchResources {
+	panic(fmt.Sprintf("batch constraints %s are zero", strings.Join(zeroConstraints, ", ")))
+}
@@ -0,0 +1,17 @@
+//go:build production
+
+package sequencer
+
+import (
+	"strings"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+// invalidConstraintsResources returns unlimited resources flagged as InvalidConstraints, so the
+// batch resources are only limited by the executor OOC errors
+func invalidConstraintsResources(zeroConstraints []string) state.BatchResources {
+	log.Errorf("batch constraints %s are zero, batch resources will not be limited", strings.Join(zeroConstraints, ", "))
+	return unlimitedBatchResources()
+}
@@ -0,0 +1,37 @@
+//go:build production
+
+package sequencer
+
+import (
+	"math"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetMaxRemainingResources(t *testing.T) {
+	assert.Equal(t, bc.ToMaxResources(), getMaxRemainingResources(bc))
+
+	constraints := bc
+	constraints.MaxSteps = 0
+	resources := getMaxRemainingResources(constraints)
+	assert.True(t, resources.InvalidConstraints)
+	assert.Equal(t, uint64(math.MaxUint64), resources.Bytes)
+	assert.Equal(t, uint32(math.MaxUint32), resources.ZKCounters.UsedSteps)
+}
+
+func TestGetUsedBatchResourcesWithInvalidConstraints(t *testing.T) {
+	constraints := bc
+	constraints.MaxSteps = 0
+	used := state.BatchResources{
+		ZKCounters: state.ZKCounters{GasUsed: 1000, UsedKeccakHashes: 2, UsedSteps: 300},
+		Bytes:      100,
+	}
+	remainingResources, err := getMaxRemainingResources(constraints).Sub(used)
+	require.NoError(t, err)
+	require.True(t, remainingResources.InvalidConstraints)
+
+	assert.Equal(t, used, getUsedBatchResources(constraints, remainingResources))
+}
@@ -0,0 +1,19 @@
+//go:build !production
+
+package sequencer
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestGetMaxRemainingResources(t *testing.T) {
+	assert.Equal(t, bc.ToMaxResources(), getMaxRemainingResources(bc))
+
+	constraints := bc
+	constraints.MaxSteps = 0
+	assert.PanicsWithValue(t, "batch constraints MaxSteps are zero", func() {
+		getMaxRemainingResources(constraints)
+	})
+}
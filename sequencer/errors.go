@@ -1,6 +1,9 @@
 package sequencer
 
-import "errors"
+import (
+	"errors"
+	"fmt"
+)
 
 var (
 	// ErrExpiredTransaction happens when the transaction is expired
@@ -10,6 +13,8 @@ var (
 	// ErrDuplicatedNonce is returned when adding a new tx to the worker and there is an existing tx
 	// with the same nonce and higher gasPrice (in this case we keep the existing tx)
 	ErrDuplicatedNonce = errors.New("duplicated nonce")
+	// ErrDuplicateTransaction is returned when a tx has already been added to one of the last batches
+	ErrDuplicateTransaction = errors.New("duplicate transaction")
 	// ErrReplacedTransaction is returned when an existing tx is replaced by a new tx with the same nonce and higher gasPrice
 	ErrReplacedTransaction = errors.New("replaced transaction")
 	// ErrGetBatchByNumber happens when we get an error trying to get a batch by number (GetBatchByNumber)
@@ -30,3 +35,14 @@ var (
 	// ErrTransactionsListEmpty happens when txSortedList is empty
 	ErrTransactionsListEmpty = errors.New("transactions list empty")
 )
+
+// BatchGapError is returned when the last batches in the state are not consecutive,
+// From and To are the first and last missing batch numbers
+type BatchGapError struct {
+	From uint64
+	To   uint64
+}
+
+func (e *BatchGapError) Error() string {
+	return fmt.Sprintf("missing batches from %d to %d in the state", e.From, e.To)
+}
@@ -0,0 +1,68 @@
+package sequencer
+
+import (
+	"sync"
+	"time"
+)
+
+// BatchFillRateController adjusts the time between L2 blocks to target a fill rate of the batches.
+// When the wip batch fill rate is below half of the target the L2 block time is increased (block
+// production is slowed down) and when it is above the midpoint between the target and a full batch
+// the L2 block time is decreased (block production is sped up)
+type BatchFillRateController struct {
+	cfg   BatchFillRateControllerCfg
+	delay time.Duration
+	mutex sync.Mutex
+}
+
+// NewBatchFillRateController creates a new BatchFillRateController starting at the provided L2 block time
+func NewBatchFillRateController(cfg BatchFillRateControllerCfg, initialDelay time.Duration) *BatchFillRateController {
+	c := &BatchFillRateController{
+		cfg:   cfg,
+		delay: initialDelay,
+	}
+	c.delay = c.bound(c.delay)
+	return c
+}
+
+// Delay returns the current time between L2 blocks
+func (c *BatchFillRateController) Delay() time.Duration {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	return c.delay
+}
+
+// Update adjusts the time between L2 blocks based on the fill rate of the wip batch.
+// Returns the time between L2 blocks to use from now on
+func (c *BatchFillRateController) Update(fillRate float64) time.Duration {
+	c.mutex.Lock()
+	defer c.mutex.Unlock()
+
+	lowFillRate := c.cfg.TargetFillRate / 2                           //nolint:gomnd
+	highFillRate := c.cfg.TargetFillRate + (1-c.cfg.TargetFillRate)/2 //nolint:gomnd
+
+	step := c.cfg.Step.Duration
+	if fillRate < lowFillRate {
+		c.delay = c.bound(c.delay + step)
+	} else if fillRate > highFillRate {
+		if c.delay > step {
+			c.delay = c.bound(c.delay - step)
+		} else {
+			c.delay = c.bound(0)
+		}
+	}
+
+	return c.delay
+}
+
+// bound returns the delay limited to the configured min and max values
+func (c *BatchFillRateController) bound(delay time.Duration) time.Duration {
+	if delay < c.cfg.MinL2BlockTime.Duration {
+		return c.cfg.MinL2BlockTime.Duration
+	}
+	if c.cfg.MaxL2BlockTime.Duration > 0 && delay > c.cfg.MaxL2BlockTime.Duration {
+		return c.cfg.MaxL2BlockTime.Duration
+	}
+	return delay
+}
@@ -0,0 +1,47 @@
+package sequencer
+
+import (
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/config/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchFillRateController(t *testing.T) {
+	cfg := BatchFillRateControllerCfg{
+		Enabled:        true,
+		TargetFillRate: 0.8,
+		Step:           types.NewDuration(time.Second),
+		MinL2BlockTime: types.NewDuration(time.Second),
+		MaxL2BlockTime: types.NewDuration(5 * time.Second),
+	}
+
+	type testCase struct {
+		name          string
+		fillRate      float64
+		expectedDelay time.Duration
+	}
+
+	testCases := []testCase{
+		{name: "low fill rate slows down block production", fillRate: 0.1, expectedDelay: 4 * time.Second},
+		{name: "max delay is not exceeded", fillRate: 0.39, expectedDelay: 5 * time.Second},
+		{name: "fill rate around the target keeps delay", fillRate: 0.8, expectedDelay: 5 * time.Second},
+		{name: "high fill rate speeds up block production", fillRate: 0.95, expectedDelay: 4 * time.Second},
+		{name: "high fill rate again", fillRate: 0.91, expectedDelay: 3 * time.Second},
+		{name: "high fill rate once more", fillRate: 1, expectedDelay: 2 * time.Second},
+		{name: "high fill rate reaches min delay", fillRate: 1, expectedDelay: 1 * time.Second},
+		{name: "min delay is not exceeded", fillRate: 1, expectedDelay: 1 * time.Second},
+	}
+
+	controller := NewBatchFillRateController(cfg, 3*time.Second)
+	assert.Equal(t, 3*time.Second, controller.Delay())
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			delay := controller.Update(tc.fillRate)
+			assert.Equal(t, tc.expectedDelay, delay)
+			assert.Equal(t, tc.expectedDelay, controller.Delay())
+		})
+	}
+}
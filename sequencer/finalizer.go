@@ -1,10 +1,13 @@
 package sequencer
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
 	"sync"
 	"sync/atomic"
 	"time"
@@ -21,10 +24,12 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime"
 	"github.com/0xPolygonHermez/zkevm-node/state/runtime/executor"
 	"github.com/ethereum/go-ethereum/common"
+	"go.opentelemetry.io/otel/codes"
 )
 
 const (
 	pendingL2BlocksBufferSize = 100
+	haltWebhookTimeout        = 10 * time.Second
 	changeL2BlockSize         = 9 //1 byte (tx type = 0B) + 4 bytes for deltaTimestamp + 4 for l1InfoTreeIndex
 )
 
@@ -84,6 +89,15 @@ type finalizer struct {
 	// stream server
 	streamServer *datastreamer.StreamServer
 	dataToStream chan statePackage.DSL2FullBlock
+	// adaptive percentage of the resources left out to close a batch, nil if disabled
+	resourceThreshold *AdaptiveResourceThreshold
+	// dynamic time between L2 blocks based on the wip batch fill rate, nil if disabled
+	fillRateController *BatchFillRateController
+	// hashes of the txs added to the last batches to detect duplicated txs, nil if disabled
+	replayProtection *BatchReplayProtection
+	// subscribers to the batch closing events
+	batchClosingSubscribers    []chan BatchClosingEvent
+	batchClosingSubscribersMux *sync.Mutex
 }
 
 // newFinalizer returns a new instance of Finalizer.
@@ -137,6 +151,22 @@ func newFinalizer(
 		// stream server
 		streamServer: streamServer,
 		dataToStream: dataToStream,
+		// batch closing events
+		batchClosingSubscribersMux: new(sync.Mutex),
+	}
+
+	if cfg.AdaptiveResourceThreshold.Enabled {
+		f.resourceThreshold = NewAdaptiveResourceThreshold(cfg.AdaptiveResourceThreshold, cfg.ResourcePercentageToCloseBatch)
+		metrics.ResourcePercentageToCloseBatch(float64(f.resourceThreshold.Percentage()))
+	}
+
+	if cfg.BatchFillRateController.Enabled {
+		f.fillRateController = NewBatchFillRateController(cfg.BatchFillRateController, cfg.L2BlockTime.Duration)
+		metrics.L2BlockTime(f.fillRateController.Delay())
+	}
+
+	if cfg.ReplayProtectionWindowBatches > 0 {
+		f.replayProtection = NewBatchReplayProtection(cfg.ReplayProtectionWindowBatches, batchConstraints.MaxTxsPerBatch)
 	}
 
 	f.haltFinalizer.Store(false)
@@ -155,7 +185,13 @@ func (f *finalizer) Start(ctx context.Context) {
 	go f.checkL1InfoTreeUpdate(ctx)
 
 	// Get the last batch if still wip or opens a new one
-	f.initWIPBatch(ctx)
+	if err := f.initWIPBatchWaitingForGaps(ctx); err != nil {
+		if ctx.Err() != nil {
+			log.Infof("finalizer stopped while initializing the wip batch. Error: %v", err)
+			return
+		}
+		log.Fatalf("failed to init wip batch. Error: %s", err)
+	}
 
 	// Initializes the wip L2 block
 	f.initWIPL2Block(ctx)
@@ -251,6 +287,7 @@ func (f *finalizer) checkL1InfoTreeUpdate(ctx context.Context) {
 			f.lastL1InfoTreeMux.Lock()
 			f.lastL1InfoTree = l1InfoRoot
 			f.lastL1InfoTreeMux.Unlock()
+			metrics.LatestGERTimestamp(l1InfoRoot.Timestamp)
 
 			if !f.lastL1InfoTreeValid {
 				f.lastL1InfoTreeCond.L.Lock()
@@ -275,7 +312,8 @@ func (f *finalizer) finalizeBatches(ctx context.Context) {
 		}
 
 		// We have reached the L2 block time, we need to close the current L2 block and open a new one
-		if !f.wipL2Block.timestamp.Add(f.cfg.L2BlockTime.Duration).After(time.Now()) {
+		if !f.wipL2Block.timestamp.Add(f.getL2BlockTime()).After(time.Now()) {
+			f.updateL2BlockTime()
 			f.finalizeL2Block(ctx)
 		}
 
@@ -300,6 +338,9 @@ func (f *finalizer) finalizeBatches(ctx context.Context) {
 						firstTxProcess = false
 						log.Info("reprocessing tx because of effective gas price calculation: %s", tx.Hash.Hex())
 						continue
+					} else if err == ErrDuplicateTransaction {
+						// The duplicated tx has already been deleted from the worker and marked as failed in the pool
+						break
 					} else {
 						log.Errorf("failed to process transaction in finalizeBatches, Err: %v", err)
 						break
@@ -380,6 +421,22 @@ func (f *finalizer) processTransaction(ctx context.Context, tx *TxTracker, first
 		executorBatchRequest.SkipFirstChangeL2Block_V2 = true
 	}
 
+	if tx != nil && f.replayProtection != nil {
+		if err := f.replayProtection.CheckTx(tx.Hash, f.wipBatch.batchNumber); err != nil {
+			// The tx has already been included in a recent batch (i.e. a forced batch), we delete it from the worker
+			// and mark it as failed in the pool
+			log.Warnf("tx %s already included in one of the last %d batches, deleting it from the worker", tx.HashStr, f.cfg.ReplayProtectionWindowBatches)
+			f.worker.DeleteTx(tx.Hash, tx.From)
+			failedReason := err.Error()
+			if updateErr := f.pool.UpdateTxStatus(ctx, tx.Hash, poolPackage.TxStatusFailed, false, &failedReason); updateErr != nil {
+				log.Errorf("failed to update status to failed in the pool for tx: %s, err: %s", tx.Hash.String(), updateErr)
+			} else {
+				metrics.TxProcessed(metrics.TxProcessedLabelFailed, 1)
+			}
+			return nil, err
+		}
+	}
+
 	hashStr := "nil"
 	if tx != nil {
 		executorBatchRequest.Transactions = append(executorBatchRequest.Transactions, tx.RawTx...)
@@ -462,7 +519,15 @@ func (f *finalizer) processTransaction(ctx context.Context, tx *TxTracker, first
 	log.Infof("processing tx: %s. Batch.BatchNumber: %d, batchNumber: %d, oldStateRoot: %s, txHash: %s, L1InfoRootIndex: %d",
 		hashStr, f.wipBatch.batchNumber, executorBatchRequest.BatchNumber, executorBatchRequest.OldStateRoot, hashStr, f.wipL2Block.l1InfoTreeExitRoot.L1InfoTreeIndex)
 
-	processBatchResponse, err := f.state.ProcessBatchV2(ctx, executorBatchRequest, false)
+	spanCtx, span := startProcessTransactionSpan(ctx, tx, f.wipBatch.batchNumber)
+	processBatchResponse, err := f.state.ProcessBatchV2(spanCtx, executorBatchRequest, false)
+	if err == nil {
+		addUsedZkCountersEvent(span, processBatchResponse.UsedZkCounters)
+	} else {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+	}
+	span.End()
 
 	if err != nil && errors.Is(err, runtime.ErrExecutorDBError) {
 		log.Errorf("failed to process transaction: %s", err)
@@ -574,8 +639,17 @@ func (f *finalizer) handleProcessTransactionResponse(ctx context.Context, tx *Tx
 		tx.EGPLog.GasPrice, tx.EGPLog.L1GasPrice, tx.EGPLog.L2GasPrice, tx.EGPLog.Reprocess, tx.EGPLog.GasPriceOC, tx.EGPLog.BalanceOC, egpEnabled, len(tx.RawTx), tx.HashStr, tx.EGPLog.Error)
 
 	f.wipL2Block.addTx(tx)
+	if f.replayProtection != nil {
+		f.replayProtection.AddTx(tx.Hash, f.wipBatch.batchNumber)
+	}
 
 	f.wipBatch.countOfTxs++
+	f.wipBatch.txsGasUsed = append(f.wipBatch.txsGasUsed, result.BlockResponses[0].TransactionResponses[0].GasUsed)
+	if effectiveGasPrice, ok := new(big.Int).SetString(result.BlockResponses[0].TransactionResponses[0].EffectiveGasPrice, 0); ok {
+		f.wipBatch.txsEffectiveGasPrice = append(f.wipBatch.txsEffectiveGasPrice, effectiveGasPrice)
+	} else {
+		log.Warnf("failed to convert effective gas price %s of tx %s to big.Int", result.BlockResponses[0].TransactionResponses[0].EffectiveGasPrice, tx.HashStr)
+	}
 
 	f.updateWorkerAfterSuccessfulProcessing(ctx, tx.Hash, tx.From, false, result)
 
@@ -731,7 +805,7 @@ func (f *finalizer) isDeadlineEncountered() bool {
 	// Timestamp resolution deadline
 	if !f.wipBatch.isEmpty() && f.wipBatch.timestamp.Add(f.cfg.TimestampResolution.Duration).Before(time.Now()) {
 		log.Infof("closing batch %d, because of timestamp resolution.", f.wipBatch.batchNumber)
-		f.wipBatch.closingReason = state.TimeoutResolutionDeadlineClosingReason
+		f.wipBatch.SetClosingReason(state.TimeoutResolutionDeadlineClosingReason)
 		return true
 	}
 	return false
@@ -758,24 +832,70 @@ func (f *finalizer) checkIfProverRestarted(proverID string) {
 	}
 }
 
-// Halt halts the finalizer
-func (f *finalizer) Halt(ctx context.Context, err error) {
-	f.haltFinalizer.Store(true)
+// sendHaltAlert stores the halt event in the event log and sends it to the HaltWebhookURL (if configured).
+// The alert is sent even if ctx is already cancelled, as the halt is usually caused by an error in the ctx scope
+func (f *finalizer) sendHaltAlert(ctx context.Context, haltReason error) {
+	ctx = context.WithoutCancel(ctx)
 
-	event := &event.Event{
+	haltEvent := &event.Event{
 		ReceivedAt:  time.Now(),
 		Source:      event.Source_Node,
 		Component:   event.Component_Sequencer,
 		Level:       event.Level_Critical,
 		EventID:     event.EventID_FinalizerHalt,
-		Description: fmt.Sprintf("finalizer halted due to error: %s", err),
+		Description: fmt.Sprintf("finalizer halted due to error: %s", haltReason),
 	}
 
-	eventErr := f.eventLog.LogEvent(ctx, event)
-	if eventErr != nil {
-		log.Errorf("error storing finalizer halt event: %v", eventErr)
+	err := f.eventLog.LogEvent(ctx, haltEvent)
+	if err != nil {
+		log.Errorf("error storing finalizer halt event: %v", err)
 	}
 
+	if f.cfg.HaltWebhookURL == "" {
+		return
+	}
+
+	err = postHaltEvent(ctx, f.cfg.HaltWebhookURL, haltEvent)
+	if err != nil {
+		log.Errorf("error sending finalizer halt event to webhook %s: %v", f.cfg.HaltWebhookURL, err)
+	}
+}
+
+// postHaltEvent sends an HTTP POST to the url with the haltEvent as JSON body
+func postHaltEvent(ctx context.Context, url string, haltEvent *event.Event) error {
+	body, err := json.Marshal(haltEvent)
+	if err != nil {
+		return err
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, haltWebhookTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	res, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return err
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < http.StatusOK || res.StatusCode >= http.StatusMultipleChoices {
+		return fmt.Errorf("unexpected status code %d", res.StatusCode)
+	}
+
+	return nil
+}
+
+// Halt halts the finalizer
+func (f *finalizer) Halt(ctx context.Context, err error) {
+	f.haltFinalizer.Store(true)
+
+	f.sendHaltAlert(ctx, err)
+
 	for {
 		log.Errorf("halting the finalizer, fatal error: %s", err)
 		time.Sleep(5 * time.Second) //nolint:gomnd
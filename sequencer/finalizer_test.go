@@ -2,9 +2,12 @@ package sequencer
 
 import (
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"math/big"
+	"net/http"
+	"net/http/httptest"
 	"sync"
 	"testing"
 	"time"
@@ -50,6 +53,8 @@ var (
 		MaxBinaries:          473170,
 		MaxSteps:             7570538,
 		MaxSHA256Hashes:      1596,
+
+		MaxForcedBatchesPerIteration: 10,
 	}
 	cfg = FinalizerCfg{
 		GERDeadlineTimeout: cfgTypes.Duration{
@@ -416,7 +421,7 @@ func TestFinalizer_handleProcessTransactionResponse(t *testing.T) {
 		initialStateRoot:   newHash,
 		stateRoot:          newHash,
 		timestamp:          now(),
-		remainingResources: getMaxRemainingResources(f.batchConstraints),
+		remainingResources: f.batchConstraints.ToMaxResources(),
 	}
 	closeBatchParams := ClosingBatchParameters{
 		BatchNumber:          f.wipBatch.batchNumber,
@@ -878,7 +883,8 @@ func TestFinalizer_openWIPBatch(t *testing.T) {
 		initialStateRoot:   oldHash,
 		imStateRoot:        oldHash,
 		timestamp:          now(),
-		remainingResources: getMaxRemainingResources(f.batchConstraints),
+		l1BlockNumber:      1,
+		remainingResources: f.batchConstraints.ToMaxResources(),
 	}
 	testCases := []struct {
 		name         string
@@ -924,6 +930,8 @@ func TestFinalizer_openWIPBatch(t *testing.T) {
 			// arrange
 			stateMock.On("BeginStateTransaction", ctx).Return(dbTxMock, tc.beginTxErr).Once()
 			if tc.beginTxErr == nil {
+				stateMock.On("GetLastBlock", ctx, dbTxMock).Return(&state.Block{BlockNumber: 1}, nil).Once()
+				stateMock.On("GetBatchByNumberForUpdate", ctx, batchNum-1, dbTxMock).Return(&state.Batch{BatchNumber: batchNum - 1}, nil).Once()
 				stateMock.On("OpenBatch", ctx, mock.Anything, dbTxMock).Return(tc.openBatchErr).Once()
 			}
 
@@ -953,16 +961,153 @@ func TestFinalizer_openWIPBatch(t *testing.T) {
 	}
 }
 
+func TestFinalizer_processForcedBatchWithoutTxs(t *testing.T) {
+	// arrange
+	f = setupFinalizer(false)
+	lastBatchNumber := uint64(5)
+	newBatchNumber := lastBatchNumber + 1
+	forcedBatch := state.ForcedBatch{
+		ForcedBatchNumber: 2,
+		GlobalExitRoot:    common.HexToHash("0x1"),
+		RawTxsData:        []byte{},
+		ForcedAt:          testNow(),
+	}
+	gerUpdateBlock := &state.ProcessBlockResponse{BlockNumber: 10, GlobalExitRoot: forcedBatch.GlobalExitRoot}
+	batchResponse := &state.ProcessBatchResponse{
+		NewStateRoot:   newHash,
+		NewBatchNumber: newBatchNumber,
+		BlockResponses: []*state.ProcessBlockResponse{gerUpdateBlock},
+	}
+
+	stateMock.On("BeginStateTransaction", ctx).Return(dbTxMock, nil).Once()
+	stateMock.On("GetBlockByNumber", ctx, forcedBatch.ForcedBatchNumber, dbTxMock).Return(&state.Block{ParentHash: oldHash}, nil).Once()
+	stateMock.On("OpenBatch", ctx, mock.Anything, dbTxMock).Return(nil).Once()
+	stateMock.On("GetForkIDByBatchNumber", mock.Anything).Return(uint64(state.FORKID_ETROG))
+	stateMock.On("ProcessBatchV2", ctx, mock.MatchedBy(func(request state.ProcessRequest) bool {
+		return len(request.Transactions) == 0 && request.L1InfoRoot_V2 == forcedBatch.GlobalExitRoot
+	}), true).Return(batchResponse, nil).Once()
+	stateMock.On("CloseBatch", ctx, mock.MatchedBy(func(receipt state.ProcessingReceipt) bool {
+		return receipt.BatchNumber == newBatchNumber && len(receipt.BatchL2Data) == 0 && receipt.BatchResources.Bytes == 0 &&
+			receipt.ClosingReason == state.ForcedBatchClosingReason
+	}), dbTxMock).Return(nil).Once()
+	stateMock.On("StoreL2Block", ctx, newBatchNumber, gerUpdateBlock, mock.Anything, dbTxMock).Return(nil).Once()
+	dbTxMock.On("Commit", ctx).Return(nil).Once()
+
+	// act
+	batchNumber, stateRoot, err := f.processForcedBatch(ctx, forcedBatch, lastBatchNumber, oldHash)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, newBatchNumber, batchNumber)
+	assert.Equal(t, newHash, stateRoot)
+	stateMock.AssertExpectations(t)
+	dbTxMock.AssertExpectations(t)
+}
+
+func TestFinalizer_processForcedBatchesMaxPerIteration(t *testing.T) {
+	// arrange
+	f = setupFinalizer(false)
+	f.batchConstraints.MaxForcedBatchesPerIteration = 1
+	defer func() { f.batchConstraints.MaxForcedBatchesPerIteration = bc.MaxForcedBatchesPerIteration }()
+	lastBatchNumber := uint64(5)
+	newBatchNumber := lastBatchNumber + 1
+	forcedBatches := []state.ForcedBatch{
+		{ForcedBatchNumber: 2, GlobalExitRoot: common.HexToHash("0x1"), RawTxsData: []byte{}, ForcedAt: testNow()},
+		{ForcedBatchNumber: 3, GlobalExitRoot: common.HexToHash("0x1"), RawTxsData: []byte{}, ForcedAt: testNow()},
+	}
+	f.nextForcedBatches = forcedBatches
+	f.nextForcedBatchDeadline = 0
+	gerUpdateBlock := &state.ProcessBlockResponse{BlockNumber: 10, GlobalExitRoot: forcedBatches[0].GlobalExitRoot}
+	batchResponse := &state.ProcessBatchResponse{
+		NewStateRoot:   newHash,
+		NewBatchNumber: newBatchNumber,
+		BlockResponses: []*state.ProcessBlockResponse{gerUpdateBlock},
+	}
+
+	stateMock.On("GetLastTrustedForcedBatchNumber", ctx, nil).Return(uint64(1), nil).Once()
+	stateMock.On("BeginStateTransaction", ctx).Return(dbTxMock, nil).Once()
+	stateMock.On("GetBlockByNumber", ctx, forcedBatches[0].ForcedBatchNumber, dbTxMock).Return(&state.Block{ParentHash: oldHash}, nil).Once()
+	stateMock.On("OpenBatch", ctx, mock.Anything, dbTxMock).Return(nil).Once()
+	stateMock.On("GetForkIDByBatchNumber", mock.Anything).Return(uint64(state.FORKID_ETROG))
+	stateMock.On("ProcessBatchV2", ctx, mock.Anything, true).Return(batchResponse, nil).Once()
+	stateMock.On("CloseBatch", ctx, mock.Anything, dbTxMock).Return(nil).Once()
+	stateMock.On("StoreL2Block", ctx, newBatchNumber, gerUpdateBlock, mock.Anything, dbTxMock).Return(nil).Once()
+	dbTxMock.On("Commit", ctx).Return(nil).Once()
+
+	// act
+	batchNumber, stateRoot := f.processForcedBatches(ctx, lastBatchNumber, oldHash)
+
+	// assert
+	assert.Equal(t, newBatchNumber, batchNumber)
+	assert.Equal(t, newHash, stateRoot)
+	assert.Equal(t, forcedBatches[1:], f.nextForcedBatches)
+	assert.NotZero(t, f.nextForcedBatchDeadline)
+	stateMock.AssertExpectations(t)
+	dbTxMock.AssertExpectations(t)
+}
+
+func TestFinalizer_processTransactionDuplicate(t *testing.T) {
+	// arrange
+	f = setupFinalizer(true)
+	ctx = context.Background()
+	f.wipL2Block = &L2Block{timestamp: now()}
+	f.replayProtection = NewBatchReplayProtection(10, bc.MaxTxsPerBatch)
+	tx := &TxTracker{Hash: common.HexToHash("0x1"), HashStr: "0x1", From: seqAddr}
+	f.replayProtection.AddTx(tx.Hash, f.wipBatch.batchNumber)
+	failedReason := ErrDuplicateTransaction.Error()
+
+	stateMock.On("GetForkIDByBatchNumber", f.wipBatch.batchNumber).Return(uint64(state.FORKID_ETROG)).Once()
+	stateMock.On("BuildChangeL2Block", mock.Anything, mock.Anything).Return([]byte{}).Once()
+	workerMock.On("DeleteTx", tx.Hash, tx.From).Return().Once()
+	poolMock.On("UpdateTxStatus", ctx, tx.Hash, pool.TxStatusFailed, false, &failedReason).Return(nil).Once()
+
+	// act
+	_, err := f.processTransaction(ctx, tx, true)
+
+	// assert
+	assert.ErrorIs(t, err, ErrDuplicateTransaction)
+	stateMock.AssertExpectations(t)
+	workerMock.AssertExpectations(t)
+	poolMock.AssertExpectations(t)
+}
+
+func TestBatch_SetClosingReason(t *testing.T) {
+	wipBatch := &Batch{batchNumber: 1, closingReason: state.EmptyClosingReason}
+
+	wipBatch.SetClosingReason(state.BatchFullClosingReason)
+	assert.Equal(t, state.BatchFullClosingReason, wipBatch.closingReason)
+	assert.Contains(t, wipBatch.closingReasonSetAt, "finalizer_test.go:")
+
+	setAt := wipBatch.closingReasonSetAt
+
+	// The first closing reason is kept
+	assert.NotPanics(t, func() { wipBatch.SetClosingReason(state.BatchAlmostFullClosingReason) })
+	assert.Equal(t, state.BatchFullClosingReason, wipBatch.closingReason)
+	assert.Equal(t, setAt, wipBatch.closingReasonSetAt)
+}
+
+func TestIsSyncedPollingIntervalWithJitter(t *testing.T) {
+	for i := 0; i < 100; i++ {
+		interval := isSyncedPollingIntervalWithJitter()
+		assert.GreaterOrEqual(t, interval, isSyncedPollingInterval)
+		assert.Less(t, interval, isSyncedPollingInterval+isSyncedPollingMaxJitter)
+	}
+}
+
 // TestFinalizer_closeBatch tests the closeBatch method.
 func TestFinalizer_closeWIPBatch(t *testing.T) {
 	// arrange
 	f = setupFinalizer(true)
+	f.wipBatch.countOfTxs = 2
+	f.wipBatch.txsGasUsed = []uint64{21000, 51000}
+	f.wipBatch.txsEffectiveGasPrice = []*big.Int{big.NewInt(1000000000), big.NewInt(2000000000)}
 	usedResources := getUsedBatchResources(f.batchConstraints, f.wipBatch.remainingResources)
 
 	receipt := state.ProcessingReceipt{
-		BatchNumber:    f.wipBatch.batchNumber,
-		BatchResources: usedResources,
-		ClosingReason:  f.wipBatch.closingReason,
+		BatchNumber:       f.wipBatch.batchNumber,
+		BatchResources:    usedResources,
+		ClosingReason:     f.wipBatch.closingReason,
+		EffectiveGasPrice: big.NewInt(1500000000),
 	}
 
 	managerErr := fmt.Errorf("some err")
@@ -985,6 +1130,8 @@ func TestFinalizer_closeWIPBatch(t *testing.T) {
 		},
 	}
 
+	batchClosingEvents := f.SubscribeToBatchClosing()
+
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// arrange
@@ -1006,11 +1153,86 @@ func TestFinalizer_closeWIPBatch(t *testing.T) {
 				assert.ErrorIs(t, err, tc.managerErr)
 			} else {
 				assert.NoError(t, err)
+				event := <-batchClosingEvents
+				assert.Equal(t, f.wipBatch.batchNumber, event.BatchNumber)
+				assert.Equal(t, f.wipBatch.closingReason, event.ClosingReason)
+				assert.Equal(t, usedResources.Bytes, event.UsedBytes)
+				assert.Equal(t, usedResources.ZKCounters.GasUsed, event.UsedGas)
+				assert.Equal(t, state.BatchGasProfile{MinGas: 21000, MaxGas: 51000, AvgGas: 36000, TotalGas: 72000}, event.GasProfile)
 			}
+			assert.Empty(t, batchClosingEvents)
 		})
 	}
 }
 
+func TestFinalizer_closeWIPBatchResumed(t *testing.T) {
+	// arrange
+	f = setupFinalizer(true)
+	// The batch was resumed after a restart, so the effective gas price of the first tx is unknown
+	f.wipBatch.countOfTxs = 2
+	f.wipBatch.txsEffectiveGasPrice = []*big.Int{big.NewInt(1000000000)}
+	receipt := state.ProcessingReceipt{
+		BatchNumber:    f.wipBatch.batchNumber,
+		BatchResources: getUsedBatchResources(f.batchConstraints, f.wipBatch.remainingResources),
+		ClosingReason:  f.wipBatch.closingReason,
+	}
+	stateMock.On("BeginStateTransaction", ctx).Return(dbTxMock, nilErr).Once()
+	stateMock.On("CloseWIPBatch", ctx, receipt, mock.Anything).Return(nilErr).Once()
+	dbTxMock.On("Commit", ctx).Return(nilErr).Once()
+
+	// act
+	err := f.closeWIPBatch(ctx)
+
+	// assert
+	assert.NoError(t, err)
+	stateMock.AssertExpectations(t)
+	dbTxMock.AssertExpectations(t)
+}
+
+// haltEventStorage stores the events logged and the error of the context used to log them
+type haltEventStorage struct {
+	events  []*event.Event
+	ctxErrs []error
+}
+
+func (s *haltEventStorage) LogEvent(ctx context.Context, ev *event.Event) error {
+	s.events = append(s.events, ev)
+	s.ctxErrs = append(s.ctxErrs, ctx.Err())
+	return nil
+}
+
+func TestFinalizer_sendHaltAlert(t *testing.T) {
+	// arrange
+	var webhookEvent event.Event
+	webhook := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		assert.NoError(t, json.NewDecoder(r.Body).Decode(&webhookEvent))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer webhook.Close()
+
+	f = setupFinalizer(false)
+	f.cfg.HaltWebhookURL = webhook.URL
+	storage := &haltEventStorage{}
+	f.eventLog = event.NewEventLog(event.Config{}, storage)
+
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	// act
+	f.sendHaltAlert(cancelledCtx, errors.New("some err"))
+
+	// assert
+	require.Len(t, storage.events, 1)
+	assert.NoError(t, storage.ctxErrs[0])
+	assert.Equal(t, event.Level_Critical, storage.events[0].Level)
+	assert.Equal(t, event.EventID_FinalizerHalt, storage.events[0].EventID)
+	assert.Equal(t, "finalizer halted due to error: some err", storage.events[0].Description)
+	assert.Equal(t, storage.events[0].EventID, webhookEvent.EventID)
+	assert.Equal(t, storage.events[0].Description, webhookEvent.Description)
+}
+
 func TestFinalizer_isDeadlineEncountered(t *testing.T) {
 	// arrange
 	f = setupFinalizer(true)
@@ -1140,6 +1362,7 @@ func TestFinalizer_checkRemainingResources(t *testing.T) {
 			if tc.expectedErr != nil {
 				assert.Error(t, err)
 				assert.EqualError(t, err, tc.expectedErr.Error())
+				assert.Equal(t, tc.remaining, f.wipBatch.remainingResources)
 			} else {
 				assert.NoError(t, err)
 			}
@@ -1895,60 +2118,153 @@ func TestFinalizer_reprocessFullBatch(t *testing.T) {
 	}
 }
 
-func TestFinalizer_getLastStateRoot(t *testing.T) {
-	f = setupFinalizer(false)
+func TestFinalizer_setWIPBatchUsedResources(t *testing.T) {
+	storedUsedResources := state.BatchResources{ZKCounters: state.ZKCounters{GasUsed: 21000, UsedSteps: 1000}, Bytes: 100}
+	wipStateBatchResources := state.BatchResources{ZKCounters: state.ZKCounters{GasUsed: 42000, UsedSteps: 2000}, Bytes: 200}
+
 	testCases := []struct {
-		name              string
-		mockBatches       []*state.Batch
-		mockError         error
-		expectedStateRoot common.Hash
-		expectedError     error
+		name                string
+		storedUsedResources *state.BatchResources
+		expectedUsed        state.BatchResources
 	}{
 		{
-			name: "Success with two batches",
-			mockBatches: []*state.Batch{
-				{BatchNumber: 2, StateRoot: common.BytesToHash([]byte("stateRoot2"))},
-				{BatchNumber: 1, StateRoot: common.BytesToHash([]byte("stateRoot1"))},
-			},
-			mockError:         nil,
-			expectedStateRoot: common.BytesToHash([]byte("stateRoot1")),
-			expectedError:     nil,
+			name:                "stored used resources",
+			storedUsedResources: &storedUsedResources,
+			expectedUsed:        storedUsedResources,
 		},
 		{
-			name: "Success with one batch",
-			mockBatches: []*state.Batch{
-				{BatchNumber: 1, StateRoot: common.BytesToHash([]byte("stateRoot1"))},
-			},
-			mockError:         nil,
-			expectedStateRoot: common.BytesToHash([]byte("stateRoot1")),
-			expectedError:     nil,
-		},
-		{
-			name:              "Error while getting batches",
-			mockBatches:       nil,
-			mockError:         errors.New("database err"),
-			expectedStateRoot: common.Hash{},
-			expectedError:     errors.New("failed to get last 2 batches, err: database err"),
+			name:                "unknown used resources",
+			storedUsedResources: nil,
+			expectedUsed:        wipStateBatchResources,
 		},
 	}
 
 	for _, tc := range testCases {
 		t.Run(tc.name, func(t *testing.T) {
 			// arrange
-			stateMock.On("GetLastNBatches", context.Background(), uint(2), nil).Return(tc.mockBatches, tc.mockError).Once()
+			f = setupFinalizer(false)
+			ctx = context.Background()
+			wipStateBatch := &state.Batch{
+				BatchNumber: 2,
+				Coinbase:    seqAddr,
+				StateRoot:   newHash,
+				Timestamp:   now(),
+				BatchL2Data: []byte{},
+				Resources:   wipStateBatchResources,
+			}
+			stateMock.On("BeginStateTransaction", ctx).Return(dbTxMock, nilErr).Once()
+			stateMock.On("GetBatchByNumberForUpdate", ctx, uint64(1), dbTxMock).Return(&state.Batch{BatchNumber: 1, StateRoot: oldHash}, nilErr).Once()
+			stateMock.On("GetWIPBatchUsedResources", ctx, uint64(2), dbTxMock).Return(tc.storedUsedResources, nilErr).Once()
+			dbTxMock.On("Commit", ctx).Return(nilErr).Once()
 
 			// act
-			stateRoot, err := f.getLastStateRoot(context.Background())
+			wipBatch, err := f.setWIPBatch(ctx, wipStateBatch)
 
 			// assert
-			assert.Equal(t, tc.expectedStateRoot, stateRoot)
-			if tc.expectedError != nil {
-				assert.EqualError(t, err, tc.expectedError.Error())
-			} else {
-				assert.NoError(t, err)
-			}
-
+			require.NoError(t, err)
+			// The remaining resources are computed from the current constraints
+			expectedRemaining, err := bc.ToMaxResources().Sub(tc.expectedUsed)
+			require.NoError(t, err)
+			assert.Equal(t, expectedRemaining, wipBatch.remainingResources)
+			assert.Equal(t, oldHash, wipBatch.initialStateRoot)
 			stateMock.AssertExpectations(t)
+			dbTxMock.AssertExpectations(t)
+		})
+	}
+}
+
+func TestFinalizer_initWIPBatchWaitingForGaps(t *testing.T) {
+	// arrange
+	f = setupFinalizer(false)
+	ctx = context.Background()
+	f.lastL1InfoTreeMux = new(sync.Mutex)
+	stateRoot := common.HexToHash("0x5")
+	localExitRoot := common.HexToHash("0x6")
+
+	// batches 3 and 4 are missing in the last batches, batch 3 is already synced when it's checked
+	// and batch 4 is synced after waiting for it
+	stateMock.On("GetLastNBatches", ctx, uint(2), nil).Return([]*state.Batch{
+		{BatchNumber: 5},
+		{BatchNumber: 2},
+	}, nil).Once()
+	stateMock.On("GetBatchByNumber", ctx, uint64(3), nil).Return(&state.Batch{BatchNumber: 3}, nil).Once()
+	stateMock.On("GetBatchByNumber", ctx, uint64(4), nil).Return(nil, state.ErrNotFound).Once()
+	stateMock.On("GetBatchByNumber", ctx, uint64(4), nil).Return(&state.Batch{BatchNumber: 4}, nil).Once()
+
+	// the last batch is closed, so a new wip batch is opened
+	stateMock.On("GetLastNBatches", ctx, uint(2), nil).Return([]*state.Batch{
+		{BatchNumber: 5, StateRoot: stateRoot, LocalExitRoot: localExitRoot},
+		{BatchNumber: 4},
+	}, nil).Once()
+	stateMock.On("BeginStateTransaction", ctx).Return(dbTxMock, nil).Once()
+	stateMock.On("GetLastBlock", ctx, dbTxMock).Return(&state.Block{BlockNumber: 1}, nil).Once()
+	stateMock.On("GetBatchByNumberForUpdate", ctx, uint64(5), dbTxMock).Return(&state.Batch{BatchNumber: 5, StateRoot: stateRoot}, nil).Once()
+	stateMock.On("OpenWIPBatch", ctx, mock.MatchedBy(func(batch state.Batch) bool {
+		return batch.BatchNumber == 6 && batch.StateRoot == stateRoot && batch.LocalExitRoot == localExitRoot
+	}), dbTxMock).Return(nil).Once()
+	dbTxMock.On("Commit", ctx).Return(nil).Once()
+
+	// act
+	err := f.initWIPBatchWaitingForGaps(ctx)
+
+	// assert
+	require.NoError(t, err)
+	assert.Equal(t, uint64(6), f.wipBatch.batchNumber)
+	assert.Equal(t, stateRoot, f.wipBatch.initialStateRoot)
+	stateMock.AssertExpectations(t)
+	dbTxMock.AssertExpectations(t)
+}
+
+func TestFinalizer_waitForMissingBatchesContextDone(t *testing.T) {
+	// arrange
+	f = setupFinalizer(false)
+	cancelledCtx, cancel := context.WithCancel(context.Background())
+	cancel()
+	stateMock.On("GetBatchByNumber", cancelledCtx, uint64(3), nil).Return(nil, state.ErrNotFound).Once()
+
+	// act
+	err := f.waitForMissingBatches(cancelledCtx, 3, 4)
+
+	// assert
+	require.ErrorIs(t, err, context.Canceled)
+	stateMock.AssertExpectations(t)
+}
+
+func TestCheckBatchGap(t *testing.T) {
+	testCases := []struct {
+		name          string
+		batches       []*state.Batch
+		expectedError error
+	}{
+		{
+			name:          "No batches",
+			batches:       nil,
+			expectedError: nil,
+		},
+		{
+			name:          "Consecutive batches",
+			batches:       []*state.Batch{{BatchNumber: 3}, {BatchNumber: 2}, {BatchNumber: 1}},
+			expectedError: nil,
+		},
+		{
+			name:          "One missing batch",
+			batches:       []*state.Batch{{BatchNumber: 3}, {BatchNumber: 1}},
+			expectedError: &BatchGapError{From: 2, To: 2},
+		},
+		{
+			name:          "Several missing batches",
+			batches:       []*state.Batch{{BatchNumber: 10}, {BatchNumber: 9}, {BatchNumber: 4}},
+			expectedError: &BatchGapError{From: 5, To: 8},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			err := checkBatchGap(tc.batches)
+			assert.Equal(t, tc.expectedError, err)
+
+			var gapErr *BatchGapError
+			assert.Equal(t, tc.expectedError != nil, errors.As(err, &gapErr))
 		})
 	}
 }
@@ -1963,7 +2279,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is ready - MaxBatchBytesSize",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.Bytes = f.getConstraintThresholdUint64(bc.MaxBatchBytesSize) - 1
+				resources.Bytes = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).Bytes - 1
 				return resources
 			},
 			expectedResult: true,
@@ -1971,7 +2287,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is NOT ready - MaxBatchBytesSize",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.Bytes = f.getConstraintThresholdUint64(bc.MaxBatchBytesSize) + 1
+				resources.Bytes = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).Bytes + 1
 				return resources
 			},
 			expectedResult: false,
@@ -1979,7 +2295,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is ready - MaxCumulativeGasUsed",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.ZKCounters.GasUsed = f.getConstraintThresholdUint64(bc.MaxCumulativeGasUsed) - 1
+				resources.ZKCounters.GasUsed = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).ZKCounters.GasUsed - 1
 				return resources
 			},
 			expectedResult: true,
@@ -1987,7 +2303,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is NOT ready - MaxCumulativeGasUsed",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.ZKCounters.GasUsed = f.getConstraintThresholdUint64(bc.MaxCumulativeGasUsed) + 1
+				resources.ZKCounters.GasUsed = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).ZKCounters.GasUsed + 1
 				return resources
 			},
 			expectedResult: false,
@@ -1995,7 +2311,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is ready - MaxSteps",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.ZKCounters.UsedSteps = f.getConstraintThresholdUint32(bc.MaxSteps) - 1
+				resources.ZKCounters.UsedSteps = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).ZKCounters.UsedSteps - 1
 				return resources
 			},
 			expectedResult: true,
@@ -2003,7 +2319,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is NOT ready - MaxSteps",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.ZKCounters.UsedSteps = f.getConstraintThresholdUint32(bc.MaxSteps) + 1
+				resources.ZKCounters.UsedSteps = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).ZKCounters.UsedSteps + 1
 				return resources
 			},
 			expectedResult: false,
@@ -2011,7 +2327,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is ready - MaxPoseidonPaddings",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.ZKCounters.UsedPoseidonPaddings = f.getConstraintThresholdUint32(bc.MaxPoseidonPaddings) - 1
+				resources.ZKCounters.UsedPoseidonPaddings = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).ZKCounters.UsedPoseidonPaddings - 1
 				return resources
 			},
 			expectedResult: true,
@@ -2019,7 +2335,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is NOT ready - MaxPoseidonPaddings",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.ZKCounters.UsedPoseidonPaddings = f.getConstraintThresholdUint32(bc.MaxPoseidonPaddings) + 1
+				resources.ZKCounters.UsedPoseidonPaddings = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).ZKCounters.UsedPoseidonPaddings + 1
 				return resources
 			},
 			expectedResult: false,
@@ -2027,7 +2343,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is ready - MaxBinaries",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.ZKCounters.UsedBinaries = f.getConstraintThresholdUint32(bc.MaxBinaries) - 1
+				resources.ZKCounters.UsedBinaries = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).ZKCounters.UsedBinaries - 1
 				return resources
 			},
 			expectedResult: true,
@@ -2035,7 +2351,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is NOT ready - MaxBinaries",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.ZKCounters.UsedBinaries = f.getConstraintThresholdUint32(bc.MaxBinaries) + 1
+				resources.ZKCounters.UsedBinaries = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).ZKCounters.UsedBinaries + 1
 				return resources
 			},
 			expectedResult: false,
@@ -2043,7 +2359,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is ready - MaxKeccakHashes",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.ZKCounters.UsedKeccakHashes = f.getConstraintThresholdUint32(bc.MaxKeccakHashes) - 1
+				resources.ZKCounters.UsedKeccakHashes = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).ZKCounters.UsedKeccakHashes - 1
 				return resources
 			},
 			expectedResult: true,
@@ -2051,7 +2367,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is NOT ready - MaxKeccakHashes",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.ZKCounters.UsedKeccakHashes = f.getConstraintThresholdUint32(bc.MaxKeccakHashes) + 1
+				resources.ZKCounters.UsedKeccakHashes = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).ZKCounters.UsedKeccakHashes + 1
 				return resources
 			},
 			expectedResult: false,
@@ -2059,7 +2375,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is ready - MaxArithmetics",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.ZKCounters.UsedArithmetics = f.getConstraintThresholdUint32(bc.MaxArithmetics) - 1
+				resources.ZKCounters.UsedArithmetics = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).ZKCounters.UsedArithmetics - 1
 				return resources
 			},
 			expectedResult: true,
@@ -2067,7 +2383,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is NOT ready - MaxArithmetics",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.ZKCounters.UsedArithmetics = f.getConstraintThresholdUint32(bc.MaxArithmetics) + 1
+				resources.ZKCounters.UsedArithmetics = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).ZKCounters.UsedArithmetics + 1
 				return resources
 			},
 			expectedResult: false,
@@ -2075,7 +2391,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is ready - MaxMemAligns",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.ZKCounters.UsedMemAligns = f.getConstraintThresholdUint32(bc.MaxMemAligns) - 1
+				resources.ZKCounters.UsedMemAligns = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).ZKCounters.UsedMemAligns - 1
 				return resources
 			},
 			expectedResult: true,
@@ -2083,7 +2399,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is NOT ready - MaxMemAligns",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.ZKCounters.UsedMemAligns = f.getConstraintThresholdUint32(bc.MaxMemAligns) + 1
+				resources.ZKCounters.UsedMemAligns = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).ZKCounters.UsedMemAligns + 1
 				return resources
 			},
 			expectedResult: false,
@@ -2091,7 +2407,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is ready - MaxSHA256Hashes",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.ZKCounters.UsedSha256Hashes_V2 = f.getConstraintThresholdUint32(bc.MaxSHA256Hashes) - 1
+				resources.ZKCounters.UsedSha256Hashes_V2 = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).ZKCounters.UsedSha256Hashes_V2 - 1
 				return resources
 			},
 			expectedResult: true,
@@ -2099,7 +2415,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		{
 			name: "Is NOT ready - MaxSHA256Hashes",
 			modifyResourceFunc: func(resources state.BatchResources) state.BatchResources {
-				resources.ZKCounters.UsedSha256Hashes_V2 = f.getConstraintThresholdUint32(bc.MaxSHA256Hashes) + 1
+				resources.ZKCounters.UsedSha256Hashes_V2 = bc.CloseThreshold(f.cfg.ResourcePercentageToCloseBatch).ZKCounters.UsedSha256Hashes_V2 + 1
 				return resources
 			},
 			expectedResult: false,
@@ -2110,7 +2426,7 @@ func TestFinalizer_isBatchAlmostFull(t *testing.T) {
 		t.Run(tc.name, func(t *testing.T) {
 			// arrange
 			f = setupFinalizer(true)
-			maxRemainingResource := getMaxRemainingResources(bc)
+			maxRemainingResource := bc.ToMaxResources()
 			f.wipBatch.remainingResources = tc.modifyResourceFunc(maxRemainingResource)
 
 			// act
@@ -2143,35 +2459,9 @@ func TestFinalizer_setNextForcedBatchDeadline(t *testing.T) {
 	assert.Equal(t, expected, f.nextForcedBatchDeadline)
 }
 
-func TestFinalizer_getConstraintThresholdUint64(t *testing.T) {
-	// arrange
-	f = setupFinalizer(false)
-	input := uint64(100)
-	expect := input * uint64(f.cfg.ResourcePercentageToCloseBatch) / 100
-
-	// act
-	result := f.getConstraintThresholdUint64(input)
-
-	// assert
-	assert.Equal(t, result, expect)
-}
-
-func TestFinalizer_getConstraintThresholdUint32(t *testing.T) {
-	// arrange
-	f = setupFinalizer(false)
-	input := uint32(100)
-	expect := input * f.cfg.ResourcePercentageToCloseBatch / 100
-
-	// act
-	result := f.getConstraintThresholdUint32(input)
-
-	// assert
-	assert.Equal(t, result, expect)
-}
-
 func TestFinalizer_getRemainingResources(t *testing.T) {
 	// act
-	remainingResources := getMaxRemainingResources(bc)
+	remainingResources := bc.ToMaxResources()
 
 	// assert
 	assert.Equal(t, remainingResources.ZKCounters.GasUsed, bc.MaxCumulativeGasUsed)
@@ -2184,6 +2474,7 @@ func TestFinalizer_getRemainingResources(t *testing.T) {
 	assert.Equal(t, remainingResources.ZKCounters.UsedSteps, bc.MaxSteps)
 	assert.Equal(t, remainingResources.ZKCounters.UsedSha256Hashes_V2, bc.MaxSHA256Hashes)
 	assert.Equal(t, remainingResources.Bytes, bc.MaxBatchBytesSize)
+	assert.True(t, getUsedBatchResources(bc, remainingResources).ZKCounters.IsZero())
 }
 
 func Test_isBatchFull(t *testing.T) {
@@ -2273,7 +2564,7 @@ func setupFinalizer(withWipBatch bool) *finalizer {
 			imStateRoot:        newHash,
 			localExitRoot:      newHash,
 			timestamp:          now(),
-			remainingResources: getMaxRemainingResources(bc),
+			remainingResources: bc.ToMaxResources(),
 			closingReason:      state.EmptyClosingReason,
 		}
 	}
@@ -2305,5 +2596,6 @@ func setupFinalizer(withWipBatch bool) *finalizer {
 		proverID:                   "",
 		lastPendingFlushID:         0,
 		pendingFlushIDCond:         sync.NewCond(new(sync.Mutex)),
+		batchClosingSubscribersMux: new(sync.Mutex),
 	}
 }
@@ -25,7 +25,16 @@ func (f *finalizer) processForcedBatches(ctx context.Context, lastBatchNumber ui
 	}
 	nextForcedBatchNumber := lastForcedBatchNumber + 1
 
-	for _, forcedBatch := range f.nextForcedBatches {
+	processedForcedBatches := 0
+	// Forced batches left to be processed in the next call due to the MaxForcedBatchesPerIteration limit
+	pendingForcedBatches := make([]state.ForcedBatch, 0)
+
+	for i, forcedBatch := range f.nextForcedBatches {
+		if processedForcedBatches >= int(f.batchConstraints.MaxForcedBatchesPerIteration) {
+			pendingForcedBatches = append(pendingForcedBatches, f.nextForcedBatches[i:]...)
+			break
+		}
+
 		forcedBatchToProcess := forcedBatch
 		// Skip already processed forced batches
 		if forcedBatchToProcess.ForcedBatchNumber < nextForcedBatchNumber {
@@ -50,9 +59,17 @@ func (f *finalizer) processForcedBatches(ctx context.Context, lastBatchNumber ui
 
 		log.Infof("processed forced batch %d, BatchNumber: %d, NewStateRoot: %s", forcedBatchToProcess.ForcedBatchNumber, lastBatchNumber, stateRoot.String())
 
+		processedForcedBatches++
+
 		nextForcedBatchNumber += 1
 	}
-	f.nextForcedBatches = make([]state.ForcedBatch, 0)
+	f.nextForcedBatches = pendingForcedBatches
+
+	if len(pendingForcedBatches) > 0 {
+		log.Infof("max forced batches per iteration (%d) reached, %d forced batches will be processed in the next batch",
+			f.batchConstraints.MaxForcedBatchesPerIteration, len(pendingForcedBatches))
+		f.setNextForcedBatchDeadline()
+	}
 
 	return lastBatchNumber, stateRoot
 }
@@ -81,6 +98,12 @@ func (f *finalizer) processForcedBatch(ctx context.Context, forcedBatch state.Fo
 
 	newBatchNumber := lastBatchNumber + 1
 
+	// A forced batch without txs is still executed, the executor creates a single L2 block that only updates
+	// the GER. It can't be skipped since its state root must match the one computed by the synchronizer
+	if len(forcedBatch.RawTxsData) == 0 {
+		log.Infof("forced batch %d has no txs, processing it to update the GER to %s", forcedBatch.ForcedBatchNumber, forcedBatch.GlobalExitRoot.String())
+	}
+
 	// Open new batch on state for the forced batch
 	processingCtx := state.ProcessingContext{
 		BatchNumber:    newBatchNumber,
@@ -130,14 +153,12 @@ func (f *finalizer) processForcedBatch(ctx context.Context, forcedBatch state.Fo
 		return rollbackOnError(fmt.Errorf("[processForcedBatch] error closing state batch %d for forced batch %d. Error: %w", newBatchNumber, forcedBatch.ForcedBatchNumber, err))
 	}
 
-	err = dbTx.Commit(ctx)
-	if err != nil {
-		return rollbackOnError(fmt.Errorf("[processForcedBatch] error when commit dbTx when processing forced batch %d. Error: %w", forcedBatch.ForcedBatchNumber, err))
-	}
-
+	// Store the L2 blocks of the forced batch (the GER update block if it has no txs) in the same dbTx
 	if len(batchResponse.BlockResponses) > 0 && !batchResponse.IsRomOOCError {
 		err = f.handleProcessForcedBatchResponse(ctx, batchResponse, dbTx)
-		return rollbackOnError(fmt.Errorf("[processForcedBatch] error when handling batch response for forced batch %d. Error: %w", forcedBatch.ForcedBatchNumber, err))
+		if err != nil {
+			return rollbackOnError(fmt.Errorf("[processForcedBatch] error when handling batch response for forced batch %d. Error: %w", forcedBatch.ForcedBatchNumber, err))
+		}
 	} //else {
 	//TODO: review if this is still needed
 	/*if f.streamServer != nil && f.currentGERHash != forcedBatch.GlobalExitRoot {
@@ -146,6 +167,11 @@ func (f *finalizer) processForcedBatch(ctx context.Context, forcedBatch state.Fo
 	}*/
 	//}
 
+	err = dbTx.Commit(ctx)
+	if err != nil {
+		return rollbackOnError(fmt.Errorf("[processForcedBatch] error when commit dbTx when processing forced batch %d. Error: %w", forcedBatch.ForcedBatchNumber, err))
+	}
+
 	return newBatchNumber, batchResponse.NewStateRoot, nil
 }
 
@@ -159,6 +185,9 @@ func (f *finalizer) addForcedTxToWorker(forcedBatchResponse *state.ProcessBatchR
 				continue
 			}
 			f.worker.AddForcedTx(txResponse.TxHash, from)
+			if f.replayProtection != nil {
+				f.replayProtection.AddTx(txResponse.TxHash, forcedBatchResponse.NewBatchNumber)
+			}
 		}
 	}
 }
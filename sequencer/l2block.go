@@ -8,6 +8,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/sequencer/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	statePackage "github.com/0xPolygonHermez/zkevm-node/state"
 	stateMetrics "github.com/0xPolygonHermez/zkevm-node/state/metrics"
@@ -22,6 +23,8 @@ type L2Block struct {
 	l1InfoTreeExitRoot state.L1InfoTreeExitRootStorageEntry
 	transactions       []*TxTracker
 	batchResponse      *state.ProcessBatchResponse
+	// used resources of the wip batch once the L2 block has been closed
+	usedBatchResources state.BatchResources
 }
 
 func (b *L2Block) isEmpty() bool {
@@ -338,6 +341,7 @@ func (f *finalizer) storeL2Block(ctx context.Context, l2Block *L2Block) error {
 		LocalExitRoot:  l2Block.batchResponse.NewLocalExitRoot,
 		BatchL2Data:    batch.BatchL2Data,
 		BatchResources: batch.Resources,
+		UsedResources:  &l2Block.usedBatchResources,
 	}
 
 	err = f.state.UpdateWIPBatch(ctx, receipt, dbTx)
@@ -387,11 +391,16 @@ func (f *finalizer) closeWIPL2Block(ctx context.Context) {
 		}
 	}
 
+	f.wipL2Block.usedBatchResources = getUsedBatchResources(f.batchConstraints, f.wipBatch.remainingResources)
+
 	f.addPendingL2BlockToProcess(ctx, f.wipL2Block)
 }
 
 func (f *finalizer) openNewWIPL2Block(ctx context.Context, prevTimestamp *time.Time) {
-	err := f.wipBatch.remainingResources.Sub(l2BlockUsedResources)
+	remainingResources, err := f.wipBatch.remainingResources.Sub(l2BlockUsedResources)
+	if err == nil {
+		f.wipBatch.remainingResources = remainingResources
+	}
 
 	// we finalize the wip batch if we got an error when subtracting the l2BlockUsedResources or we have exhausted some resources of the batch
 	if err != nil || f.isBatchResourcesExhausted() {
@@ -418,3 +427,22 @@ func (f *finalizer) openNewWIPL2Block(ctx context.Context, prevTimestamp *time.T
 
 	log.Debugf("new WIP L2 block created. Batch: %d, initialStateRoot: %s, timestamp: %d", f.wipBatch.batchNumber, f.wipL2Block.initialStateRoot, f.wipL2Block.timestamp.Unix())
 }
+
+// getL2BlockTime returns the time between L2 blocks
+func (f *finalizer) getL2BlockTime() time.Duration {
+	if f.fillRateController != nil {
+		return f.fillRateController.Delay()
+	}
+	return f.cfg.L2BlockTime.Duration
+}
+
+// updateL2BlockTime adjusts the time between L2 blocks based on the fill rate of the wip batch
+func (f *finalizer) updateL2BlockTime() {
+	if f.fillRateController == nil {
+		return
+	}
+
+	usedResources := getUsedBatchResources(f.batchConstraints, f.wipBatch.remainingResources)
+	l2BlockTime := f.fillRateController.Update(getBatchFillRate(f.batchConstraints, usedResources))
+	metrics.L2BlockTime(l2BlockTime)
+}
@@ -28,8 +28,28 @@ const (
 	WorkerPrefix = Prefix + "worker_"
 	// WorkerProcessingTimeName is the name of the metric that shows the worker processing time.
 	WorkerProcessingTimeName = WorkerPrefix + "processing_time"
+	// WorkerQueueDepthName is the name of the metric that shows the number of txs ready to be processed by the worker.
+	WorkerQueueDepthName = WorkerPrefix + "queue_depth"
+	// ResourcePercentageToCloseBatchName is the name of the metric that shows the current percentage of the resources left out to close a batch.
+	ResourcePercentageToCloseBatchName = Prefix + "resource_percentage_to_close_batch"
+	// L2BlockTimeName is the name of the metric that shows the current time between L2 blocks in seconds.
+	L2BlockTimeName = Prefix + "l2_block_time"
+	// BatchesClosedName is the name of the metric that counts the closed batches.
+	BatchesClosedName = Prefix + "batches_closed"
+	// BatchTxsName is the name of the metric that shows the number of txs of the closed batches.
+	BatchTxsName = Prefix + "batch_txs"
+	// BatchBytesName is the name of the metric that shows the used bytes of the closed batches.
+	BatchBytesName = Prefix + "batch_bytes"
+	// BatchGasName is the name of the metric that shows the used gas of the closed batches.
+	BatchGasName = Prefix + "batch_gas"
+	// BatchDurationName is the name of the metric that shows the time the closed batches have been open.
+	BatchDurationName = Prefix + "batch_duration"
+	// LatestGERTimestampName is the name of the metric that shows the timestamp of the latest global exit root.
+	LatestGERTimestampName = Prefix + "latest_ger_timestamp"
 	// TxProcessedLabelName is the name of the label for the processed transactions.
 	TxProcessedLabelName = "status"
+	// BatchesClosedLabelName is the name of the label for the closed batches.
+	BatchesClosedLabelName = "reason"
 )
 
 // TxProcessedLabel represents the possible values for the
@@ -73,6 +93,13 @@ func Register() {
 			},
 			Labels: []string{TxProcessedLabelName},
 		},
+		{
+			CounterOpts: prometheus.CounterOpts{
+				Name: BatchesClosedName,
+				Help: "[SEQUENCER] number of batches closed",
+			},
+			Labels: []string{BatchesClosedLabelName},
+		},
 	}
 
 	gauges = []prometheus.GaugeOpts{
@@ -88,6 +115,22 @@ func Register() {
 			Name: SequenceRewardInPolName,
 			Help: "[SEQUENCER] reward for a sequence in pol",
 		},
+		{
+			Name: ResourcePercentageToCloseBatchName,
+			Help: "[SEQUENCER] current percentage of the resources left out to close a batch",
+		},
+		{
+			Name: L2BlockTimeName,
+			Help: "[SEQUENCER] current time between L2 blocks in seconds",
+		},
+		{
+			Name: WorkerQueueDepthName,
+			Help: "[SEQUENCER] number of txs ready to be processed by the worker",
+		},
+		{
+			Name: LatestGERTimestampName,
+			Help: "[SEQUENCER] unix timestamp of the latest global exit root",
+		},
 	}
 
 	histograms = []prometheus.HistogramOpts{
@@ -99,6 +142,22 @@ func Register() {
 			Name: WorkerProcessingTimeName,
 			Help: "[SEQUENCER] worker processing time",
 		},
+		{
+			Name: BatchTxsName,
+			Help: "[SEQUENCER] number of txs of the closed batches",
+		},
+		{
+			Name: BatchBytesName,
+			Help: "[SEQUENCER] used bytes of the closed batches",
+		},
+		{
+			Name: BatchGasName,
+			Help: "[SEQUENCER] used gas of the closed batches",
+		},
+		{
+			Name: BatchDurationName,
+			Help: "[SEQUENCER] time the closed batches have been open",
+		},
 	}
 
 	metrics.RegisterCounters(counters...)
@@ -151,3 +210,33 @@ func WorkerProcessingTime(lastProcessTime time.Duration) {
 	execTimeInSeconds := float64(lastProcessTime) / float64(time.Second)
 	metrics.HistogramObserve(WorkerProcessingTimeName, execTimeInSeconds)
 }
+
+// ResourcePercentageToCloseBatch sets the gauge for the current percentage of the resources left out to close a batch.
+func ResourcePercentageToCloseBatch(percentage float64) {
+	metrics.GaugeSet(ResourcePercentageToCloseBatchName, percentage)
+}
+
+// L2BlockTime sets the gauge for the current time between L2 blocks.
+func L2BlockTime(l2BlockTime time.Duration) {
+	metrics.GaugeSet(L2BlockTimeName, l2BlockTime.Seconds())
+}
+
+// BatchClosed increases the counter of closed batches for the given closing reason
+// and observes the batch txs, bytes, gas and duration on the histograms.
+func BatchClosed(closingReason string, countOfTxs int, usedBytes uint64, usedGas uint64, duration time.Duration) {
+	metrics.CounterVecInc(BatchesClosedName, closingReason)
+	metrics.HistogramObserve(BatchTxsName, float64(countOfTxs))
+	metrics.HistogramObserve(BatchBytesName, float64(usedBytes))
+	metrics.HistogramObserve(BatchGasName, float64(usedGas))
+	metrics.HistogramObserve(BatchDurationName, duration.Seconds())
+}
+
+// LatestGERTimestamp sets the gauge for the timestamp of the latest global exit root.
+func LatestGERTimestamp(timestamp time.Time) {
+	metrics.GaugeSet(LatestGERTimestampName, float64(timestamp.Unix()))
+}
+
+// WorkerQueueDepth sets the gauge to the number of txs ready to be processed by the worker.
+func WorkerQueueDepth(depth int) {
+	metrics.GaugeSet(WorkerQueueDepthName, float64(depth))
+}
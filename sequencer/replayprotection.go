@@ -0,0 +1,37 @@
+package sequencer
+
+import (
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+)
+
+// BatchReplayProtection keeps the hashes of the txs added to the last batches (including the forced ones)
+// to detect a tx that is going to be added again to the wip batch
+type BatchReplayProtection struct {
+	windowBatches  uint64
+	recentTxHashes *lru.Cache[common.Hash, uint64]
+}
+
+// NewBatchReplayProtection creates a new BatchReplayProtection for the last windowBatches batches,
+// keeping up to maxTxsPerBatch tx hashes for each one
+func NewBatchReplayProtection(windowBatches uint64, maxTxsPerBatch uint64) *BatchReplayProtection {
+	return &BatchReplayProtection{
+		windowBatches:  windowBatches,
+		recentTxHashes: lru.NewCache[common.Hash, uint64](int(windowBatches * maxTxsPerBatch)),
+	}
+}
+
+// AddTx stores the hash of a tx added to the batch batchNumber
+func (p *BatchReplayProtection) AddTx(txHash common.Hash, batchNumber uint64) {
+	p.recentTxHashes.Add(txHash, batchNumber)
+}
+
+// CheckTx returns ErrDuplicateTransaction if the tx has been added to any of the last windowBatches
+// batches before batchNumber (or to batchNumber itself)
+func (p *BatchReplayProtection) CheckTx(txHash common.Hash, batchNumber uint64) error {
+	txBatchNumber, found := p.recentTxHashes.Get(txHash)
+	if found && txBatchNumber <= batchNumber && batchNumber-txBatchNumber < p.windowBatches {
+		return ErrDuplicateTransaction
+	}
+	return nil
+}
@@ -0,0 +1,24 @@
+package sequencer
+
+import (
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestBatchReplayProtection(t *testing.T) {
+	txHash := common.HexToHash("0x1")
+	otherTxHash := common.HexToHash("0x2")
+
+	p := NewBatchReplayProtection(3, 10)
+	assert.NoError(t, p.CheckTx(txHash, 10))
+
+	p.AddTx(txHash, 10)
+	assert.ErrorIs(t, p.CheckTx(txHash, 10), ErrDuplicateTransaction)
+	assert.ErrorIs(t, p.CheckTx(txHash, 12), ErrDuplicateTransaction)
+	assert.NoError(t, p.CheckTx(otherTxHash, 12))
+
+	// Out of the window
+	assert.NoError(t, p.CheckTx(txHash, 13))
+}
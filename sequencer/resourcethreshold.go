@@ -0,0 +1,119 @@
+package sequencer
+
+import (
+	"sync"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+)
+
+const (
+	// lowFillRate is the average fill rate below which the batches are closed earlier
+	lowFillRate = 0.5
+	// highFillRate is the average fill rate above which the batches are closed later
+	highFillRate = 0.9
+)
+
+// AdaptiveResourceThreshold adjusts the percentage of the resources left out to close a batch
+// based on the rolling average fill rate of the last closed batches. When the average fill rate
+// is low the percentage is increased (batches are closed earlier) and when it is high the percentage
+// is decreased (batches are filled more before being closed)
+type AdaptiveResourceThreshold struct {
+	cfg        AdaptiveResourceThresholdCfg
+	percentage uint32
+	fillRates  []float64
+	next       int
+	count      int
+	mutex      sync.Mutex
+}
+
+// NewAdaptiveResourceThreshold creates a new AdaptiveResourceThreshold starting at the provided percentage
+func NewAdaptiveResourceThreshold(cfg AdaptiveResourceThresholdCfg, initialPercentage uint32) *AdaptiveResourceThreshold {
+	t := &AdaptiveResourceThreshold{
+		cfg:        cfg,
+		percentage: initialPercentage,
+		fillRates:  make([]float64, cfg.NumOfBatches),
+	}
+	t.percentage = t.bound(t.percentage)
+	return t
+}
+
+// Percentage returns the current percentage of the resources left out to close a batch
+func (t *AdaptiveResourceThreshold) Percentage() uint32 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	return t.percentage
+}
+
+// AddClosedBatch adds the fill rate of a closed batch to the rolling window and adjusts the percentage
+// once the window is full. Returns the percentage to use from now on
+func (t *AdaptiveResourceThreshold) AddClosedBatch(fillRate float64) uint32 {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	if len(t.fillRates) == 0 {
+		return t.percentage
+	}
+
+	t.fillRates[t.next] = fillRate
+	t.next = (t.next + 1) % len(t.fillRates)
+	if t.count < len(t.fillRates) {
+		t.count++
+	}
+	if t.count < len(t.fillRates) {
+		return t.percentage
+	}
+
+	average := 0.0
+	for _, rate := range t.fillRates {
+		average += rate
+	}
+	average = average / float64(len(t.fillRates))
+
+	if average < lowFillRate {
+		t.percentage = t.bound(t.percentage + t.cfg.Step)
+	} else if average > highFillRate && t.percentage >= t.cfg.Step {
+		t.percentage = t.bound(t.percentage - t.cfg.Step)
+	}
+
+	return t.percentage
+}
+
+// bound returns the percentage limited to the configured min and max values
+func (t *AdaptiveResourceThreshold) bound(percentage uint32) uint32 {
+	if percentage < t.cfg.MinResourcePercentage {
+		return t.cfg.MinResourcePercentage
+	}
+	if t.cfg.MaxResourcePercentage > 0 && percentage > t.cfg.MaxResourcePercentage {
+		return t.cfg.MaxResourcePercentage
+	}
+	return percentage
+}
+
+// getBatchFillRate returns the fill rate of a batch, that is the highest ratio between the
+// used and the max value of each one of the batch resources
+func getBatchFillRate(constraints state.BatchConstraintsCfg, usedResources state.BatchResources) float64 {
+	fillRate := 0.0
+	addRate := func(used, max uint64) {
+		if max == 0 {
+			return
+		}
+		if rate := float64(used) / float64(max); rate > fillRate {
+			fillRate = rate
+		}
+	}
+
+	zkCounters := usedResources.ZKCounters
+	addRate(usedResources.Bytes, constraints.MaxBatchBytesSize)
+	addRate(zkCounters.GasUsed, constraints.MaxCumulativeGasUsed)
+	addRate(uint64(zkCounters.UsedKeccakHashes), uint64(constraints.MaxKeccakHashes))
+	addRate(uint64(zkCounters.UsedPoseidonHashes), uint64(constraints.MaxPoseidonHashes))
+	addRate(uint64(zkCounters.UsedPoseidonPaddings), uint64(constraints.MaxPoseidonPaddings))
+	addRate(uint64(zkCounters.UsedMemAligns), uint64(constraints.MaxMemAligns))
+	addRate(uint64(zkCounters.UsedArithmetics), uint64(constraints.MaxArithmetics))
+	addRate(uint64(zkCounters.UsedBinaries), uint64(constraints.MaxBinaries))
+	addRate(uint64(zkCounters.UsedSteps), uint64(constraints.MaxSteps))
+	addRate(uint64(zkCounters.UsedSha256Hashes_V2), uint64(constraints.MaxSHA256Hashes))
+
+	return fillRate
+}
@@ -0,0 +1,64 @@
+package sequencer
+
+import (
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAdaptiveResourceThreshold(t *testing.T) {
+	cfg := AdaptiveResourceThresholdCfg{
+		Enabled:               true,
+		NumOfBatches:          2,
+		Step:                  2,
+		MinResourcePercentage: 5,
+		MaxResourcePercentage: 12,
+	}
+
+	type testCase struct {
+		name               string
+		fillRate           float64
+		expectedPercentage uint32
+	}
+
+	testCases := []testCase{
+		{name: "window not full yet", fillRate: 0.1, expectedPercentage: 10},
+		{name: "low fill rate closes earlier", fillRate: 0.1, expectedPercentage: 12},
+		{name: "max percentage is not exceeded", fillRate: 0.1, expectedPercentage: 12},
+		{name: "average fill rate in range keeps percentage", fillRate: 0.95, expectedPercentage: 12},
+		{name: "high fill rate closes later", fillRate: 0.95, expectedPercentage: 10},
+		{name: "high fill rate closes even later", fillRate: 0.95, expectedPercentage: 8},
+		{name: "high fill rate again", fillRate: 0.95, expectedPercentage: 6},
+		{name: "min percentage is not exceeded", fillRate: 0.95, expectedPercentage: 5},
+	}
+
+	threshold := NewAdaptiveResourceThreshold(cfg, 10)
+	assert.Equal(t, uint32(10), threshold.Percentage())
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			percentage := threshold.AddClosedBatch(tc.fillRate)
+			assert.Equal(t, tc.expectedPercentage, percentage)
+			assert.Equal(t, tc.expectedPercentage, threshold.Percentage())
+		})
+	}
+}
+
+func TestGetBatchFillRate(t *testing.T) {
+	constraints := state.BatchConstraintsCfg{
+		MaxBatchBytesSize:    100,
+		MaxCumulativeGasUsed: 1000,
+		MaxSteps:             10,
+	}
+	usedResources := state.BatchResources{
+		ZKCounters: state.ZKCounters{
+			GasUsed:   500,
+			UsedSteps: 8,
+		},
+		Bytes: 20,
+	}
+
+	assert.Equal(t, 0.8, getBatchFillRate(constraints, usedResources))
+	assert.Equal(t, 0.0, getBatchFillRate(constraints, state.BatchResources{}))
+}
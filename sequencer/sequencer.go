@@ -17,6 +17,8 @@ import (
 
 const (
 	datastreamChannelMultiplier = 2
+	// batchCountByClosingReasonMetricsInterval is the interval to refresh the batch count by closing reason metric
+	batchCountByClosingReasonMetricsInterval = time.Minute
 )
 
 // Sequencer represents a sequencer
@@ -42,6 +44,10 @@ type Sequencer struct {
 
 // New init sequencer
 func New(cfg Config, batchCfg state.BatchConfig, poolCfg pool.Config, txPool txPool, stateI stateInterface, etherman etherman, eventLog *event.EventLog) (*Sequencer, error) {
+	if err := batchCfg.Constraints.Validate(); err != nil {
+		return nil, fmt.Errorf("invalid batch constraints, err: %w", err)
+	}
+
 	addr, err := etherman.TrustedSequencer()
 	if err != nil {
 		return nil, fmt.Errorf("failed to get trusted sequencer address, err: %v", err)
@@ -97,8 +103,10 @@ func (s *Sequencer) Start(ctx context.Context) {
 		go s.sendDataToStreamer()
 	}
 
-	s.worker = NewWorker(s.stateI, s.batchCfg.Constraints)
+	s.worker = NewWorker(s.stateI, s.batchCfg.Constraints, s.cfg.WorkerQueueDepthWarnThreshold)
 	s.finalizer = newFinalizer(s.cfg.Finalizer, s.poolCfg, s.worker, s.pool, s.stateI, s.etherman, s.address, s.isSynced, s.batchCfg.Constraints, s.eventLog, s.streamServer, s.dataToStream)
+	go s.updateBatchClosingMetrics(s.finalizer.SubscribeToBatchClosing())
+	go s.updateBatchCountByClosingReasonMetrics(ctx)
 	go s.finalizer.Start(ctx)
 
 	go s.purgeOldPoolTxs(ctx) //TODO: Review if this function is needed as we have other go func to expire old txs in the worker
@@ -111,6 +119,32 @@ func (s *Sequencer) Start(ctx context.Context) {
 	<-ctx.Done()
 }
 
+// updateBatchClosingMetrics updates the batch metrics each time the finalizer closes a batch
+func (s *Sequencer) updateBatchClosingMetrics(batchClosingEvents <-chan BatchClosingEvent) {
+	for event := range batchClosingEvents {
+		metrics.BatchClosed(string(event.ClosingReason), event.CountOfTxs, event.UsedBytes, event.UsedGas, event.Duration)
+	}
+}
+
+// updateBatchCountByClosingReasonMetrics refreshes periodically the number of closed batches by closing reason,
+// out of the finalizer loop as it needs to count the batches in the state
+func (s *Sequencer) updateBatchCountByClosingReasonMetrics(ctx context.Context) {
+	ticker := time.NewTicker(batchCountByClosingReasonMetricsInterval)
+	defer ticker.Stop()
+
+	for {
+		if err := s.stateI.UpdateBatchCountByClosingReasonMetrics(ctx, nil); err != nil {
+			log.Errorf("failed to update batch count by closing reason metrics. Error: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
 // checkStateInconsistency checks if state inconsistency happened
 func (s *Sequencer) checkStateInconsistency(ctx context.Context) {
 	for {
@@ -198,7 +232,26 @@ func (s *Sequencer) loadFromPool(ctx context.Context) {
 }
 
 func (s *Sequencer) addTxToWorker(ctx context.Context, tx pool.Transaction) error {
-	txTracker, err := s.worker.NewTxTracker(tx.Transaction, tx.ZKCounters, tx.IP)
+	// Discard the tx before processing it if its encoded size doesn't fit in a batch
+	if encodedSize := state.EstimateEncodedSize(&tx.Transaction); encodedSize > s.batchCfg.Constraints.MaxBatchBytesSize {
+		log.Infof("tx %s discarded, encoded size %d exceeds the max batch bytes size %d", tx.Hash().String(), encodedSize, s.batchCfg.Constraints.MaxBatchBytesSize)
+		failedReason := pool.ErrOversizedData.Error()
+		return s.pool.UpdateTxStatus(ctx, tx.Hash(), pool.TxStatusFailed, false, &failedReason)
+	}
+
+	// If the zk counters of the tx are unknown we use a heuristic estimation of them, it's only a hint
+	// to order the txs as it isn't based on the executor counters
+	zkCounters := tx.ZKCounters
+	if zkCounters.IsZero() {
+		estimatedZKCounters, err := state.EstimateZKCounters(&tx.Transaction)
+		if err != nil {
+			log.Warnf("failed to estimate zk counters for tx %s. Error: %v", tx.Hash().String(), err)
+		} else {
+			zkCounters = estimatedZKCounters
+		}
+	}
+
+	txTracker, err := s.worker.NewTxTracker(tx.Transaction, zkCounters, tx.IP)
 	if err != nil {
 		return err
 	}
@@ -0,0 +1,52 @@
+package sequencer
+
+import (
+	"context"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/trace"
+)
+
+const (
+	tracerName = "github.com/0xPolygonHermez/zkevm-node/sequencer"
+
+	processTransactionSpanName = "processTransaction"
+	usedZkCountersEventName    = "usedZkCounters"
+)
+
+// tracer is the OpenTelemetry tracer of the sequencer, the spans are discarded
+// unless a global TracerProvider has been registered
+var tracer = otel.Tracer(tracerName)
+
+// startProcessTransactionSpan starts the span of the executor call done to process a tx
+// in the wip batch. tx is nil when only the L2 block is processed
+func startProcessTransactionSpan(ctx context.Context, tx *TxTracker, batchNumber uint64) (context.Context, trace.Span) {
+	attrs := []attribute.KeyValue{
+		attribute.Int64("batch.number", int64(batchNumber)),
+	}
+	if tx != nil {
+		attrs = append(attrs,
+			attribute.String("tx.hash", tx.HashStr),
+			attribute.String("tx.from", tx.FromStr),
+			attribute.Int64("tx.gas", int64(tx.Gas)),
+		)
+	}
+	return tracer.Start(ctx, processTransactionSpanName, trace.WithAttributes(attrs...))
+}
+
+// addUsedZkCountersEvent records the zkCounters used by the executor as a span event
+func addUsedZkCountersEvent(span trace.Span, counters state.ZKCounters) {
+	span.AddEvent(usedZkCountersEventName, trace.WithAttributes(
+		attribute.Int64("gasUsed", int64(counters.GasUsed)),
+		attribute.Int64("keccakHashes", int64(counters.UsedKeccakHashes)),
+		attribute.Int64("poseidonHashes", int64(counters.UsedPoseidonHashes)),
+		attribute.Int64("poseidonPaddings", int64(counters.UsedPoseidonPaddings)),
+		attribute.Int64("memAligns", int64(counters.UsedMemAligns)),
+		attribute.Int64("arithmetics", int64(counters.UsedArithmetics)),
+		attribute.Int64("binaries", int64(counters.UsedBinaries)),
+		attribute.Int64("steps", int64(counters.UsedSteps)),
+		attribute.Int64("sha256Hashes", int64(counters.UsedSha256Hashes_V2)),
+	))
+}
@@ -0,0 +1,48 @@
+package sequencer
+
+import (
+	"context"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+)
+
+func TestProcessTransactionSpan(t *testing.T) {
+	recorder := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(recorder))
+	previousProvider := otel.GetTracerProvider()
+	otel.SetTracerProvider(provider)
+	defer otel.SetTracerProvider(previousProvider)
+
+	tx := &TxTracker{
+		HashStr: common.HexToHash("0x1").String(),
+		FromStr: common.HexToAddress("0x2").String(),
+		Gas:     21000,
+	}
+	_, span := startProcessTransactionSpan(context.Background(), tx, 10)
+	addUsedZkCountersEvent(span, state.ZKCounters{GasUsed: 21000, UsedSteps: 100})
+	span.End()
+
+	spans := recorder.Ended()
+	require.Len(t, spans, 1)
+	assert.Equal(t, processTransactionSpanName, spans[0].Name())
+	assert.ElementsMatch(t, []attribute.KeyValue{
+		attribute.Int64("batch.number", 10),
+		attribute.String("tx.hash", tx.HashStr),
+		attribute.String("tx.from", tx.FromStr),
+		attribute.Int64("tx.gas", 21000),
+	}, spans[0].Attributes())
+
+	require.Len(t, spans[0].Events(), 1)
+	event := spans[0].Events()[0]
+	assert.Equal(t, usedZkCountersEventName, event.Name)
+	assert.Contains(t, event.Attributes, attribute.Int64("gasUsed", 21000))
+	assert.Contains(t, event.Attributes, attribute.Int64("steps", 100))
+}
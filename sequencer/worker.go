@@ -10,6 +10,7 @@ import (
 
 	"github.com/0xPolygonHermez/zkevm-node/log"
 	"github.com/0xPolygonHermez/zkevm-node/pool"
+	"github.com/0xPolygonHermez/zkevm-node/sequencer/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
@@ -22,20 +23,44 @@ type Worker struct {
 	workerMutex      sync.Mutex
 	state            stateInterface
 	batchConstraints state.BatchConstraintsCfg
+	// queueDepthWarnThreshold is the number of ready txs from which a warning is logged, 0 disables it
+	queueDepthWarnThreshold uint64
+	queueDepthWarned        bool
 }
 
 // NewWorker creates an init a worker
-func NewWorker(state stateInterface, constraints state.BatchConstraintsCfg) *Worker {
+func NewWorker(state stateInterface, constraints state.BatchConstraintsCfg, queueDepthWarnThreshold uint64) *Worker {
 	w := Worker{
-		pool:             make(map[string]*addrQueue),
-		txSortedList:     newTxSortedList(),
-		state:            state,
-		batchConstraints: constraints,
+		pool:                    make(map[string]*addrQueue),
+		txSortedList:            newTxSortedList(),
+		state:                   state,
+		batchConstraints:        constraints,
+		queueDepthWarnThreshold: queueDepthWarnThreshold,
 	}
 
 	return &w
 }
 
+// updateQueueDepth updates the queue depth metric with the number of ready txs and logs a warning
+// the first time the depth exceeds the configured threshold. Must be called with the workerMutex locked
+func (w *Worker) updateQueueDepth() {
+	depth := w.txSortedList.len()
+	metrics.WorkerQueueDepth(depth)
+
+	if w.queueDepthWarnThreshold == 0 {
+		return
+	}
+
+	if uint64(depth) > w.queueDepthWarnThreshold {
+		if !w.queueDepthWarned {
+			log.Warnf("worker queue depth %d exceeds the warning threshold %d", depth, w.queueDepthWarnThreshold)
+			w.queueDepthWarned = true
+		}
+	} else {
+		w.queueDepthWarned = false
+	}
+}
+
 // NewTxTracker creates and inits a TxTracker
 func (w *Worker) NewTxTracker(tx types.Transaction, counters state.ZKCounters, ip string) (*TxTracker, error) {
 	return newTxTracker(tx, counters, ip)
@@ -110,6 +135,7 @@ func (w *Worker) AddTxTracker(ctx context.Context, tx *TxTracker) (replacedTx *T
 		log.Debugf("[AddTxTracker] newReadyTx(%s) nonce(%d) gasPrice(%d) addr(%s) added to TxSortedList", newReadyTx.HashStr, newReadyTx.Nonce, newReadyTx.GasPrice, tx.FromStr)
 		w.txSortedList.add(newReadyTx)
 	}
+	w.updateQueueDepth()
 
 	if repTx != nil {
 		log.Debugf("[AddTxTracker] replacedTx(%s) nonce(%d) gasPrice(%d) addr(%s) has been replaced", repTx.HashStr, repTx.Nonce, repTx.GasPrice, tx.FromStr)
@@ -134,6 +160,7 @@ func (w *Worker) applyAddressUpdate(from common.Address, fromNonce *uint64, from
 			log.Debugf("[applyAddressUpdate] newReadyTx(%s) nonce(%d) gasPrice(%d) added to TxSortedList", newReadyTx.Hash.String(), newReadyTx.Nonce, newReadyTx.GasPrice)
 			w.txSortedList.add(newReadyTx)
 		}
+		w.updateQueueDepth()
 
 		return newReadyTx, prevReadyTx, txsToDelete
 	}
@@ -199,6 +226,7 @@ func (w *Worker) DeleteTx(txHash common.Hash, addr common.Address) {
 		if deletedReadyTx != nil {
 			log.Debugf("[DeleteTx] tx(%s) deleted from TxSortedList", deletedReadyTx.Hash.String())
 			w.txSortedList.delete(deletedReadyTx)
+			w.updateQueueDepth()
 		}
 	} else {
 		log.Warnf("[DeleteTx] addrQueue(%s) not found", addr.String())
@@ -318,7 +346,7 @@ func (w *Worker) GetBestFittingTx(resources state.BatchResources) (*TxTracker, e
 				foundMutex.RUnlock()
 
 				txCandidate := w.txSortedList.getByIndex(i)
-				err := bresources.Sub(txCandidate.BatchResources)
+				_, err := bresources.Sub(txCandidate.BatchResources)
 				if err != nil {
 					// We don't add this Tx
 					continue
@@ -365,6 +393,7 @@ func (w *Worker) ExpireTransactions(maxTime time.Duration) []*TxTracker {
 			delete(w.pool, addrQueue.fromStr)
 		}
 	}
+	w.updateQueueDepth()
 	log.Debug("expire transactions ended. addrQueue len: ", len(w.pool), " deleteCount: ", len(txs))
 
 	return txs
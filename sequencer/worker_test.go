@@ -271,7 +271,8 @@ func TestWorkerGetBestTx(t *testing.T) {
 			if tx.HashStr != expectedGetBestTx[ct].String() {
 				t.Fatalf("Error GetBestFittingTx(%d). Expected=%s, Actual=%s", ct, expectedGetBestTx[ct].String(), tx.HashStr)
 			}
-			err := rc.Sub(tx.BatchResources)
+			var err error
+			rc, err = rc.Sub(tx.BatchResources)
 			assert.NoError(t, err)
 
 			touch := make(map[common.Address]*state.InfoReadWrite)
@@ -288,7 +289,21 @@ func TestWorkerGetBestTx(t *testing.T) {
 	}
 }
 
+func TestWorkerQueueDepthWarning(t *testing.T) {
+	worker := NewWorker(NewStateMock(t), rcMax, 2)
+
+	for i := 1; i <= 3; i++ {
+		worker.txSortedList.add(&TxTracker{HashStr: common.Hash{byte(i)}.String(), GasPrice: big.NewInt(int64(i))})
+		worker.updateQueueDepth()
+	}
+	assert.True(t, worker.queueDepthWarned)
+
+	worker.txSortedList.delete(&TxTracker{HashStr: common.Hash{3}.String(), GasPrice: big.NewInt(3)})
+	worker.updateQueueDepth()
+	assert.False(t, worker.queueDepthWarned)
+}
+
 func initWorker(stateMock *StateMock, rcMax state.BatchConstraintsCfg) *Worker {
-	worker := NewWorker(stateMock, rcMax)
+	worker := NewWorker(stateMock, rcMax, 0)
 	return worker
 }
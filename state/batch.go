@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
@@ -40,6 +41,26 @@ type Batch struct {
 	Resources      BatchResources
 	// WIP: if WIP == true is a openBatch
 	WIP bool
+	// L1BlockNumber is the latest L1 block number known when the batch was opened by the sequencer, 0 if unknown
+	L1BlockNumber uint64
+	// EffectiveGasPrice is the average effective gas price of the txs of the batch, nil if unknown
+	EffectiveGasPrice *big.Int
+}
+
+// Validate checks that the batch is self-consistent before storing it: the batch number and
+// coinbase can't be zero and the timestamp must be set. The BatchL2Data isn't checked as the
+// sequencer can close empty batches, for example when the forced batches deadline is reached
+func (b *Batch) Validate() error {
+	if b.BatchNumber == 0 {
+		return fmt.Errorf("%w: batch number can't be 0", ErrInvalidBatch)
+	}
+	if b.Coinbase == ZeroAddress {
+		return fmt.Errorf("%w: batch %d coinbase can't be the zero address", ErrInvalidBatch, b.BatchNumber)
+	}
+	if b.Timestamp.IsZero() {
+		return fmt.Errorf("%w: batch %d timestamp can't be zero", ErrInvalidBatch, b.BatchNumber)
+	}
+	return nil
 }
 
 // ProcessingContext is the necessary data that a batch needs to provide to the runtime,
@@ -73,6 +94,16 @@ const (
 	GlobalExitRootDeadlineClosingReason ClosingReason = "Global Exit Root deadline"
 )
 
+// closingReasons are the reasons a batch can be closed with
+var closingReasons = []ClosingReason{
+	BatchFullClosingReason,
+	ForcedBatchClosingReason,
+	BatchAlmostFullClosingReason,
+	ForcedBatchDeadlineClosingReason,
+	TimeoutResolutionDeadlineClosingReason,
+	GlobalExitRootDeadlineClosingReason,
+}
+
 // ProcessingReceipt indicates the outcome (StateRoot, AccInputHash) of processing a batch
 type ProcessingReceipt struct {
 	BatchNumber    uint64
@@ -84,6 +115,22 @@ type ProcessingReceipt struct {
 	BatchL2Data    []byte
 	ClosingReason  ClosingReason
 	BatchResources BatchResources
+	// UsedResources are the resources used by the wip batch, nil if they are unknown
+	UsedResources *BatchResources
+	// EffectiveGasPrice is the average effective gas price of the txs of the batch, nil if unknown
+	EffectiveGasPrice *big.Int
+}
+
+// Validate checks the receipt before closing the batch with it: the batch number and the state root
+// can't be zero
+func (r *ProcessingReceipt) Validate() error {
+	if r.BatchNumber == 0 {
+		return fmt.Errorf("%w: batch number can't be 0", ErrInvalidBatch)
+	}
+	if r.StateRoot == ZeroHash {
+		return fmt.Errorf("%w: batch %d state root can't be zero", ErrInvalidBatch, r.BatchNumber)
+	}
+	return nil
 }
 
 // VerifiedBatch represents a VerifiedBatch
@@ -154,6 +201,9 @@ func (s *State) OpenWIPBatch(ctx context.Context, batch Batch, dbTx pgx.Tx) erro
 	if dbTx == nil {
 		return ErrDBTxNil
 	}
+	if err := batch.Validate(); err != nil {
+		return err
+	}
 
 	//TODO: Use s.GetLastBatch to retrieve number and time and avoid to do 2 queries
 	// Check if the batch that is being opened has batch num + 1 compared to the latest batch
@@ -183,6 +233,19 @@ func (s *State) OpenWIPBatch(ctx context.Context, batch Batch, dbTx pgx.Tx) erro
 	return s.OpenWIPBatchInStorage(ctx, batch, dbTx)
 }
 
+// UpdateBatchCountByClosingReasonMetrics queries the number of closed batches for each closing reason
+// and exposes them in the state metrics
+func (s *State) UpdateBatchCountByClosingReasonMetrics(ctx context.Context, dbTx pgx.Tx) error {
+	for _, reason := range closingReasons {
+		count, err := s.GetBatchCountByClosingReason(ctx, reason, dbTx)
+		if err != nil {
+			return err
+		}
+		metrics.BatchCountByClosingReason(string(reason), count)
+	}
+	return nil
+}
+
 // GetWIPBatch returns the wip batch in the state
 func (s *State) GetWIPBatch(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*Batch, error) {
 	return s.GetWIPBatchInStorage(ctx, batchNumber, dbTx)
@@ -441,6 +504,9 @@ func (s *State) CloseBatch(ctx context.Context, receipt ProcessingReceipt, dbTx
 
 // CloseWIPBatch is used by sequencer to close the wip batch
 func (s *State) CloseWIPBatch(ctx context.Context, receipt ProcessingReceipt, dbTx pgx.Tx) error {
+	if err := receipt.Validate(); err != nil {
+		return err
+	}
 	return s.CloseWIPBatchInStorage(ctx, receipt, dbTx)
 }
 
@@ -558,6 +624,12 @@ func (s *State) GetBatchTimestamp(ctx context.Context, batchNumber uint64, force
 
 // GetL1InfoTreeDataFromBatchL2Data returns a map with the L1InfoTreeData used in the L2 blocks included in the batchL2Data and the last L1InfoRoot used
 func (s *State) GetL1InfoTreeDataFromBatchL2Data(ctx context.Context, batchL2Data []byte, dbTx pgx.Tx) (map[uint32]L1DataV2, common.Hash, error) {
+	if s.l1InfoTreeDataCache != nil {
+		if l1InfoTreeData, l1InfoRoot, found := s.l1InfoTreeDataCache.get(batchL2Data); found {
+			return l1InfoTreeData, l1InfoRoot, nil
+		}
+	}
+
 	batchRaw, err := DecodeBatchV2(batchL2Data)
 	if err != nil {
 		return nil, ZeroHash, err
@@ -590,5 +662,9 @@ func (s *State) GetL1InfoTreeDataFromBatchL2Data(ctx context.Context, batchL2Dat
 		}
 	}
 
+	if s.l1InfoTreeDataCache != nil {
+		s.l1InfoTreeDataCache.add(batchL2Data, l1InfoTreeData, lastL1InfoRoot)
+	}
+
 	return l1InfoTreeData, lastL1InfoRoot, nil
 }
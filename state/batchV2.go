@@ -83,7 +83,7 @@ func (s *State) ProcessBatchV2(ctx context.Context, request ProcessRequest, upda
 		processBatchRequest.SkipVerifyL1InfoRoot = cTrue
 	}
 
-	res, err := s.sendBatchRequestToExecutorV2(ctx, processBatchRequest, request.Caller)
+	res, executionTime, err := s.sendBatchRequestToExecutorV2(ctx, processBatchRequest, request.Caller)
 	if err != nil {
 		return nil, err
 	}
@@ -93,6 +93,7 @@ func (s *State) ProcessBatchV2(ctx context.Context, request ProcessRequest, upda
 	if err != nil {
 		return nil, err
 	}
+	result.ExecutionTimeMs = uint64(executionTime.Milliseconds())
 
 	log.Debugf("ProcessBatchV2 end")
 	log.Debugf("*******************************************")
@@ -257,12 +258,14 @@ func (s *State) processBatchV2(ctx context.Context, processingCtx *ProcessingCon
 		processBatchRequest.L1InfoRoot = currentl1InfoRoot.Bytes()
 	}
 
-	return s.sendBatchRequestToExecutorV2(ctx, processBatchRequest, caller)
+	res, _, err := s.sendBatchRequestToExecutorV2(ctx, processBatchRequest, caller)
+	return res, err
 }
 
-func (s *State) sendBatchRequestToExecutorV2(ctx context.Context, processBatchRequest *executor.ProcessBatchRequestV2, caller metrics.CallerLabel) (*executor.ProcessBatchResponseV2, error) {
+// sendBatchRequestToExecutorV2 sends the batch request to the executor and returns its response and the time it took
+func (s *State) sendBatchRequestToExecutorV2(ctx context.Context, processBatchRequest *executor.ProcessBatchRequestV2, caller metrics.CallerLabel) (*executor.ProcessBatchResponseV2, time.Duration, error) {
 	if s.executorClient == nil {
-		return nil, ErrExecutorNil
+		return nil, 0, ErrExecutorNil
 	}
 	// Send Batch to the Executor
 	if caller != metrics.DiscardCallerLabel {
@@ -302,7 +305,7 @@ func (s *State) sendBatchRequestToExecutorV2(ctx context.Context, processBatchRe
 	}
 	log.Infof("batch %d took %v to be processed by the executor ", processBatchRequest.OldBatchNum+1, elapsed)
 
-	return res, err
+	return res, elapsed, err
 }
 
 func processBatchResponseToString(r *executor.ProcessBatchResponseV2, prefix string) string {
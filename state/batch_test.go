@@ -0,0 +1,86 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBatchValidate(t *testing.T) {
+	validBatch := func() Batch {
+		return Batch{
+			BatchNumber: 1,
+			Coinbase:    common.HexToAddress("0x1"),
+			Timestamp:   time.Unix(1, 0),
+			BatchL2Data: []byte{0x1},
+		}
+	}
+
+	testCases := []struct {
+		name          string
+		modify        func(b *Batch)
+		expectedError string
+	}{
+		{
+			name:   "valid closed batch",
+			modify: func(b *Batch) {},
+		},
+		{
+			name: "valid wip batch without BatchL2Data",
+			modify: func(b *Batch) {
+				b.WIP = true
+				b.BatchL2Data = nil
+			},
+		},
+		{
+			name:          "batch number is zero",
+			modify:        func(b *Batch) { b.BatchNumber = 0 },
+			expectedError: "invalid batch: batch number can't be 0",
+		},
+		{
+			name:          "coinbase is the zero address",
+			modify:        func(b *Batch) { b.Coinbase = ZeroAddress },
+			expectedError: "invalid batch: batch 1 coinbase can't be the zero address",
+		},
+		{
+			name:          "timestamp is zero",
+			modify:        func(b *Batch) { b.Timestamp = time.Time{} },
+			expectedError: "invalid batch: batch 1 timestamp can't be zero",
+		},
+		{
+			name:   "valid closed batch without BatchL2Data",
+			modify: func(b *Batch) { b.BatchL2Data = []byte{} },
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			batch := validBatch()
+			tc.modify(&batch)
+			err := batch.Validate()
+			if tc.expectedError == "" {
+				require.NoError(t, err)
+				return
+			}
+			require.ErrorIs(t, err, ErrInvalidBatch)
+			require.EqualError(t, err, tc.expectedError)
+		})
+	}
+}
+
+func TestProcessingReceiptValidate(t *testing.T) {
+	receipt := ProcessingReceipt{BatchNumber: 1, StateRoot: common.HexToHash("0x1")}
+	require.NoError(t, receipt.Validate())
+
+	receipt.StateRoot = ZeroHash
+	err := receipt.Validate()
+	require.ErrorIs(t, err, ErrInvalidBatch)
+	require.EqualError(t, err, "invalid batch: batch 1 state root can't be zero")
+
+	receipt.BatchNumber = 0
+	err = receipt.Validate()
+	require.ErrorIs(t, err, ErrInvalidBatch)
+	require.EqualError(t, err, "invalid batch: batch number can't be 0")
+}
@@ -0,0 +1,35 @@
+package state
+
+import (
+	"fmt"
+
+	"github.com/klauspost/compress/zstd"
+)
+
+var (
+	// batchL2DataEncoder and batchL2DataDecoder are safe for concurrent use with EncodeAll and DecodeAll
+	batchL2DataEncoder, _ = zstd.NewWriter(nil)
+	batchL2DataDecoder, _ = zstd.NewReader(nil)
+)
+
+// CompressBatchL2Data returns the BatchL2Data to store in the DB, compressed with zstd if threshold is
+// not 0 and the length of data is greater than threshold. The returned flag is true if the data has
+// been compressed, and it must be stored along with the data to be able to read it back
+func CompressBatchL2Data(data []byte, threshold uint64) ([]byte, bool) {
+	if threshold == 0 || uint64(len(data)) <= threshold {
+		return data, false
+	}
+	return batchL2DataEncoder.EncodeAll(data, nil), true
+}
+
+// DecompressBatchL2Data returns the original BatchL2Data from the data stored in the DB
+func DecompressBatchL2Data(data []byte, compressed bool) ([]byte, error) {
+	if !compressed {
+		return data, nil
+	}
+	decompressed, err := batchL2DataDecoder.DecodeAll(data, nil)
+	if err != nil {
+		return nil, fmt.Errorf("error decompressing BatchL2Data. Error: %w", err)
+	}
+	return decompressed, nil
+}
@@ -0,0 +1,40 @@
+package state
+
+import (
+	"bytes"
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestCompressedBatchL2Data(t *testing.T) {
+	data := bytes.Repeat([]byte{0x0b, 0x00, 0x00, 0x00, 0x01}, 100)
+
+	// Below the threshold the data is stored as it is
+	stored, compressed := CompressBatchL2Data(data, uint64(len(data)))
+	require.False(t, compressed)
+	require.Equal(t, data, stored)
+
+	// A threshold of 0 disables the compression
+	_, compressed = CompressBatchL2Data(data, 0)
+	require.False(t, compressed)
+
+	stored, compressed = CompressBatchL2Data(data, 10)
+	require.True(t, compressed)
+	require.Less(t, len(stored), len(data))
+	decompressed, err := DecompressBatchL2Data(stored, compressed)
+	require.NoError(t, err)
+	require.Equal(t, data, decompressed)
+
+	// Data stored without compression is returned as it is, even if it looks like compressed data
+	decompressed, err = DecompressBatchL2Data(stored, false)
+	require.NoError(t, err)
+	require.Equal(t, stored, decompressed)
+	decompressed, err = DecompressBatchL2Data(nil, false)
+	require.NoError(t, err)
+	require.Nil(t, decompressed)
+
+	// Invalid compressed data
+	_, err = DecompressBatchL2Data(data, true)
+	require.Error(t, err)
+}
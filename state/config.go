@@ -1,6 +1,8 @@
 package state
 
 import (
+	"fmt"
+
 	"github.com/0xPolygonHermez/zkevm-node/config/types"
 	"github.com/0xPolygonHermez/zkevm-node/db"
 )
@@ -45,6 +47,14 @@ type Config struct {
 	// MaxNativeBlockHashBlockRange is a configuration to set the max range for block number when querying
 	// native block hashes in a single call to the state, if zero it means no limit
 	MaxNativeBlockHashBlockRange uint64
+
+	// BatchL2DataCompressionThreshold is the size in bytes of the BatchL2Data from which it is stored
+	// compressed in the database, if zero it means no compression
+	BatchL2DataCompressionThreshold uint64
+
+	// L1InfoTreeCacheTTL is the time the L1InfoTree data of a BatchL2Data is kept in memory to avoid
+	// querying it again if the same BatchL2Data is processed, if zero it means no cache
+	L1InfoTreeCacheTTL types.Duration
 }
 
 // BatchConfig represents the configuration of the batch constraints
@@ -65,6 +75,69 @@ type BatchConstraintsCfg struct {
 	MaxBinaries          uint32 `mapstructure:"MaxBinaries"`
 	MaxSteps             uint32 `mapstructure:"MaxSteps"`
 	MaxSHA256Hashes      uint32 `mapstructure:"MaxSHA256Hashes"`
+	// MaxForcedBatchesPerIteration is the max number of forced batches processed each time the
+	// sequencer processes the pending forced batches, the rest are processed the next time
+	MaxForcedBatchesPerIteration uint32 `mapstructure:"MaxForcedBatchesPerIteration"`
+}
+
+// Validate returns an error if the batch constraints are not valid
+func (c BatchConstraintsCfg) Validate() error {
+	if c.MaxForcedBatchesPerIteration == 0 {
+		return fmt.Errorf("MaxForcedBatchesPerIteration must be greater than 0")
+	}
+	return nil
+}
+
+// ToMaxResources returns the max resources that can be used in a batch
+func (c BatchConstraintsCfg) ToMaxResources() BatchResources {
+	return BatchResources{
+		ZKCounters: ZKCounters{
+			GasUsed:              c.MaxCumulativeGasUsed,
+			UsedKeccakHashes:     c.MaxKeccakHashes,
+			UsedPoseidonHashes:   c.MaxPoseidonHashes,
+			UsedPoseidonPaddings: c.MaxPoseidonPaddings,
+			UsedMemAligns:        c.MaxMemAligns,
+			UsedArithmetics:      c.MaxArithmetics,
+			UsedBinaries:         c.MaxBinaries,
+			UsedSteps:            c.MaxSteps,
+			UsedSha256Hashes_V2:  c.MaxSHA256Hashes,
+		},
+		Bytes: c.MaxBatchBytesSize,
+	}
+}
+
+// ZeroConstraints returns the names of the constraints that are not set (zero)
+func (c BatchConstraintsCfg) ZeroConstraints() []string {
+	constraints := []struct {
+		name  string
+		isSet bool
+	}{
+		{"MaxTxsPerBatch", c.MaxTxsPerBatch != 0},
+		{"MaxBatchBytesSize", c.MaxBatchBytesSize != 0},
+		{"MaxCumulativeGasUsed", c.MaxCumulativeGasUsed != 0},
+		{"MaxKeccakHashes", c.MaxKeccakHashes != 0},
+		{"MaxPoseidonHashes", c.MaxPoseidonHashes != 0},
+		{"MaxPoseidonPaddings", c.MaxPoseidonPaddings != 0},
+		{"MaxMemAligns", c.MaxMemAligns != 0},
+		{"MaxArithmetics", c.MaxArithmetics != 0},
+		{"MaxBinaries", c.MaxBinaries != 0},
+		{"MaxSteps", c.MaxSteps != 0},
+		{"MaxSHA256Hashes", c.MaxSHA256Hashes != 0},
+	}
+
+	zeroConstraints := []string{}
+	for _, constraint := range constraints {
+		if !constraint.isSet {
+			zeroConstraints = append(zeroConstraints, constraint.name)
+		}
+	}
+	return zeroConstraints
+}
+
+// CloseThreshold returns the remaining resources below which a batch must be closed, given the percentage
+// of the max resources that is left out for the batch to be closed
+func (c BatchConstraintsCfg) CloseThreshold(resourcePercentageToCloseBatch uint32) BatchResources {
+	return c.ToMaxResources().ScaleBy(float64(resourcePercentageToCloseBatch) / 100.0) //nolint:gomnd
 }
 
 // IsWithinConstraints checks if the counters are within the batch constraints
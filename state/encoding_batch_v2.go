@@ -76,6 +76,8 @@ var (
 	ErrInvalidBatchV2 = errors.New("invalid batch v2")
 	// ErrInvalidRLP is returned when the rlp is invalid.
 	ErrInvalidRLP = errors.New("invalid rlp codification")
+	// ErrBatchV2EmptyL2Block is returned when a L2 block of the batch doesn't contain any transaction
+	ErrBatchV2EmptyL2Block = errors.New("batch v2 contains a L2 block without transactions")
 )
 
 func (b *BatchRawV2) String() string {
@@ -90,6 +92,19 @@ func (b *BatchRawV2) String() string {
 	return res
 }
 
+// CheckNoEmptyL2Blocks returns an error if any of the L2 blocks of the batch doesn't contain transactions.
+// Note that DecodeBatchV2 and EncodeBatchV2 don't perform this check, since the sequencer closes empty
+// L2 blocks (e.g. to update the L1InfoRoot or the timestamp), so it must be called explicitly by the
+// callers that require it
+func (b *BatchRawV2) CheckNoEmptyL2Blocks() error {
+	for i, block := range b.Blocks {
+		if len(block.Transactions) == 0 {
+			return fmt.Errorf("L2 block %d (deltaTimestamp: %d, indexL1InfoTree: %d): %w", i, block.DeltaTimestamp, block.IndexL1InfoTree, ErrBatchV2EmptyL2Block)
+		}
+	}
+	return nil
+}
+
 // EncodeBatchV2 encodes a batch of transactions into a byte slice.
 func EncodeBatchV2(batch *BatchRawV2) ([]byte, error) {
 	var err error
@@ -188,6 +203,38 @@ func DecodeBatchV2(txsData []byte) (*BatchRawV2, error) {
 	return &BatchRawV2{blocks}, nil
 }
 
+// ParseFirstTransaction decodes only the first transaction of a batch V2, skipping the headers of the
+// L2 blocks before it, so the rest of the batch is not decoded. It's used to inspect the first tx (e.g.
+// its gas price) of large batches. It returns nil if the batch has no transactions
+func ParseFirstTransaction(txsData []byte) (*L2TxRaw, error) {
+	var err error
+	pos := int(0)
+	for pos < len(txsData) {
+		if txsData[pos] == changeL2Block {
+			pos, _, err = decodeBlockHeader(txsData, pos+1)
+			if err != nil {
+				return nil, fmt.Errorf("pos: %d can't decode new BlockHeader: %w", pos, err)
+			}
+			continue
+		}
+		if pos == 0 {
+			_, _, err := decodeTxRLP(txsData, pos)
+			if err == nil {
+				// There is no changeL2Block but have a valid RLP transaction
+				return nil, ErrBatchV2DontStartWithChangeL2Block
+			}
+			// No changeL2Block and no valid RLP transaction
+			return nil, fmt.Errorf("no ChangeL2Block neither valid Tx, batch malformed : %w", ErrInvalidBatchV2)
+		}
+		_, tx, err := decodeTxRLP(txsData, pos)
+		if err != nil {
+			return nil, fmt.Errorf("can't decode transaction: %w", err)
+		}
+		return tx, nil
+	}
+	return nil, nil
+}
+
 // DecodeForcedBatchV2 decodes a forced batch V2 (Etrog)
 // Is forbidden changeL2Block, so are just the set of transactions
 func DecodeForcedBatchV2(txsData []byte) (*ForcedBatchRawV2, error) {
@@ -217,6 +217,21 @@ func TestDecodeEncodeBatchV2(t *testing.T) {
 	require.Equal(t, batchL2Data, encoded)
 }
 
+func TestCheckNoEmptyL2BlocksBatchV2(t *testing.T) {
+	batchL2Data, err := hex.DecodeString(codedL2Block1 + codedL2Block2)
+	require.NoError(t, err)
+	decodedBatch, err := DecodeBatchV2(batchL2Data)
+	require.NoError(t, err)
+	require.NoError(t, decodedBatch.CheckNoEmptyL2Blocks())
+
+	emptyL2BlockData, err := hex.DecodeString(codedL2Block1 + codedL2BlockHeader)
+	require.NoError(t, err)
+	decodedBatch, err = DecodeBatchV2(emptyL2BlockData)
+	require.NoError(t, err)
+	require.Equal(t, 2, len(decodedBatch.Blocks))
+	require.ErrorIs(t, decodedBatch.CheckNoEmptyL2Blocks(), ErrBatchV2EmptyL2Block)
+}
+
 func TestEncodeEmptyBatchV2Fails(t *testing.T) {
 	l2Batch := BatchRawV2{}
 	_, err := EncodeBatchV2(&l2Batch)
@@ -239,3 +254,66 @@ func TestDecodeForcedBatchV2WithRegularBatch(t *testing.T) {
 	_, err = DecodeForcedBatchV2(batchL2Data)
 	require.Error(t, err)
 }
+
+func TestParseFirstTransaction(t *testing.T) {
+	batchL2Data, err := hex.DecodeString(codedL2BlockHeader + codedL2Block1 + codedL2Block2)
+	require.NoError(t, err)
+	decodedBatch, err := DecodeBatchV2(batchL2Data)
+	require.NoError(t, err)
+
+	tx, err := ParseFirstTransaction(batchL2Data)
+	require.NoError(t, err)
+	require.NotNil(t, tx)
+	expectedTx := decodedBatch.Blocks[1].Transactions[0]
+	require.Equal(t, expectedTx.Tx.Hash(), tx.Tx.Hash())
+	require.Equal(t, expectedTx.Tx.GasPrice(), tx.Tx.GasPrice())
+	require.Equal(t, expectedTx.EfficiencyPercentage, tx.EfficiencyPercentage)
+
+	// A batch without txs
+	batchL2Data, err = hex.DecodeString(codedL2BlockHeader + codedL2BlockHeader)
+	require.NoError(t, err)
+	tx, err = ParseFirstTransaction(batchL2Data)
+	require.NoError(t, err)
+	require.Nil(t, tx)
+
+	// A batch that doesn't start with a changeL2Block
+	batchL2Data, err = hex.DecodeString(codedRLP2Txs1)
+	require.NoError(t, err)
+	_, err = ParseFirstTransaction(batchL2Data)
+	require.ErrorIs(t, err, ErrBatchV2DontStartWithChangeL2Block)
+
+	// A truncated first tx
+	batchL2Data, err = hex.DecodeString(codedL2BlockHeader + codedRLP2Txs1[:20])
+	require.NoError(t, err)
+	_, err = ParseFirstTransaction(batchL2Data)
+	require.ErrorIs(t, err, ErrInvalidBatchV2)
+}
+
+func benchmarkBatchV2Data(b *testing.B) []byte {
+	codedBatch := ""
+	for i := 0; i < 500; i++ {
+		codedBatch += codedL2Block1
+	}
+	batchL2Data, err := hex.DecodeString(codedBatch)
+	require.NoError(b, err)
+	return batchL2Data
+}
+
+func BenchmarkParseFirstTransaction(b *testing.B) {
+	batchL2Data := benchmarkBatchV2Data(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		_, err := ParseFirstTransaction(batchL2Data)
+		require.NoError(b, err)
+	}
+}
+
+func BenchmarkDecodeBatchV2FirstTransaction(b *testing.B) {
+	batchL2Data := benchmarkBatchV2Data(b)
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		batch, err := DecodeBatchV2(batchL2Data)
+		require.NoError(b, err)
+		_ = batch.Blocks[0].Transactions[0]
+	}
+}
@@ -16,6 +16,8 @@ var (
 	ErrStateNotSynchronized = errors.New("state not synchronized")
 	// ErrNotFound indicates an object has not been found for the search criteria used
 	ErrNotFound = errors.New("object not found")
+	// ErrGERNotFound indicates the global exit root is unknown, it wraps ErrNotFound
+	ErrGERNotFound = fmt.Errorf("global exit root %w", ErrNotFound)
 	// ErrNilDBTransaction indicates the db transaction has not been properly initialized
 	ErrNilDBTransaction = errors.New("database transaction not properly initialized")
 	// ErrAlreadyInitializedDBTransaction indicates the db transaction was already initialized
@@ -32,6 +34,8 @@ var (
 	ErrBatchAlreadyClosed = errors.New("batch is already closed")
 	// ErrClosingBatchWithoutTxs indicates that the batch attempted to close does not have txs.
 	ErrClosingBatchWithoutTxs = errors.New("can not close a batch without transactions")
+	// ErrInvalidBatch indicates that the batch is not self-consistent and can't be stored
+	ErrInvalidBatch = errors.New("invalid batch")
 	// ErrTimestampGE indicates that timestamp needs to be greater or equal
 	ErrTimestampGE = errors.New("timestamp needs to be greater or equal")
 	// ErrDBTxNil indicates that the method requires a dbTx that is not nil
@@ -55,6 +59,8 @@ var (
 	ErrUnsupportedDuration = errors.New("unsupported time duration")
 	// ErrInvalidData is the error when the raw txs is unexpected
 	ErrInvalidData = errors.New("invalid data")
+	// ErrZKCountersEstimationOverflow is the error when the estimated zk counters of a tx overflow
+	ErrZKCountersEstimationOverflow = errors.New("estimated zk counters overflow")
 	// ErrBatchResourceBytesUnderflow happens when the batch runs out of Bytes
 	ErrBatchResourceBytesUnderflow = NewBatchRemainingResourcesUnderflowError(nil, "Bytes")
 	// ErrInvalidBlockRange returned when the selected block range is invalid, generally
@@ -69,6 +75,12 @@ var (
 	// ErrMaxNativeBlockHashBlockRangeLimitExceeded returned when the range between block number range
 	// to filter native block hashes is bigger than the configured limit
 	ErrMaxNativeBlockHashBlockRangeLimitExceeded = errors.New("native block hashes are limited to a %v block range")
+	// ErrMaxForcedBatchRangeLimitExceeded returned when the range between L1 block number range
+	// to filter forced batches is bigger than the configured limit
+	ErrMaxForcedBatchRangeLimitExceeded = errors.New("forced batches are limited to a %v L1 block range")
+	// ErrMaxInlineReceiptTxsLimitExceeded returned when the number of txs of a block to be returned
+	// with the receipts inline is bigger than the configured limit
+	ErrMaxInlineReceiptTxsLimitExceeded = errors.New("blocks with receipts inline are limited to %v transactions")
 
 	zkCounterErrPrefix = "ZKCounter: "
 )
@@ -2,12 +2,14 @@ package state
 
 import (
 	"fmt"
+	"math"
 	"math/big"
 	"sort"
 	"strconv"
 
 	"github.com/0xPolygonHermez/zkevm-node/hex"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
 	"github.com/ethereum/go-ethereum/rlp"
 )
@@ -117,6 +119,113 @@ func EncodeTransaction(tx types.Transaction, effectivePercentage uint8, forkID u
 	return EncodeTransactions([]types.Transaction{tx}, []uint8{effectivePercentage}, forkID)
 }
 
+// EstimateEncodedSize returns the size in bytes that the given transaction will take once
+// encoded in the BatchL2Data (including the effective percentage byte). The size is computed
+// from the tx fields without encoding the tx, so it can be used to discard oversized txs
+// before they are processed by the executor
+func EstimateEncodedSize(tx *types.Transaction) uint64 {
+	size := rlpUintSize(tx.Nonce()) + rlpBigIntSize(tx.GasPrice()) + rlpUintSize(tx.Gas()) + rlpBigIntSize(tx.Value()) + rlpBytesSize(tx.Data())
+	if tx.To() == nil {
+		size += headerByteLength
+	} else {
+		size += headerByteLength + common.AddressLength
+	}
+
+	if !IsPreEIP155Tx(*tx) {
+		// chainID, 0, 0
+		size += rlpBigIntSize(tx.ChainId()) + headerByteLength + headerByteLength
+	}
+
+	return rlpLengthPrefixSize(size) + size + rLength + sLength + vLength + EfficiencyPercentageByteLength
+}
+
+// Coefficients of the heuristic model used by EstimateZKCounters. They are rough guesses, they don't
+// come from the executor nor from a fit of the zk counters it returned for processed txs
+const (
+	estimatedStepsPerDataByte   = 8
+	estimatedGasPerStep         = 10
+	estimatedGasPerPoseidonHash = 100
+	estimatedGasPerArithmetic   = 1000
+	estimatedGasPerBinary       = 100
+	keccakHashRateBytes         = 136
+	poseidonPaddingRateBytes    = 56
+	memAlignBytes               = 32
+)
+
+// EstimateZKCounters returns a heuristic estimation of the zk counters used by the tx, computed from its
+// gas limit and data length without processing it. It isn't based on the executor counters and can be far
+// from them, so it must only be used as a hint to order the txs. The actual counters are only known after
+// processing the tx with the executor
+func EstimateZKCounters(tx *types.Transaction) (ZKCounters, error) {
+	if tx == nil {
+		return ZKCounters{}, fmt.Errorf("can't estimate zk counters of a nil tx")
+	}
+
+	dataLen := uint64(len(tx.Data()))
+	gas := tx.Gas()
+
+	steps := dataLen*estimatedStepsPerDataByte + gas/estimatedGasPerStep
+	poseidonHashes := gas / estimatedGasPerPoseidonHash
+	binaries := dataLen + gas/estimatedGasPerBinary
+	if steps > math.MaxUint32 || poseidonHashes > math.MaxUint32 || binaries > math.MaxUint32 {
+		return ZKCounters{}, ErrZKCountersEstimationOverflow
+	}
+
+	return ZKCounters{
+		GasUsed: gas,
+		// The tx hash plus the hash of the encoded tx data
+		UsedKeccakHashes:     uint32(1 + EstimateEncodedSize(tx)/keccakHashRateBytes),
+		UsedPoseidonHashes:   uint32(poseidonHashes),
+		UsedPoseidonPaddings: uint32(dataLen / poseidonPaddingRateBytes),
+		UsedMemAligns:        uint32(dataLen / memAlignBytes),
+		// The signature recovery plus the arithmetic operations of the tx execution
+		UsedArithmetics: uint32(1 + gas/estimatedGasPerArithmetic),
+		UsedBinaries:    uint32(binaries),
+		UsedSteps:       uint32(steps),
+	}, nil
+}
+
+// rlpUintSize returns the size of the rlp encoding of the given uint
+func rlpUintSize(value uint64) uint64 {
+	if value < 128 { //nolint:gomnd
+		return headerByteLength
+	}
+	return headerByteLength + bytesLength(value)
+}
+
+// rlpBigIntSize returns the size of the rlp encoding of the given big int
+func rlpBigIntSize(value *big.Int) uint64 {
+	if value == nil || value.BitLen() <= 7 { //nolint:gomnd
+		return headerByteLength
+	}
+	return headerByteLength + uint64((value.BitLen()+7)/8) //nolint:gomnd
+}
+
+// rlpBytesSize returns the size of the rlp encoding of the given byte array
+func rlpBytesSize(data []byte) uint64 {
+	if len(data) == 1 && data[0] < 128 { //nolint:gomnd
+		return headerByteLength
+	}
+	return rlpLengthPrefixSize(uint64(len(data))) + uint64(len(data))
+}
+
+// rlpLengthPrefixSize returns the size of the rlp prefix of a string or list with the given length
+func rlpLengthPrefixSize(length uint64) uint64 {
+	if length <= shortRlp {
+		return headerByteLength
+	}
+	return headerByteLength + bytesLength(length)
+}
+
+// bytesLength returns the minimum number of bytes needed to represent the given value
+func bytesLength(value uint64) uint64 {
+	length := uint64(0)
+	for ; value > 0; value >>= 8 {
+		length++
+	}
+	return length
+}
+
 // EncodeUnsignedTransaction RLP encodes the given unsigned transaction
 func EncodeUnsignedTransaction(tx types.Transaction, chainID uint64, forcedNonce *uint64, forkID uint64) ([]byte, error) {
 	v, _ := new(big.Int).SetString("0x1c", 0)
@@ -0,0 +1,65 @@
+package state
+
+import (
+	"crypto/sha256"
+	"maps"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/lru"
+)
+
+const (
+	// l1InfoTreeDataCacheSize is the max number of BatchL2Data whose L1InfoTree data is kept in the cache
+	l1InfoTreeDataCacheSize = 100
+)
+
+type l1InfoTreeDataCacheEntry struct {
+	l1InfoTreeData map[uint32]L1DataV2
+	l1InfoRoot     common.Hash
+	expiresAt      time.Time
+}
+
+// l1InfoTreeDataCache keeps the result of GetL1InfoTreeDataFromBatchL2Data for a short time,
+// so processing several times the same BatchL2Data doesn't query again the L1InfoTree
+type l1InfoTreeDataCache struct {
+	ttl   time.Duration
+	cache *lru.Cache[[sha256.Size]byte, l1InfoTreeDataCacheEntry]
+	now   func() time.Time
+}
+
+func newL1InfoTreeDataCache(size int, ttl time.Duration) *l1InfoTreeDataCache {
+	return &l1InfoTreeDataCache{
+		ttl:   ttl,
+		cache: lru.NewCache[[sha256.Size]byte, l1InfoTreeDataCacheEntry](size),
+		now:   time.Now,
+	}
+}
+
+// get returns the L1InfoTree data of batchL2Data if it is in the cache and has not expired
+func (c *l1InfoTreeDataCache) get(batchL2Data []byte) (map[uint32]L1DataV2, common.Hash, bool) {
+	key := sha256.Sum256(batchL2Data)
+	entry, found := c.cache.Get(key)
+	if !found {
+		return nil, ZeroHash, false
+	}
+	if c.now().After(entry.expiresAt) {
+		c.cache.Remove(key)
+		return nil, ZeroHash, false
+	}
+	return maps.Clone(entry.l1InfoTreeData), entry.l1InfoRoot, true
+}
+
+// add stores the L1InfoTree data of batchL2Data in the cache
+func (c *l1InfoTreeDataCache) add(batchL2Data []byte, l1InfoTreeData map[uint32]L1DataV2, l1InfoRoot common.Hash) {
+	c.cache.Add(sha256.Sum256(batchL2Data), l1InfoTreeDataCacheEntry{
+		l1InfoTreeData: maps.Clone(l1InfoTreeData),
+		l1InfoRoot:     l1InfoRoot,
+		expiresAt:      c.now().Add(c.ttl),
+	})
+}
+
+// clear removes all the cached L1InfoTree data
+func (c *l1InfoTreeDataCache) clear() {
+	c.cache.Purge()
+}
@@ -0,0 +1,46 @@
+package state
+
+import (
+	"testing"
+	"time"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+func TestL1InfoTreeDataCache(t *testing.T) {
+	now := time.Unix(1700000000, 0)
+	cache := newL1InfoTreeDataCache(2, time.Minute)
+	cache.now = func() time.Time { return now }
+
+	batchL2Data := []byte{0x0b, 0x01}
+	l1InfoTreeData := map[uint32]L1DataV2{1: {GlobalExitRoot: common.HexToHash("0x1"), MinTimestamp: 10}}
+	l1InfoRoot := common.HexToHash("0x2")
+
+	_, _, found := cache.get(batchL2Data)
+	require.False(t, found)
+
+	cache.add(batchL2Data, l1InfoTreeData, l1InfoRoot)
+	data, root, found := cache.get(batchL2Data)
+	require.True(t, found)
+	require.Equal(t, l1InfoTreeData, data)
+	require.Equal(t, l1InfoRoot, root)
+
+	// the returned map is a copy, so modifying it doesn't change the cached one
+	delete(data, 1)
+	data, _, found = cache.get(batchL2Data)
+	require.True(t, found)
+	require.Equal(t, l1InfoTreeData, data)
+
+	_, _, found = cache.get([]byte{0x0b, 0x02})
+	require.False(t, found)
+
+	now = now.Add(time.Minute + time.Second)
+	_, _, found = cache.get(batchL2Data)
+	require.False(t, found)
+
+	cache.add(batchL2Data, l1InfoTreeData, l1InfoRoot)
+	cache.clear()
+	_, _, found = cache.get(batchL2Data)
+	require.False(t, found)
+}
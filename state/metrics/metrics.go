@@ -15,8 +15,12 @@ const (
 	Prefix = "state_"
 	// ExecutorProcessingTimeName is the name of the metric that shows the processing time in the executor.
 	ExecutorProcessingTimeName = Prefix + "executor_processing_time"
+	// BatchCountByClosingReasonName is the name of the metric that shows the number of closed batches by closing reason.
+	BatchCountByClosingReasonName = Prefix + "batch_count_by_closing_reason"
 	// CallerLabelName is the name of the label for the caller.
 	CallerLabelName = "caller"
+	// ClosingReasonLabelName is the name of the label for the closing reason.
+	ClosingReasonLabelName = "reason"
 
 	// SequencerCallerLabel is used when sequencer is calling the function
 	SequencerCallerLabel CallerLabel = "sequencer"
@@ -38,7 +42,18 @@ func Register() {
 		},
 	}
 
+	gaugeVecs := []metrics.GaugeVecOpts{
+		{
+			GaugeOpts: prometheus.GaugeOpts{
+				Name: BatchCountByClosingReasonName,
+				Help: "[STATE] number of closed batches by closing reason",
+			},
+			Labels: []string{ClosingReasonLabelName},
+		},
+	}
+
 	metrics.RegisterHistogramVecs(histogramVecs...)
+	metrics.RegisterGaugeVecs(gaugeVecs...)
 }
 
 // ExecutorProcessingTime observes the last processing time of the executor in the histogram vector by the provided elapsed time
@@ -47,3 +62,8 @@ func ExecutorProcessingTime(caller string, lastExecutionTime time.Duration) {
 	execTimeInSeconds := float64(lastExecutionTime) / float64(time.Second)
 	metrics.HistogramVecObserve(ExecutorProcessingTimeName, caller, execTimeInSeconds)
 }
+
+// BatchCountByClosingReason sets the number of closed batches for the given closing reason.
+func BatchCountByClosingReason(reason string, count int64) {
+	metrics.GaugeVecSet(BatchCountByClosingReasonName, reason, float64(count))
+}
@@ -6,6 +6,7 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math/big"
 	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/state"
@@ -83,7 +84,7 @@ func (p *PostgresStorage) GetVerifiedBatch(ctx context.Context, batchNumber uint
 
 // GetLastNBatches returns the last numBatches batches.
 func (p *PostgresStorage) GetLastNBatches(ctx context.Context, numBatches uint, dbTx pgx.Tx) ([]*state.Batch, error) {
-	const getLastNBatchesSQL = "SELECT batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, forced_batch_num, batch_resources, wip from state.batch ORDER BY batch_num DESC LIMIT $1"
+	const getLastNBatchesSQL = "SELECT batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, raw_txs_data_compressed, forced_batch_num, batch_resources, wip, l1_block_num, avg_effective_gas_price from state.batch ORDER BY batch_num DESC LIMIT $1"
 
 	e := p.getExecQuerier(dbTx)
 	rows, err := e.Query(ctx, getLastNBatchesSQL, numBatches)
@@ -107,6 +108,36 @@ func (p *PostgresStorage) GetLastNBatches(ctx context.Context, numBatches uint,
 	return batches, nil
 }
 
+// GetBatchesByStateRoot returns the batches that reference the provided state root, either as the
+// state root after processing the batch or as the initial state root, which is the state root of
+// the previous batch. The batches are sorted by batch number
+func (p *PostgresStorage) GetBatchesByStateRoot(ctx context.Context, stateRoot common.Hash, dbTx pgx.Tx) ([]*state.Batch, error) {
+	const getBatchesByStateRootSQL = `
+		SELECT batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, raw_txs_data_compressed, forced_batch_num, batch_resources, wip, l1_block_num, avg_effective_gas_price
+		  FROM state.batch
+		 WHERE state_root = $1
+		    OR batch_num IN (SELECT batch_num + 1 FROM state.batch WHERE state_root = $1)
+		 ORDER BY batch_num`
+
+	e := p.getExecQuerier(dbTx)
+	rows, err := e.Query(ctx, getBatchesByStateRootSQL, stateRoot.String())
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	batches := []*state.Batch{}
+	for rows.Next() {
+		batch, err := scanBatch(rows)
+		if err != nil {
+			return nil, err
+		}
+		batches = append(batches, &batch)
+	}
+
+	return batches, rows.Err()
+}
+
 // GetLastNBatchesByL2BlockNumber returns the last numBatches batches along with the l2 block state root by l2BlockNumber
 // if the l2BlockNumber parameter is nil, it means we want to get the most recent last N batches
 func (p *PostgresStorage) GetLastNBatchesByL2BlockNumber(ctx context.Context, l2BlockNumber *uint64, numBatches uint, dbTx pgx.Tx) ([]*state.Batch, common.Hash, error) {
@@ -119,6 +150,7 @@ func (p *PostgresStorage) GetLastNBatchesByL2BlockNumber(ctx context.Context, l2
                b.timestamp,
                b.coinbase,
                b.raw_txs_data,
+               b.raw_txs_data_compressed,
 			   b.wip,
                /* gets the state root of the l2 block with the highest number associated to the batch in the row */
                (SELECT l2b1.header->>'stateRoot'
@@ -256,7 +288,7 @@ func (p *PostgresStorage) SetInitSyncBatch(ctx context.Context, batchNumber uint
 // GetBatchByNumber returns the batch with the given number.
 func (p *PostgresStorage) GetBatchByNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.Batch, error) {
 	const getBatchByNumberSQL = `
-		SELECT batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, forced_batch_num, batch_resources, wip
+		SELECT batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, raw_txs_data_compressed, forced_batch_num, batch_resources, wip, l1_block_num, avg_effective_gas_price
 		  FROM state.batch 
 		 WHERE batch_num = $1`
 
@@ -273,10 +305,36 @@ func (p *PostgresStorage) GetBatchByNumber(ctx context.Context, batchNumber uint
 	return &batch, nil
 }
 
+// GetBatchByNumberForUpdate returns the batch with the given number locking its row until dbTx ends.
+// If the batch is being updated by another db transaction it waits for it to finish, so the batch
+// read is the one committed by it instead of a stale version
+func (p *PostgresStorage) GetBatchByNumberForUpdate(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.Batch, error) {
+	if dbTx == nil {
+		return nil, state.ErrDBTxNil
+	}
+
+	const getBatchByNumberForUpdateSQL = `
+		SELECT batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, raw_txs_data_compressed, forced_batch_num, batch_resources, wip, l1_block_num, avg_effective_gas_price
+		  FROM state.batch 
+		 WHERE batch_num = $1
+		   FOR UPDATE`
+
+	row := dbTx.QueryRow(ctx, getBatchByNumberForUpdateSQL, batchNumber)
+	batch, err := scanBatch(row)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, state.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	return &batch, nil
+}
+
 // GetBatchByTxHash returns the batch including the given tx
 func (p *PostgresStorage) GetBatchByTxHash(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*state.Batch, error) {
 	const getBatchByTxHashSQL = `
-		SELECT b.batch_num, b.global_exit_root, b.local_exit_root, b.acc_input_hash, b.state_root, b.timestamp, b.coinbase, b.raw_txs_data, b.forced_batch_num, b.batch_resources, b.wip
+		SELECT b.batch_num, b.global_exit_root, b.local_exit_root, b.acc_input_hash, b.state_root, b.timestamp, b.coinbase, b.raw_txs_data, b.raw_txs_data_compressed, b.forced_batch_num, b.batch_resources, b.wip, b.l1_block_num, b.avg_effective_gas_price
 		  FROM state.transaction t, state.batch b, state.l2block l 
 		  WHERE t.hash = $1 AND l.block_num = t.l2_block_num AND b.batch_num = l.batch_num`
 
@@ -295,7 +353,7 @@ func (p *PostgresStorage) GetBatchByTxHash(ctx context.Context, transactionHash
 // GetBatchByL2BlockNumber returns the batch related to the l2 block accordingly to the provided l2 block number.
 func (p *PostgresStorage) GetBatchByL2BlockNumber(ctx context.Context, l2BlockNumber uint64, dbTx pgx.Tx) (*state.Batch, error) {
 	const getBatchByL2BlockNumberSQL = `
-		SELECT bt.batch_num, bt.global_exit_root, bt.local_exit_root, bt.acc_input_hash, bt.state_root, bt.timestamp, bt.coinbase, bt.raw_txs_data, bt.forced_batch_num, bt.batch_resources, bt.wip
+		SELECT bt.batch_num, bt.global_exit_root, bt.local_exit_root, bt.acc_input_hash, bt.state_root, bt.timestamp, bt.coinbase, bt.raw_txs_data, bt.raw_txs_data_compressed, bt.forced_batch_num, bt.batch_resources, bt.wip, bt.l1_block_num, bt.avg_effective_gas_price
 		  FROM state.batch bt
 		 INNER JOIN state.l2block bl
 		    ON bt.batch_num = bl.batch_num
@@ -326,9 +384,12 @@ func (p *PostgresStorage) GetVirtualBatchByNumber(ctx context.Context, batchNumb
 			timestamp,
 			coinbase,
 			raw_txs_data,
+			raw_txs_data_compressed,
 			forced_batch_num,
 			batch_resources, 
-			wip
+			wip,
+			l1_block_num,
+			avg_effective_gas_price
 		FROM
 			state.batch
 		WHERE
@@ -382,16 +443,26 @@ func (p *PostgresStorage) IsSequencingTXSynced(ctx context.Context, transactionH
 	}
 	return exists, nil
 }
+
+// compressBatchL2Data returns the BatchL2Data to store in the DB, compressed if it is larger
+// than BatchL2DataCompressionThreshold, and the value of the raw_txs_data_compressed flag
+func (p *PostgresStorage) compressBatchL2Data(batchL2Data []byte) ([]byte, bool) {
+	return state.CompressBatchL2Data(batchL2Data, p.cfg.BatchL2DataCompressionThreshold)
+}
+
 func scanBatch(row pgx.Row) (state.Batch, error) {
 	batch := state.Batch{}
 	var (
-		gerStr        string
-		lerStr        *string
-		aihStr        *string
-		stateStr      *string
-		coinbaseStr   string
-		resourcesData []byte
-		wip           bool
+		gerStr                string
+		lerStr                *string
+		aihStr                *string
+		stateStr              *string
+		coinbaseStr           string
+		resourcesData         []byte
+		wip                   bool
+		l1BlockNum            *uint64
+		avgEffectiveGasPrice  *string
+		batchL2DataCompressed bool
 	)
 	err := row.Scan(
 		&batch.BatchNumber,
@@ -402,13 +473,20 @@ func scanBatch(row pgx.Row) (state.Batch, error) {
 		&batch.Timestamp,
 		&coinbaseStr,
 		&batch.BatchL2Data,
+		&batchL2DataCompressed,
 		&batch.ForcedBatchNum,
 		&resourcesData,
 		&wip,
+		&l1BlockNum,
+		&avgEffectiveGasPrice,
 	)
 	if err != nil {
 		return batch, err
 	}
+	batch.BatchL2Data, err = state.DecompressBatchL2Data(batch.BatchL2Data, batchL2DataCompressed)
+	if err != nil {
+		return batch, err
+	}
 	batch.GlobalExitRoot = common.HexToHash(gerStr)
 	if lerStr != nil {
 		batch.LocalExitRoot = common.HexToHash(*lerStr)
@@ -427,6 +505,16 @@ func scanBatch(row pgx.Row) (state.Batch, error) {
 		}
 	}
 	batch.WIP = wip
+	if l1BlockNum != nil {
+		batch.L1BlockNumber = *l1BlockNum
+	}
+	if avgEffectiveGasPrice != nil {
+		var ok bool
+		batch.EffectiveGasPrice, ok = new(big.Int).SetString(*avgEffectiveGasPrice, 10) //nolint:gomnd
+		if !ok {
+			return batch, fmt.Errorf("invalid average effective gas price %s for batch %d", *avgEffectiveGasPrice, batch.BatchNumber)
+		}
+	}
 
 	batch.Coinbase = common.HexToAddress(coinbaseStr)
 	return batch, nil
@@ -435,13 +523,14 @@ func scanBatch(row pgx.Row) (state.Batch, error) {
 func scanBatchWithL2BlockStateRoot(row pgx.Row) (state.Batch, *common.Hash, error) {
 	batch := state.Batch{}
 	var (
-		gerStr              string
-		lerStr              *string
-		aihStr              *string
-		stateStr            *string
-		coinbaseStr         string
-		l2BlockStateRootStr *string
-		wip                 bool
+		gerStr                string
+		lerStr                *string
+		aihStr                *string
+		stateStr              *string
+		coinbaseStr           string
+		l2BlockStateRootStr   *string
+		wip                   bool
+		batchL2DataCompressed bool
 	)
 	if err := row.Scan(
 		&batch.BatchNumber,
@@ -452,11 +541,17 @@ func scanBatchWithL2BlockStateRoot(row pgx.Row) (state.Batch, *common.Hash, erro
 		&batch.Timestamp,
 		&coinbaseStr,
 		&batch.BatchL2Data,
+		&batchL2DataCompressed,
 		&wip,
 		&l2BlockStateRootStr,
 	); err != nil {
 		return batch, nil, err
 	}
+	var err error
+	batch.BatchL2Data, err = state.DecompressBatchL2Data(batch.BatchL2Data, batchL2DataCompressed)
+	if err != nil {
+		return batch, nil, err
+	}
 	batch.GlobalExitRoot = common.HexToHash(gerStr)
 	if lerStr != nil {
 		batch.LocalExitRoot = common.HexToHash(*lerStr)
@@ -541,6 +636,42 @@ func (p *PostgresStorage) GetVirtualBatch(ctx context.Context, batchNumber uint6
 	return &virtualBatch, nil
 }
 
+// GetVirtualBatchesBySequencerAddress returns the virtual batches in the range [fromBatchNumber, toBatchNumber]
+// sequenced by the provided sequencer address, ordered by batch number
+func (p *PostgresStorage) GetVirtualBatchesBySequencerAddress(ctx context.Context, sequencerAddr common.Address, fromBatchNumber, toBatchNumber uint64, dbTx pgx.Tx) ([]state.VirtualBatch, error) {
+	const getVirtualBatchesBySequencerAddressSQL = `
+    SELECT block_num, batch_num, tx_hash, coinbase, sequencer_addr, timestamp_batch_etrog
+      FROM state.virtual_batch
+     WHERE sequencer_addr = $1 AND batch_num >= $2 AND batch_num <= $3
+     ORDER BY batch_num ASC`
+
+	e := p.getExecQuerier(dbTx)
+	rows, err := e.Query(ctx, getVirtualBatchesBySequencerAddressSQL, sequencerAddr.String(), fromBatchNumber, toBatchNumber)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	virtualBatches := []state.VirtualBatch{}
+	for rows.Next() {
+		var (
+			virtualBatch  state.VirtualBatch
+			txHash        string
+			coinbase      string
+			sequencerAddr string
+		)
+		err := rows.Scan(&virtualBatch.BlockNumber, &virtualBatch.BatchNumber, &txHash, &coinbase, &sequencerAddr, &virtualBatch.TimestampBatchEtrog)
+		if err != nil {
+			return nil, err
+		}
+		virtualBatch.Coinbase = common.HexToAddress(coinbase)
+		virtualBatch.SequencerAddr = common.HexToAddress(sequencerAddr)
+		virtualBatch.TxHash = common.HexToHash(txHash)
+		virtualBatches = append(virtualBatches, virtualBatch)
+	}
+	return virtualBatches, rows.Err()
+}
+
 func (p *PostgresStorage) StoreGenesisBatch(ctx context.Context, batch state.Batch, dbTx pgx.Tx) error {
 	const addGenesisBatchSQL = "INSERT INTO state.batch (batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, forced_batch_num, wip) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, FALSE)"
 
@@ -587,13 +718,14 @@ func (p *PostgresStorage) OpenBatchInStorage(ctx context.Context, batchContext s
 
 // OpenWIPBatchInStorage adds a new wip batch into the state storage
 func (p *PostgresStorage) OpenWIPBatchInStorage(ctx context.Context, batch state.Batch, dbTx pgx.Tx) error {
-	const openBatchSQL = "INSERT INTO state.batch (batch_num, global_exit_root, state_root, local_exit_root, timestamp, coinbase, forced_batch_num, raw_txs_data, batch_resources, wip) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, TRUE)"
+	const openBatchSQL = "INSERT INTO state.batch (batch_num, global_exit_root, state_root, local_exit_root, timestamp, coinbase, forced_batch_num, raw_txs_data, raw_txs_data_compressed, batch_resources, wip, l1_block_num) VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, TRUE, $11)"
 
 	resourcesData, err := json.Marshal(batch.Resources)
 	if err != nil {
 		return err
 	}
 	resources := string(resourcesData)
+	batchL2Data, batchL2DataCompressed := p.compressBatchL2Data(batch.BatchL2Data)
 
 	e := p.getExecQuerier(dbTx)
 	_, err = e.Exec(
@@ -605,8 +737,10 @@ func (p *PostgresStorage) OpenWIPBatchInStorage(ctx context.Context, batch state
 		batch.Timestamp.UTC(),
 		batch.Coinbase.String(),
 		batch.ForcedBatchNum,
-		batch.BatchL2Data,
+		batchL2Data,
+		batchL2DataCompressed,
 		resources,
+		batch.L1BlockNumber,
 	)
 	return err
 }
@@ -614,30 +748,36 @@ func (p *PostgresStorage) OpenWIPBatchInStorage(ctx context.Context, batch state
 // CloseBatchInStorage closes a batch in the state storage
 func (p *PostgresStorage) CloseBatchInStorage(ctx context.Context, receipt state.ProcessingReceipt, dbTx pgx.Tx) error {
 	const closeBatchSQL = `UPDATE state.batch 
-		SET state_root = $1, local_exit_root = $2, acc_input_hash = $3, raw_txs_data = $4, batch_resources = $5, closing_reason = $6, wip = FALSE
-		  WHERE batch_num = $7`
+		SET state_root = $1, local_exit_root = $2, acc_input_hash = $3, raw_txs_data = $4, raw_txs_data_compressed = $5, batch_resources = $6, closing_reason = $7, wip = FALSE
+		  WHERE batch_num = $8`
 
 	e := p.getExecQuerier(dbTx)
 	batchResourcesJsonBytes, err := json.Marshal(receipt.BatchResources)
 	if err != nil {
 		return err
 	}
+	batchL2Data, batchL2DataCompressed := p.compressBatchL2Data(receipt.BatchL2Data)
 	_, err = e.Exec(ctx, closeBatchSQL, receipt.StateRoot.String(), receipt.LocalExitRoot.String(),
-		receipt.AccInputHash.String(), receipt.BatchL2Data, string(batchResourcesJsonBytes), receipt.ClosingReason, receipt.BatchNumber)
+		receipt.AccInputHash.String(), batchL2Data, batchL2DataCompressed, string(batchResourcesJsonBytes), receipt.ClosingReason, receipt.BatchNumber)
 
 	return err
 }
 
 // CloseWIPBatchInStorage is used by sequencer to close the wip batch in the state storage
 func (p *PostgresStorage) CloseWIPBatchInStorage(ctx context.Context, receipt state.ProcessingReceipt, dbTx pgx.Tx) error {
-	const closeWIPBatchSQL = `UPDATE state.batch SET batch_resources = $1, closing_reason = $2, wip = FALSE WHERE batch_num = $3`
+	const closeWIPBatchSQL = `UPDATE state.batch SET batch_resources = $1, closing_reason = $2, avg_effective_gas_price = $3, wip = FALSE WHERE batch_num = $4`
 
 	e := p.getExecQuerier(dbTx)
 	batchResourcesJsonBytes, err := json.Marshal(receipt.BatchResources)
 	if err != nil {
 		return err
 	}
-	_, err = e.Exec(ctx, closeWIPBatchSQL, string(batchResourcesJsonBytes), receipt.ClosingReason, receipt.BatchNumber)
+	var avgEffectiveGasPrice *string
+	if receipt.EffectiveGasPrice != nil {
+		egp := receipt.EffectiveGasPrice.String()
+		avgEffectiveGasPrice = &egp
+	}
+	_, err = e.Exec(ctx, closeWIPBatchSQL, string(batchResourcesJsonBytes), receipt.ClosingReason, avgEffectiveGasPrice, receipt.BatchNumber)
 
 	return err
 }
@@ -645,7 +785,7 @@ func (p *PostgresStorage) CloseWIPBatchInStorage(ctx context.Context, receipt st
 // GetWIPBatchInStorage returns the wip batch in the state
 func (p *PostgresStorage) GetWIPBatchInStorage(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.Batch, error) {
 	const getWIPBatchByNumberSQL = `
-		SELECT batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, forced_batch_num, batch_resources, wip
+		SELECT batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, raw_txs_data_compressed, forced_batch_num, batch_resources, wip, l1_block_num, avg_effective_gas_price
 		  FROM state.batch 
 		 WHERE batch_num = $1 AND wip = TRUE`
 
@@ -758,9 +898,12 @@ func (p *PostgresStorage) GetVirtualBatchToProve(ctx context.Context, lastVerfie
 			b.timestamp,
 			b.coinbase,
 			b.raw_txs_data,
+			b.raw_txs_data_compressed,
 			b.forced_batch_num,
 			b.batch_resources, 
-			b.wip
+			b.wip,
+			b.l1_block_num,
+			b.avg_effective_gas_price
 		FROM
 			state.batch b,
 			state.virtual_batch v
@@ -823,7 +966,7 @@ func (p *PostgresStorage) GetSequences(ctx context.Context, lastVerifiedBatchNum
 // GetLastClosedBatch returns the latest closed batch
 func (p *PostgresStorage) GetLastClosedBatch(ctx context.Context, dbTx pgx.Tx) (*state.Batch, error) {
 	const getLastClosedBatchSQL = `
-		SELECT bt.batch_num, bt.global_exit_root, bt.local_exit_root, bt.acc_input_hash, bt.state_root, bt.timestamp, bt.coinbase, bt.raw_txs_data, bt.batch_resources, bt.wip
+		SELECT bt.batch_num, bt.global_exit_root, bt.local_exit_root, bt.acc_input_hash, bt.state_root, bt.timestamp, bt.coinbase, bt.raw_txs_data, bt.raw_txs_data_compressed, bt.forced_batch_num, bt.batch_resources, bt.wip, bt.l1_block_num, bt.avg_effective_gas_price
 			FROM state.batch bt
 			WHERE wip = FALSE
 			ORDER BY bt.batch_num DESC
@@ -863,26 +1006,90 @@ func (p *PostgresStorage) GetLastClosedBatchNumber(ctx context.Context, dbTx pgx
 
 // UpdateBatchL2Data updates data tx data in a batch
 func (p *PostgresStorage) UpdateBatchL2Data(ctx context.Context, batchNumber uint64, batchL2Data []byte, dbTx pgx.Tx) error {
-	const updateL2DataSQL = "UPDATE state.batch SET raw_txs_data = $2 WHERE batch_num = $1"
+	const updateL2DataSQL = "UPDATE state.batch SET raw_txs_data = $2, raw_txs_data_compressed = $3 WHERE batch_num = $1"
+
+	compressedBatchL2Data, batchL2DataCompressed := p.compressBatchL2Data(batchL2Data)
 
 	e := p.getExecQuerier(dbTx)
-	_, err := e.Exec(ctx, updateL2DataSQL, batchNumber, batchL2Data)
+	_, err := e.Exec(ctx, updateL2DataSQL, batchNumber, compressedBatchL2Data, batchL2DataCompressed)
 	return err
 }
 
 // UpdateWIPBatch updates the data in a batch
 func (p *PostgresStorage) UpdateWIPBatch(ctx context.Context, receipt state.ProcessingReceipt, dbTx pgx.Tx) error {
-	const updateL2DataSQL = "UPDATE state.batch SET raw_txs_data = $2, global_exit_root = $3, state_root = $4, local_exit_root = $5, batch_resources = $6 WHERE batch_num = $1"
+	const updateL2DataSQL = "UPDATE state.batch SET raw_txs_data = $2, raw_txs_data_compressed = $3, global_exit_root = $4, state_root = $5, local_exit_root = $6, batch_resources = $7, used_resources = $8 WHERE batch_num = $1"
 
 	e := p.getExecQuerier(dbTx)
 	batchResourcesJsonBytes, err := json.Marshal(receipt.BatchResources)
 	if err != nil {
 		return err
 	}
-	_, err = e.Exec(ctx, updateL2DataSQL, receipt.BatchNumber, receipt.BatchL2Data, receipt.GlobalExitRoot.String(), receipt.StateRoot.String(), receipt.LocalExitRoot.String(), string(batchResourcesJsonBytes))
+	var usedResources *string
+	if receipt.UsedResources != nil {
+		usedResourcesJsonBytes, err := json.Marshal(receipt.UsedResources)
+		if err != nil {
+			return err
+		}
+		usedResourcesStr := string(usedResourcesJsonBytes)
+		usedResources = &usedResourcesStr
+	}
+	batchL2Data, batchL2DataCompressed := p.compressBatchL2Data(receipt.BatchL2Data)
+	_, err = e.Exec(ctx, updateL2DataSQL, receipt.BatchNumber, batchL2Data, batchL2DataCompressed, receipt.GlobalExitRoot.String(), receipt.StateRoot.String(), receipt.LocalExitRoot.String(), string(batchResourcesJsonBytes), usedResources)
 	return err
 }
 
+// GetWIPBatchUsedResources returns the used resources stored for the given wip batch, nil if they are unknown
+func (p *PostgresStorage) GetWIPBatchUsedResources(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) (*state.BatchResources, error) {
+	const getUsedResourcesSQL = "SELECT used_resources FROM state.batch WHERE batch_num = $1 AND wip = TRUE"
+
+	var usedResourcesData []byte
+	e := p.getExecQuerier(dbTx)
+	err := e.QueryRow(ctx, getUsedResourcesSQL, batchNumber).Scan(&usedResourcesData)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, state.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	if usedResourcesData == nil {
+		return nil, nil
+	}
+
+	usedResources := &state.BatchResources{}
+	err = json.Unmarshal(usedResourcesData, usedResources)
+	if err != nil {
+		return nil, err
+	}
+	return usedResources, nil
+}
+
+// GetBatchCountByClosingReason returns the number of batches closed with the given closing reason
+func (p *PostgresStorage) GetBatchCountByClosingReason(ctx context.Context, reason state.ClosingReason, dbTx pgx.Tx) (int64, error) {
+	const getBatchCountByClosingReasonSQL = "SELECT COUNT(*) FROM state.batch WHERE closing_reason = $1"
+
+	var count int64
+	q := p.getExecQuerier(dbTx)
+	err := q.QueryRow(ctx, getBatchCountByClosingReasonSQL, string(reason)).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetBatchCountByClosingReasonInRange returns the number of batches in the range [fromBatchNumber, toBatchNumber]
+// closed with the given closing reason
+func (p *PostgresStorage) GetBatchCountByClosingReasonInRange(ctx context.Context, reason state.ClosingReason, fromBatchNumber, toBatchNumber uint64, dbTx pgx.Tx) (int64, error) {
+	const getBatchCountByClosingReasonInRangeSQL = "SELECT COUNT(*) FROM state.batch WHERE closing_reason = $1 AND batch_num >= $2 AND batch_num <= $3"
+
+	var count int64
+	q := p.getExecQuerier(dbTx)
+	err := q.QueryRow(ctx, getBatchCountByClosingReasonInRangeSQL, string(reason), fromBatchNumber, toBatchNumber).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
 // AddAccumulatedInputHash adds the accumulated input hash
 func (p *PostgresStorage) AddAccumulatedInputHash(ctx context.Context, batchNum uint64, accInputHash common.Hash, dbTx pgx.Tx) error {
 	const addAccInputHashBatchSQL = "UPDATE state.batch SET acc_input_hash = $1 WHERE batch_num = $2"
@@ -0,0 +1,62 @@
+package pgstatestorage
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync/atomic"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	"github.com/jackc/pgx/v4"
+)
+
+// batchIteratorCursorID is used to give a unique name to the cursor of each iterator
+var batchIteratorCursorID atomic.Uint64
+
+// batchIterator is a state.BatchIterator backed by a server-side cursor
+type batchIterator struct {
+	dbTx       pgx.Tx
+	cursorName string
+	closed     bool
+}
+
+// NewBatchIterator returns an iterator over the batches, in ascending order, starting at fromBatchNumber.
+// Batches are fetched one at a time from a server-side cursor, so the dbTx is required and the iterator
+// must be closed before the dbTx is committed or rolled back.
+func (p *PostgresStorage) NewBatchIterator(ctx context.Context, fromBatchNumber uint64, dbTx pgx.Tx) (state.BatchIterator, error) {
+	if dbTx == nil {
+		return nil, state.ErrDBTxNil
+	}
+	cursorName := fmt.Sprintf("batch_iterator_%d", batchIteratorCursorID.Add(1))
+	declareBatchCursorSQL := "DECLARE " + cursorName + " NO SCROLL CURSOR FOR SELECT batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, raw_txs_data_compressed, forced_batch_num, batch_resources, wip, l1_block_num, avg_effective_gas_price FROM state.batch WHERE batch_num >= $1 ORDER BY batch_num ASC"
+	if _, err := dbTx.Exec(ctx, declareBatchCursorSQL, fromBatchNumber); err != nil {
+		return nil, err
+	}
+	return &batchIterator{dbTx: dbTx, cursorName: cursorName}, nil
+}
+
+// Next returns the next batch, or nil if there are no more batches
+func (it *batchIterator) Next(ctx context.Context) (*state.Batch, error) {
+	if it.closed {
+		return nil, nil
+	}
+	batch, err := scanBatch(it.dbTx.QueryRow(ctx, "FETCH NEXT FROM "+it.cursorName))
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, nil
+	} else if err != nil {
+		return nil, err
+	}
+	return &batch, nil
+}
+
+// Close closes the server-side cursor
+func (it *batchIterator) Close() {
+	if it.closed {
+		return
+	}
+	it.closed = true
+	if _, err := it.dbTx.Exec(context.Background(), "CLOSE "+it.cursorName); err != nil {
+		log.Warnf("error closing cursor %s. Error: %v", it.cursorName, err)
+	}
+}
@@ -68,6 +68,51 @@ func (p *PostgresStorage) GetForcedBatchesSince(ctx context.Context, forcedBatch
 	return forcesBatches, nil
 }
 
+// GetForcedBatchesByL1BlockRange gets the L1 forced batches included in the L1 blocks
+// between fromBlock and toBlock, both included
+func (p *PostgresStorage) GetForcedBatchesByL1BlockRange(ctx context.Context, fromBlock, toBlock uint64, dbTx pgx.Tx) ([]state.ForcedBatch, error) {
+	const getForcedBatchesByL1BlockRangeSQL = `
+		SELECT forced_batch_num, global_exit_root, timestamp, raw_txs_data, coinbase, block_num
+		  FROM state.forced_batch
+		 WHERE block_num BETWEEN $1 AND $2
+		 ORDER BY forced_batch_num ASC`
+
+	if toBlock < fromBlock {
+		return nil, state.ErrInvalidBlockRange
+	}
+
+	q := p.getExecQuerier(dbTx)
+	rows, err := q.Query(ctx, getForcedBatchesByL1BlockRangeSQL, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	forcedBatches := []state.ForcedBatch{}
+
+	for rows.Next() {
+		var (
+			forcedBatch    state.ForcedBatch
+			globalExitRoot string
+			rawTxs         string
+			seq            string
+		)
+		err := rows.Scan(&forcedBatch.ForcedBatchNumber, &globalExitRoot, &forcedBatch.ForcedAt, &rawTxs, &seq, &forcedBatch.BlockNumber)
+		if err != nil {
+			return nil, err
+		}
+		forcedBatch.RawTxsData, err = hex.DecodeString(rawTxs)
+		if err != nil {
+			return nil, err
+		}
+		forcedBatch.Sequencer = common.HexToAddress(seq)
+		forcedBatch.GlobalExitRoot = common.HexToHash(globalExitRoot)
+		forcedBatches = append(forcedBatches, forcedBatch)
+	}
+
+	return forcedBatches, nil
+}
+
 // GetNextForcedBatches gets the next forced batches from the queue.
 func (p *PostgresStorage) GetNextForcedBatches(ctx context.Context, nextForcedBatches int, dbTx pgx.Tx) ([]state.ForcedBatch, error) {
 	const getNextForcedBatchesSQL = `
@@ -128,7 +173,7 @@ func (p *PostgresStorage) GetLastTrustedForcedBatchNumber(ctx context.Context, d
 // GetBatchByForcedBatchNum returns the batch with the given forced batch number.
 func (p *PostgresStorage) GetBatchByForcedBatchNum(ctx context.Context, forcedBatchNumber uint64, dbTx pgx.Tx) (*state.Batch, error) {
 	const getForcedBatchByNumberSQL = `
-		SELECT batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, forced_batch_num
+		SELECT batch_num, global_exit_root, local_exit_root, acc_input_hash, state_root, timestamp, coinbase, raw_txs_data, raw_txs_data_compressed, forced_batch_num, batch_resources, wip, l1_block_num, avg_effective_gas_price
 		  FROM state.batch
 		 WHERE forced_batch_num = $1`
 
@@ -48,6 +48,22 @@ func (p *PostgresStorage) GetLatestGlobalExitRoot(ctx context.Context, maxBlockN
 	return exitRoot, receivedAt, nil
 }
 
+// GetLastGlobalExitRoot returns the global exit root with the most recent timestamp
+func (p *PostgresStorage) GetLastGlobalExitRoot(ctx context.Context, dbTx pgx.Tx) (*state.GlobalExitRoot, error) {
+	var exitRoot state.GlobalExitRoot
+
+	const getLastGlobalExitRootSQL = "SELECT block_num, timestamp, mainnet_exit_root, rollup_exit_root, global_exit_root FROM state.exit_root ORDER BY timestamp DESC, id DESC LIMIT 1"
+
+	e := p.getExecQuerier(dbTx)
+	err := e.QueryRow(ctx, getLastGlobalExitRootSQL).Scan(&exitRoot.BlockNumber, &exitRoot.Timestamp, &exitRoot.MainnetExitRoot, &exitRoot.RollupExitRoot, &exitRoot.GlobalExitRoot)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, state.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+	return &exitRoot, nil
+}
+
 // GetNumberOfBlocksSinceLastGERUpdate gets number of blocks since last global exit root update
 func (p *PostgresStorage) GetNumberOfBlocksSinceLastGERUpdate(ctx context.Context, dbTx pgx.Tx) (uint64, error) {
 	var (
@@ -143,7 +159,7 @@ func (p *PostgresStorage) GetLatestGer(ctx context.Context, maxBlockNumber uint6
 }
 
 // GetExitRootByGlobalExitRoot returns the mainnet and rollup exit root given
-// a global exit root number.
+// a global exit root number. ErrGERNotFound is returned if the global exit root is unknown.
 func (p *PostgresStorage) GetExitRootByGlobalExitRoot(ctx context.Context, ger common.Hash, dbTx pgx.Tx) (*state.GlobalExitRoot, error) {
 	var (
 		exitRoot state.GlobalExitRoot
@@ -156,7 +172,7 @@ func (p *PostgresStorage) GetExitRootByGlobalExitRoot(ctx context.Context, ger c
 	err = e.QueryRow(ctx, sql, ger).Scan(&exitRoot.BlockNumber, &exitRoot.MainnetExitRoot, &exitRoot.RollupExitRoot, &exitRoot.GlobalExitRoot)
 
 	if errors.Is(err, pgx.ErrNoRows) {
-		return nil, state.ErrNotFound
+		return nil, state.ErrGERNotFound
 	} else if err != nil {
 		return nil, err
 	}
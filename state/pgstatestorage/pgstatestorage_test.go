@@ -207,6 +207,174 @@ func TestGetBatchByL2BlockNumber(t *testing.T) {
 	require.NoError(t, dbTx.Commit(ctx))
 }
 
+func TestGetTransactionByBatchNumberAndIndex(t *testing.T) {
+	setup()
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	err = testState.AddBlock(ctx, block, dbTx)
+	assert.NoError(t, err)
+
+	batchNumber := uint64(1)
+	_, err = testState.Exec(ctx, "INSERT INTO state.batch (batch_num, wip) VALUES ($1,FALSE)", batchNumber)
+	assert.NoError(t, err)
+
+	time := time.Now()
+	const numBlocks, txsPerBlock = 2, 2
+
+	// The txs of the batch ordered by L2 block and index in the block
+	batchTxs := []*types.Transaction{}
+	for i := 0; i < numBlocks; i++ {
+		blockNumber := big.NewInt(int64(i) + 1)
+
+		transactions := []*types.Transaction{}
+		receipts := []*types.Receipt{}
+		for j := 0; j < txsPerBlock; j++ {
+			tx := types.NewTx(&types.LegacyTx{
+				Nonce:    uint64(i*txsPerBlock + j),
+				To:       nil,
+				Value:    new(big.Int),
+				Gas:      0,
+				GasPrice: big.NewInt(0),
+			})
+			transactions = append(transactions, tx)
+			receipts = append(receipts, &types.Receipt{
+				Type:              tx.Type(),
+				PostState:         state.ZeroHash.Bytes(),
+				CumulativeGasUsed: 0,
+				EffectiveGasPrice: big.NewInt(0),
+				BlockNumber:       blockNumber,
+				GasUsed:           tx.Gas(),
+				TxHash:            tx.Hash(),
+				TransactionIndex:  uint(j),
+				Status:            types.ReceiptStatusSuccessful,
+			})
+		}
+
+		header := state.NewL2Header(&types.Header{
+			Number:     blockNumber,
+			ParentHash: state.ZeroHash,
+			Coinbase:   state.ZeroAddress,
+			Root:       state.ZeroHash,
+			GasUsed:    1,
+			GasLimit:   10,
+			Time:       uint64(time.Unix()),
+		})
+
+		l2Block := state.NewL2Block(header, transactions, []*state.L2Header{}, receipts, &trie.StackTrie{})
+		for _, receipt := range receipts {
+			receipt.BlockHash = l2Block.Hash()
+		}
+
+		storeTxsEGPData := []state.StoreTxEGPData{}
+		for range transactions {
+			storeTxsEGPData = append(storeTxsEGPData, state.StoreTxEGPData{EGPLog: nil, EffectivePercentage: state.MaxEffectivePercentage})
+		}
+
+		err = pgStateStorage.AddL2Block(ctx, batchNumber, l2Block, receipts, storeTxsEGPData, dbTx)
+		require.NoError(t, err)
+
+		batchTxs = append(batchTxs, transactions...)
+	}
+
+	for i, expectedTx := range batchTxs {
+		tx, err := pgStateStorage.GetTransactionByBatchNumberAndIndex(ctx, batchNumber, uint64(i), dbTx)
+		require.NoError(t, err)
+		assert.Equal(t, expectedTx.Hash(), tx.Hash())
+	}
+
+	// Index out of the range of the txs of the batch
+	_, err = pgStateStorage.GetTransactionByBatchNumberAndIndex(ctx, batchNumber, uint64(len(batchTxs)), dbTx)
+	require.ErrorIs(t, err, state.ErrNotFound)
+
+	// Batch not found
+	_, err = pgStateStorage.GetTransactionByBatchNumberAndIndex(ctx, batchNumber+1, 0, dbTx)
+	require.ErrorIs(t, err, state.ErrNotFound)
+
+	require.NoError(t, dbTx.Commit(ctx))
+}
+
+func TestGetTxsGasUsedByBatchNumber(t *testing.T) {
+	initOrResetDB()
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Rollback(ctx)) }()
+	err = testState.AddBlock(ctx, block, dbTx)
+	assert.NoError(t, err)
+
+	batchNumber := uint64(1)
+	_, err = dbTx.Exec(ctx, "INSERT INTO state.batch (batch_num, wip) VALUES ($1,FALSE), ($2,FALSE)", batchNumber, batchNumber+1)
+	assert.NoError(t, err)
+
+	time := time.Now()
+	const numBlocks, txsPerBlock = 2, 2
+
+	// The gas used by the txs of the batch ordered by L2 block and index in the block
+	expectedTxsGasUsed := []uint64{}
+	for i := 0; i < numBlocks; i++ {
+		blockNumber := big.NewInt(int64(i) + 1)
+
+		transactions := []*types.Transaction{}
+		receipts := []*types.Receipt{}
+		for j := 0; j < txsPerBlock; j++ {
+			gasUsed := uint64(21000 * (i*txsPerBlock + j + 1))
+			tx := types.NewTx(&types.LegacyTx{
+				Nonce:    uint64(i*txsPerBlock + j),
+				To:       nil,
+				Value:    new(big.Int),
+				Gas:      gasUsed,
+				GasPrice: big.NewInt(0),
+			})
+			transactions = append(transactions, tx)
+			receipts = append(receipts, &types.Receipt{
+				Type:              tx.Type(),
+				PostState:         state.ZeroHash.Bytes(),
+				CumulativeGasUsed: 0,
+				EffectiveGasPrice: big.NewInt(0),
+				BlockNumber:       blockNumber,
+				GasUsed:           gasUsed,
+				TxHash:            tx.Hash(),
+				TransactionIndex:  uint(j),
+				Status:            types.ReceiptStatusSuccessful,
+			})
+			expectedTxsGasUsed = append(expectedTxsGasUsed, gasUsed)
+		}
+
+		header := state.NewL2Header(&types.Header{
+			Number:     blockNumber,
+			ParentHash: state.ZeroHash,
+			Coinbase:   state.ZeroAddress,
+			Root:       state.ZeroHash,
+			GasUsed:    1,
+			GasLimit:   10,
+			Time:       uint64(time.Unix()),
+		})
+
+		l2Block := state.NewL2Block(header, transactions, []*state.L2Header{}, receipts, &trie.StackTrie{})
+		for _, receipt := range receipts {
+			receipt.BlockHash = l2Block.Hash()
+		}
+
+		storeTxsEGPData := []state.StoreTxEGPData{}
+		for range transactions {
+			storeTxsEGPData = append(storeTxsEGPData, state.StoreTxEGPData{EGPLog: nil, EffectivePercentage: state.MaxEffectivePercentage})
+		}
+
+		err = pgStateStorage.AddL2Block(ctx, batchNumber, l2Block, receipts, storeTxsEGPData, dbTx)
+		require.NoError(t, err)
+	}
+
+	txsGasUsed, err := pgStateStorage.GetTxsGasUsedByBatchNumber(ctx, batchNumber, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, expectedTxsGasUsed, txsGasUsed)
+
+	// Batch without txs
+	txsGasUsed, err = pgStateStorage.GetTxsGasUsedByBatchNumber(ctx, batchNumber+1, dbTx)
+	require.NoError(t, err)
+	assert.Empty(t, txsGasUsed)
+}
+
 func TestAddAndGetSequences(t *testing.T) {
 	initOrResetDB()
 
@@ -322,6 +490,55 @@ func TestAddGlobalExitRoot(t *testing.T) {
 	assert.Equal(t, globalExitRoot.GlobalExitRoot, exit.GlobalExitRoot)
 }
 
+func TestGetLastGlobalExitRoot(t *testing.T) {
+	// Init database instance
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Commit(ctx)) }()
+
+	_, err = testState.GetLastGlobalExitRoot(ctx, dbTx)
+	require.ErrorIs(t, err, state.ErrNotFound)
+
+	block := &state.Block{
+		BlockNumber: 1,
+		BlockHash:   common.HexToHash("0x29e885edaf8e4b51e1d2e05f9da28161d2fb4f6b1d53827d9b80a23cf2d7d9f1"),
+		ParentHash:  common.HexToHash("0x29e885edaf8e4b51e1d2e05f9da28161d2fb4f6b1d53827d9b80a23cf2d7d9f1"),
+		ReceivedAt:  time.Now(),
+	}
+	err = testState.AddBlock(ctx, block, dbTx)
+	require.NoError(t, err)
+
+	now := time.Now().UTC().Truncate(time.Second)
+	latest := state.GlobalExitRoot{
+		BlockNumber:     1,
+		Timestamp:       now,
+		MainnetExitRoot: common.HexToHash("0x1"),
+		RollupExitRoot:  common.HexToHash("0x2"),
+		GlobalExitRoot:  common.HexToHash("0x3"),
+	}
+	older := state.GlobalExitRoot{
+		BlockNumber:     1,
+		Timestamp:       now.Add(-time.Minute),
+		MainnetExitRoot: common.HexToHash("0x4"),
+		RollupExitRoot:  common.HexToHash("0x5"),
+		GlobalExitRoot:  common.HexToHash("0x6"),
+	}
+	// The older one is added last to check the order is by timestamp
+	require.NoError(t, testState.AddGlobalExitRoot(ctx, &latest, dbTx))
+	require.NoError(t, testState.AddGlobalExitRoot(ctx, &older, dbTx))
+
+	ger, err := testState.GetLastGlobalExitRoot(ctx, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, latest.BlockNumber, ger.BlockNumber)
+	assert.Equal(t, latest.Timestamp.Unix(), ger.Timestamp.Unix())
+	assert.Equal(t, latest.MainnetExitRoot, ger.MainnetExitRoot)
+	assert.Equal(t, latest.RollupExitRoot, ger.RollupExitRoot)
+	assert.Equal(t, latest.GlobalExitRoot, ger.GlobalExitRoot)
+}
+
 func TestVerifiedBatch(t *testing.T) {
 	initOrResetDB()
 
@@ -447,6 +664,50 @@ func TestForcedBatch(t *testing.T) {
 	assert.Equal(t, forcedBatch.ForcedAt.Unix(), fb.ForcedAt.Unix())
 	assert.Equal(t, forcedBatch.GlobalExitRoot, fb.GlobalExitRoot)
 }
+
+func TestGetForcedBatchesByL1BlockRange(t *testing.T) {
+	// Init database instance
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Commit(ctx)) }()
+
+	for blockNumber := uint64(1); blockNumber <= 3; blockNumber++ {
+		block := &state.Block{
+			BlockNumber: blockNumber,
+			BlockHash:   common.BigToHash(new(big.Int).SetUint64(blockNumber)),
+			ReceivedAt:  time.Now(),
+		}
+		require.NoError(t, testState.AddBlock(ctx, block, dbTx))
+
+		forcedBatch := state.ForcedBatch{
+			BlockNumber:       blockNumber,
+			ForcedBatchNumber: blockNumber,
+			Sequencer:         common.HexToAddress("0x2536C2745Ac4A584656A830f7bdCd329c94e8F30"),
+			RawTxsData:        []byte{0x0b, byte(blockNumber)},
+			ForcedAt:          time.Now(),
+			GlobalExitRoot:    common.HexToHash("0x40a885edaf8e4b51e1d2e05f9da28161d2fb4f6b1d53827d9b80a23cf2d7d9a0"),
+		}
+		require.NoError(t, testState.AddForcedBatch(ctx, &forcedBatch, dbTx))
+	}
+
+	forcedBatches, err := testState.GetForcedBatchesByL1BlockRange(ctx, 2, 3, dbTx)
+	require.NoError(t, err)
+	require.Len(t, forcedBatches, 2)
+	assert.Equal(t, uint64(2), forcedBatches[0].ForcedBatchNumber)
+	assert.Equal(t, []byte{0x0b, 0x02}, forcedBatches[0].RawTxsData)
+	assert.Equal(t, uint64(3), forcedBatches[1].ForcedBatchNumber)
+
+	forcedBatches, err = testState.GetForcedBatchesByL1BlockRange(ctx, 4, 10, dbTx)
+	require.NoError(t, err)
+	assert.Empty(t, forcedBatches)
+
+	_, err = testState.GetForcedBatchesByL1BlockRange(ctx, 3, 2, dbTx)
+	require.ErrorIs(t, err, state.ErrInvalidBlockRange)
+}
+
 func TestCleanupLockedProofs(t *testing.T) {
 	require := require.New(t)
 	assert := assert.New(t)
@@ -561,6 +822,48 @@ func TestVirtualBatch(t *testing.T) {
 	require.NoError(t, dbTx.Commit(ctx))
 }
 
+func TestGetVirtualBatchesBySequencerAddress(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, dbTx.Rollback(ctx)) }()
+
+	err = testState.AddBlock(ctx, state.NewBlock(1), dbTx)
+	require.NoError(t, err)
+
+	sequencer1 := common.HexToAddress("0xf39Fd6e51aad88F6F4ce6aB8827279cffFb92266")
+	sequencer2 := common.HexToAddress("0x70997970C51812dc3A010C7d01b50e0d17dc79C8")
+	sequencers := []common.Address{sequencer1, sequencer1, sequencer2, sequencer1}
+	expected := []state.VirtualBatch{}
+	for i, sequencer := range sequencers {
+		batchNumber := uint64(i + 1)
+		_, err = testState.Exec(ctx, "INSERT INTO state.batch (batch_num, wip) VALUES ($1, FALSE)", batchNumber)
+		require.NoError(t, err)
+		virtualBatch := state.VirtualBatch{
+			BlockNumber:   1,
+			BatchNumber:   batchNumber,
+			Coinbase:      sequencer,
+			SequencerAddr: sequencer,
+			TxHash:        common.HexToHash("0x29e885edaf8e4b51e1d2e05f9da28161d2fb4f6b1d53827d9b80a23cf2d7d9f1"),
+		}
+		err = testState.AddVirtualBatch(ctx, &virtualBatch, dbTx)
+		require.NoError(t, err)
+		if sequencer == sequencer1 && batchNumber >= 2 {
+			expected = append(expected, virtualBatch)
+		}
+	}
+
+	virtualBatches, err := testState.GetVirtualBatchesBySequencerAddress(ctx, sequencer1, 2, 4, dbTx)
+	require.NoError(t, err)
+	require.Equal(t, expected, virtualBatches)
+
+	virtualBatches, err = testState.GetVirtualBatchesBySequencerAddress(ctx, common.HexToAddress("0x1"), 1, 4, dbTx)
+	require.NoError(t, err)
+	require.Empty(t, virtualBatches)
+}
+
 func TestForkIDs(t *testing.T) {
 	initOrResetDB()
 
@@ -831,6 +1134,248 @@ func TestGetBatchByNumber(t *testing.T) {
 	require.NoError(t, dbTx.Commit(ctx))
 }
 
+func TestGetBatchesByStateRoot(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+
+	root1 := common.HexToHash("0x1")
+	root2 := common.HexToHash("0x2")
+	root3 := common.HexToHash("0x3")
+
+	// Batches 2 and 3 share the same state root
+	batchStateRoots := []common.Hash{root1, root2, root2, root3}
+	for i, stateRoot := range batchStateRoots {
+		_, err = testState.Exec(ctx, `INSERT INTO state.batch
+		(batch_num, global_exit_root, local_exit_root, state_root, timestamp, coinbase, raw_txs_data, wip)
+		VALUES($1, '0x0000000000000000000000000000000000000000000000000000000000000000', '0x0000000000000000000000000000000000000000000000000000000000000000', $2, '2022-12-19 08:17:45.000', '0x0000000000000000000000000000000000000000', NULL, FALSE);
+		`, i+1, stateRoot.String())
+		require.NoError(t, err)
+	}
+
+	type testCase struct {
+		name            string
+		stateRoot       common.Hash
+		expectedBatches []uint64
+	}
+
+	testCases := []testCase{
+		{
+			name:            "state root of a single batch",
+			stateRoot:       root1,
+			expectedBatches: []uint64{1, 2},
+		},
+		{
+			name:            "state root shared by several batches",
+			stateRoot:       root2,
+			expectedBatches: []uint64{2, 3, 4},
+		},
+		{
+			name:            "state root of the last batch",
+			stateRoot:       root3,
+			expectedBatches: []uint64{4},
+		},
+		{
+			name:            "state root not found",
+			stateRoot:       common.HexToHash("0x4"),
+			expectedBatches: []uint64{},
+		},
+	}
+
+	for _, testCase := range testCases {
+		t.Run(testCase.name, func(t *testing.T) {
+			batches, err := testState.GetBatchesByStateRoot(ctx, testCase.stateRoot, dbTx)
+			require.NoError(t, err)
+
+			batchNumbers := []uint64{}
+			for _, batch := range batches {
+				batchNumbers = append(batchNumbers, batch.BatchNumber)
+			}
+			assert.Equal(t, testCase.expectedBatches, batchNumbers)
+		})
+	}
+
+	require.NoError(t, dbTx.Commit(ctx))
+}
+
+func TestGetBatchByNumberForUpdate(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	_, err := testState.Exec(ctx, `INSERT INTO state.batch
+	(batch_num, global_exit_root, local_exit_root, state_root, timestamp, coinbase, raw_txs_data, wip)
+	VALUES(1, '0x0000000000000000000000000000000000000000000000000000000000000000', '0x0000000000000000000000000000000000000000000000000000000000000000', '0xbf34f9a52a63229e90d1016011655bc12140bba5b771817b88cbf340d08dcbde', '2022-12-19 08:17:45.000', '0x0000000000000000000000000000000000000000', NULL, TRUE);
+	`)
+	require.NoError(t, err)
+
+	_, err = testState.GetBatchByNumberForUpdate(ctx, 1, nil)
+	require.ErrorIs(t, err, state.ErrDBTxNil)
+
+	// Close the batch in a db transaction that is not committed yet
+	writeDbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	_, err = writeDbTx.Exec(ctx, "UPDATE state.batch SET wip = FALSE WHERE batch_num = 1")
+	require.NoError(t, err)
+
+	readDbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+	defer func() { require.NoError(t, readDbTx.Commit(ctx)) }()
+
+	// Without locking the committed version of the batch is read
+	b, err := testState.GetBatchByNumber(ctx, 1, readDbTx)
+	require.NoError(t, err)
+	assert.True(t, b.WIP)
+
+	type result struct {
+		batch *state.Batch
+		err   error
+	}
+	resultCh := make(chan result, 1)
+	go func() {
+		b, err := testState.GetBatchByNumberForUpdate(ctx, 1, readDbTx)
+		resultCh <- result{batch: b, err: err}
+	}()
+
+	// The read waits for the db transaction that is closing the batch
+	select {
+	case <-resultCh:
+		t.Fatal("batch read for update while it was being updated by another db transaction")
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	require.NoError(t, writeDbTx.Commit(ctx))
+
+	select {
+	case res := <-resultCh:
+		require.NoError(t, res.err)
+		assert.Equal(t, uint64(1), res.batch.BatchNumber)
+		assert.False(t, res.batch.WIP)
+	case <-time.After(5 * time.Second):
+		t.Fatal("batch read for update did not finish after the other db transaction was committed")
+	}
+
+	_, err = testState.GetBatchByNumberForUpdate(ctx, 2, readDbTx)
+	require.ErrorIs(t, err, state.ErrNotFound)
+}
+
+func TestBatchIterator(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+
+	for i := 1; i <= 3; i++ {
+		_, err = testState.Exec(ctx, `INSERT INTO state.batch
+		(batch_num, global_exit_root, local_exit_root, state_root, timestamp, coinbase, raw_txs_data, wip)
+		VALUES($1, '0x0000000000000000000000000000000000000000000000000000000000000000', '0x0000000000000000000000000000000000000000000000000000000000000000', '0xbf34f9a52a63229e90d1016011655bc12140bba5b771817b88cbf340d08dcbde', '2022-12-19 08:17:45.000', '0x0000000000000000000000000000000000000000', NULL, FALSE);
+		`, i)
+		require.NoError(t, err)
+	}
+
+	_, err = testState.NewBatchIterator(ctx, 2, nil)
+	require.ErrorIs(t, err, state.ErrDBTxNil)
+
+	it, err := testState.NewBatchIterator(ctx, 2, dbTx)
+	require.NoError(t, err)
+	batchNumbers := []uint64{}
+	for {
+		b, err := it.Next(ctx)
+		require.NoError(t, err)
+		if b == nil {
+			break
+		}
+		batchNumbers = append(batchNumbers, b.BatchNumber)
+	}
+	it.Close()
+	assert.Equal(t, []uint64{2, 3}, batchNumbers)
+
+	b, err := it.Next(ctx)
+	require.NoError(t, err)
+	assert.Nil(t, b)
+
+	require.NoError(t, dbTx.Commit(ctx))
+}
+
+func TestWIPBatchUsedResources(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+
+	_, err = testState.Exec(ctx, `INSERT INTO state.batch
+	(batch_num, global_exit_root, local_exit_root, state_root, timestamp, coinbase, raw_txs_data, wip)
+	VALUES(1, '0x0000000000000000000000000000000000000000000000000000000000000000', '0x0000000000000000000000000000000000000000000000000000000000000000', '0xbf34f9a52a63229e90d1016011655bc12140bba5b771817b88cbf340d08dcbde', '2022-12-19 08:17:45.000', '0x0000000000000000000000000000000000000000', NULL, TRUE);
+	`)
+	require.NoError(t, err)
+
+	batchNum := uint64(1)
+	usedResources, err := testState.GetWIPBatchUsedResources(ctx, batchNum, dbTx)
+	require.NoError(t, err)
+	assert.Nil(t, usedResources)
+
+	receipt := state.ProcessingReceipt{
+		BatchNumber:    batchNum,
+		BatchResources: state.BatchResources{ZKCounters: state.ZKCounters{GasUsed: 21000}, Bytes: 100},
+		UsedResources:  &state.BatchResources{ZKCounters: state.ZKCounters{GasUsed: 21000, UsedSteps: 1000}, Bytes: 100},
+	}
+	err = testState.UpdateWIPBatch(ctx, receipt, dbTx)
+	require.NoError(t, err)
+
+	usedResources, err = testState.GetWIPBatchUsedResources(ctx, batchNum, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, receipt.UsedResources, usedResources)
+
+	_, err = testState.GetWIPBatchUsedResources(ctx, uint64(2), dbTx)
+	require.ErrorIs(t, err, state.ErrNotFound)
+
+	require.NoError(t, dbTx.Commit(ctx))
+}
+
+func TestGetBatchCountByClosingReason(t *testing.T) {
+	initOrResetDB()
+
+	ctx := context.Background()
+	dbTx, err := testState.BeginStateTransaction(ctx)
+	require.NoError(t, err)
+
+	closingReasons := []state.ClosingReason{
+		state.BatchFullClosingReason,
+		state.TimeoutResolutionDeadlineClosingReason,
+		state.BatchFullClosingReason,
+		state.ForcedBatchClosingReason,
+		state.BatchFullClosingReason,
+	}
+	for i, reason := range closingReasons {
+		_, err = testState.Exec(ctx, `INSERT INTO state.batch
+		(batch_num, global_exit_root, local_exit_root, state_root, timestamp, coinbase, raw_txs_data, closing_reason, wip)
+		VALUES($1, '0x0000000000000000000000000000000000000000000000000000000000000000', '0x0000000000000000000000000000000000000000000000000000000000000000', '0xbf34f9a52a63229e90d1016011655bc12140bba5b771817b88cbf340d08dcbde', '2022-12-19 08:17:45.000', '0x0000000000000000000000000000000000000000', NULL, $2, FALSE);
+		`, i+1, string(reason))
+		require.NoError(t, err)
+	}
+
+	count, err := testState.GetBatchCountByClosingReason(ctx, state.BatchFullClosingReason, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), count)
+
+	count, err = testState.GetBatchCountByClosingReason(ctx, state.GlobalExitRootDeadlineClosingReason, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(0), count)
+
+	count, err = testState.GetBatchCountByClosingReasonInRange(ctx, state.BatchFullClosingReason, 2, 4, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	count, err = testState.GetBatchCountByClosingReasonInRange(ctx, state.ForcedBatchClosingReason, 1, 5, dbTx)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), count)
+
+	require.NoError(t, dbTx.Commit(ctx))
+}
+
 func TestGetLogs(t *testing.T) {
 	initOrResetDB()
 
@@ -137,6 +137,35 @@ func (p *PostgresStorage) GetTxsHashesByBatchNumber(ctx context.Context, batchNu
 	return txs, nil
 }
 
+// GetTxsGasUsedByBatchNumber returns the gas used by each transaction of the given batch,
+// read from their receipts.
+func (p *PostgresStorage) GetTxsGasUsedByBatchNumber(ctx context.Context, batchNumber uint64, dbTx pgx.Tx) ([]uint64, error) {
+	const getTxsGasUsedByBatchNumberSQL = `
+		SELECT r.gas_used
+		  FROM state.receipt r
+		 INNER JOIN state.transaction t ON r.tx_hash = t.hash
+		 INNER JOIN state.l2block b ON t.l2_block_num = b.block_num
+		 WHERE b.batch_num = $1
+		 ORDER BY r.block_num ASC, r.tx_index ASC`
+
+	e := p.getExecQuerier(dbTx)
+	rows, err := e.Query(ctx, getTxsGasUsedByBatchNumberSQL, batchNumber)
+	if !errors.Is(err, pgx.ErrNoRows) && err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	txsGasUsed := make([]uint64, 0, len(rows.RawValues()))
+	for rows.Next() {
+		var gasUsed uint64
+		if err := rows.Scan(&gasUsed); err != nil {
+			return nil, err
+		}
+		txsGasUsed = append(txsGasUsed, gasUsed)
+	}
+	return txsGasUsed, nil
+}
+
 // GetTransactionByHash gets a transaction accordingly to the provided transaction hash
 func (p *PostgresStorage) GetTransactionByHash(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*types.Transaction, error) {
 	var encoded string
@@ -279,6 +308,35 @@ func (p *PostgresStorage) GetTransactionByL2BlockNumberAndIndex(ctx context.Cont
 	return tx, nil
 }
 
+// GetTransactionByBatchNumberAndIndex gets a transaction from a batch by its position in the batch,
+// the transactions of the batch are sorted by L2 block and index in the L2 block
+func (p *PostgresStorage) GetTransactionByBatchNumberAndIndex(ctx context.Context, batchNumber uint64, index uint64, dbTx pgx.Tx) (*types.Transaction, error) {
+	var encoded string
+	const getTransactionByBatchNumberAndIndexSQL = `
+		SELECT t.encoded
+		  FROM state.l2block b
+		 INNER JOIN state.receipt r ON r.block_num = b.block_num
+		 INNER JOIN state.transaction t ON t.hash = r.tx_hash
+		 WHERE b.batch_num = $1
+		 ORDER BY r.block_num ASC, r.tx_index ASC
+		OFFSET $2 LIMIT 1`
+
+	q := p.getExecQuerier(dbTx)
+	err := q.QueryRow(ctx, getTransactionByBatchNumberAndIndexSQL, batchNumber, index).Scan(&encoded)
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, state.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	tx, err := state.DecodeTx(encoded)
+	if err != nil {
+		return nil, err
+	}
+
+	return tx, nil
+}
+
 // getTransactionLogs returns the logs of a transaction by transaction hash
 func (p *PostgresStorage) getTransactionLogs(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) ([]*types.Log, error) {
 	q := p.getExecQuerier(dbTx)
@@ -483,3 +541,24 @@ func (p *PostgresStorage) GetTransactionEGPLogByHash(ctx context.Context, transa
 
 	return &egpLog, nil
 }
+
+// GetTransactionEffectiveGasPrice gets the effective gas price stored in the receipt of the provided transaction hash,
+// nil if the receipt doesn't have it (txs processed before it was stored)
+func (p *PostgresStorage) GetTransactionEffectiveGasPrice(ctx context.Context, transactionHash common.Hash, dbTx pgx.Tx) (*big.Int, error) {
+	var effectiveGasPrice *uint64
+	const getTransactionEffectiveGasPriceSQL = "SELECT effective_gas_price FROM state.receipt WHERE tx_hash = $1"
+
+	q := p.getExecQuerier(dbTx)
+	err := q.QueryRow(ctx, getTransactionEffectiveGasPriceSQL, transactionHash.String()).Scan(&effectiveGasPrice)
+
+	if errors.Is(err, pgx.ErrNoRows) {
+		return nil, state.ErrNotFound
+	} else if err != nil {
+		return nil, err
+	}
+
+	if effectiveGasPrice == nil {
+		return nil, nil
+	}
+	return new(big.Int).SetUint64(*effectiveGasPrice), nil
+}
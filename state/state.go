@@ -36,9 +36,13 @@ type State struct {
 	tree           *merkletree.StateTree
 	eventLog       *event.EventLog
 	l1InfoTree     *l1infotree.L1InfoTree
+	// l1InfoTreeDataCache is nil if the cache is disabled
+	l1InfoTreeDataCache *l1InfoTreeDataCache
 
 	newL2BlockEvents        chan NewL2BlockEvent
 	newL2BlockEventHandlers []NewL2BlockEventHandler
+
+	newVerifiedBatchEventHandlers []NewVerifiedBatchEventHandler
 }
 
 // NewState creates a new State
@@ -58,10 +62,25 @@ func NewState(cfg Config, storage storage, executorClient executor.ExecutorServi
 		newL2BlockEventHandlers: []NewL2BlockEventHandler{},
 		l1InfoTree:              mt,
 	}
+	if cfg.L1InfoTreeCacheTTL.Duration > 0 {
+		state.l1InfoTreeDataCache = newL1InfoTreeDataCache(l1InfoTreeDataCacheSize, cfg.L1InfoTreeCacheTTL.Duration)
+	}
 
 	return state
 }
 
+// Reset removes the L1 blocks after blockNumber and the data synced from them. The cached
+// L1InfoTree data is cleared, as the L1InfoTree leafs of the removed blocks are deleted too
+func (s *State) Reset(ctx context.Context, blockNumber uint64, dbTx pgx.Tx) error {
+	if err := s.storage.Reset(ctx, blockNumber, dbTx); err != nil {
+		return err
+	}
+	if s.l1InfoTreeDataCache != nil {
+		s.l1InfoTreeDataCache.clear()
+	}
+	return nil
+}
+
 // BeginStateTransaction starts a state transaction
 func (s *State) BeginStateTransaction(ctx context.Context) (pgx.Tx, error) {
 	tx, err := s.Begin(ctx)
@@ -107,6 +126,14 @@ func (s *State) GetStorageAt(ctx context.Context, address common.Address, positi
 	return s.tree.GetStorageAt(ctx, address, position, root.Bytes())
 }
 
+// GetAccountProof returns the merkle proofs of the account leafs and of the given storage positions
+func (s *State) GetAccountProof(ctx context.Context, address common.Address, storagePositions []*big.Int, root common.Hash) (*merkletree.AccountProof, error) {
+	if s.tree == nil {
+		return nil, ErrStateTreeNil
+	}
+	return s.tree.GetAccountProof(ctx, address, storagePositions, root.Bytes())
+}
+
 // GetLastStateRoot returns the latest state root
 func (s *State) GetLastStateRoot(ctx context.Context, dbTx pgx.Tx) (common.Hash, error) {
 	lastBlockHeader, err := s.GetLastL2BlockHeader(ctx, dbTx)
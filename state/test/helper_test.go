@@ -10,6 +10,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	"github.com/ethereum/go-ethereum/common"
 	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 	"github.com/stretchr/testify/assert"
 	"github.com/stretchr/testify/require"
 )
@@ -239,3 +240,78 @@ func TestCheckLogOrder(t *testing.T) {
 	ok = state.CheckLogOrder(logs)
 	assert.Equal(t, true, ok)
 }
+
+func TestEstimateEncodedSize(t *testing.T) {
+	privateKey, err := crypto.GenerateKey()
+	require.NoError(t, err)
+	to := common.HexToAddress("0x1275fbb540c8efc58b812ba83b0d0b8b9917ae98")
+
+	testCases := []struct {
+		name   string
+		signer types.Signer
+		txData types.TxData
+	}{
+		{
+			name:   "simple transfer",
+			signer: types.NewEIP155Signer(big.NewInt(1001)),
+			txData: &types.LegacyTx{Nonce: 0, GasPrice: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(0)},
+		},
+		{
+			name:   "big values",
+			signer: types.NewEIP155Signer(big.NewInt(1001)),
+			txData: &types.LegacyTx{Nonce: 1234567, GasPrice: big.NewInt(1000000000000), Gas: 30000000, To: &to, Value: new(big.Int).Lsh(big.NewInt(1), 200), Data: []byte{0x1}},
+		},
+		{
+			name:   "contract deployment",
+			signer: types.NewEIP155Signer(big.NewInt(1001)),
+			txData: &types.LegacyTx{Nonce: 127, GasPrice: big.NewInt(128), Gas: 1000000, Value: big.NewInt(1), Data: make([]byte, 55)},
+		},
+		{
+			name:   "big data",
+			signer: types.NewEIP155Signer(big.NewInt(1001)),
+			txData: &types.LegacyTx{Nonce: 128, GasPrice: big.NewInt(127), Gas: 1000000, To: &to, Value: big.NewInt(1), Data: make([]byte, 70000)},
+		},
+		{
+			name:   "pre EIP155",
+			signer: types.HomesteadSigner{},
+			txData: &types.LegacyTx{Nonce: 3, GasPrice: big.NewInt(1000000000), Gas: 24931, To: &to, Value: big.NewInt(0), Data: []byte{0x64, 0xfb, 0xb7, 0x7c}},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			tx, err := types.SignNewTx(privateKey, tc.signer, tc.txData)
+			require.NoError(t, err)
+
+			encoded, err := state.EncodeTransaction(*tx, state.MaxEffectivePercentage, state.FORKID_DRAGONFRUIT)
+			require.NoError(t, err)
+			assert.Equal(t, uint64(len(encoded)), state.EstimateEncodedSize(tx))
+		})
+	}
+}
+
+func TestEstimateZKCounters(t *testing.T) {
+	to := common.HexToAddress("0x1275fbb540c8efc58b812ba83b0d0b8b9917ae98")
+
+	transfer := types.NewTx(&types.LegacyTx{Nonce: 0, GasPrice: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(1)})
+	counters, err := state.EstimateZKCounters(transfer)
+	require.NoError(t, err)
+	assert.Equal(t, uint64(21000), counters.GasUsed)
+	assert.Equal(t, uint32(21000/10), counters.UsedSteps)
+	assert.Equal(t, uint32(0), counters.UsedMemAligns)
+
+	call := types.NewTx(&types.LegacyTx{Nonce: 0, GasPrice: big.NewInt(1), Gas: 21000, To: &to, Value: big.NewInt(1), Data: make([]byte, 1000)})
+	callCounters, err := state.EstimateZKCounters(call)
+	require.NoError(t, err)
+	assert.Equal(t, uint32(1000*8+21000/10), callCounters.UsedSteps)
+	assert.Greater(t, callCounters.UsedKeccakHashes, counters.UsedKeccakHashes)
+	assert.Greater(t, callCounters.UsedPoseidonPaddings, counters.UsedPoseidonPaddings)
+	assert.Greater(t, callCounters.UsedBinaries, counters.UsedBinaries)
+
+	overflow := types.NewTx(&types.LegacyTx{Nonce: 0, GasPrice: big.NewInt(1), Gas: ^uint64(0), To: &to, Value: big.NewInt(1)})
+	_, err = state.EstimateZKCounters(overflow)
+	require.ErrorIs(t, err, state.ErrZKCountersEstimationOverflow)
+
+	_, err = state.EstimateZKCounters(nil)
+	require.Error(t, err)
+}
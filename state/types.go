@@ -3,6 +3,7 @@ package state
 import (
 	"encoding/json"
 	"fmt"
+	"math"
 	"math/big"
 	"strings"
 	"time"
@@ -65,6 +66,8 @@ type ProcessBatchResponse struct {
 	ForkID               uint64
 	InvalidBatch_V2      bool
 	RomError_V2          error
+	// ExecutionTimeMs is the time in milliseconds the executor took to process the batch request
+	ExecutionTimeMs uint64
 }
 
 // ProcessBlockResponse represents the response of a block
@@ -177,6 +180,28 @@ func (z *ZKCounters) SumUp(other ZKCounters) {
 	z.UsedSha256Hashes_V2 += other.UsedSha256Hashes_V2
 }
 
+// IsZero returns true if all the zk counters are zero
+func (z ZKCounters) IsZero() bool {
+	return z.GasUsed == 0 && z.UsedKeccakHashes == 0 && z.UsedPoseidonHashes == 0 && z.UsedPoseidonPaddings == 0 &&
+		z.UsedMemAligns == 0 && z.UsedArithmetics == 0 && z.UsedBinaries == 0 && z.UsedSteps == 0 && z.UsedSha256Hashes_V2 == 0
+}
+
+// Diff returns the element-wise difference between the zk counters and other, clamped to zero
+// for the counters where other is greater
+func (z ZKCounters) Diff(other ZKCounters) ZKCounters {
+	return ZKCounters{
+		GasUsed:              z.GasUsed - min(z.GasUsed, other.GasUsed),
+		UsedKeccakHashes:     z.UsedKeccakHashes - min(z.UsedKeccakHashes, other.UsedKeccakHashes),
+		UsedPoseidonHashes:   z.UsedPoseidonHashes - min(z.UsedPoseidonHashes, other.UsedPoseidonHashes),
+		UsedPoseidonPaddings: z.UsedPoseidonPaddings - min(z.UsedPoseidonPaddings, other.UsedPoseidonPaddings),
+		UsedMemAligns:        z.UsedMemAligns - min(z.UsedMemAligns, other.UsedMemAligns),
+		UsedArithmetics:      z.UsedArithmetics - min(z.UsedArithmetics, other.UsedArithmetics),
+		UsedBinaries:         z.UsedBinaries - min(z.UsedBinaries, other.UsedBinaries),
+		UsedSteps:            z.UsedSteps - min(z.UsedSteps, other.UsedSteps),
+		UsedSha256Hashes_V2:  z.UsedSha256Hashes_V2 - min(z.UsedSha256Hashes_V2, other.UsedSha256Hashes_V2),
+	}
+}
+
 // Sub subtract zk counters with passed zk counters (not safe)
 func (z *ZKCounters) Sub(other ZKCounters) error {
 	// ZKCounters
@@ -225,23 +250,26 @@ func (z *ZKCounters) Sub(other ZKCounters) error {
 type BatchResources struct {
 	ZKCounters ZKCounters
 	Bytes      uint64
+	// InvalidConstraints is true if the resources are not limited because the batch constraints they
+	// were built from have zero fields
+	InvalidConstraints bool `json:"-"`
 }
 
-// Sub subtracts the batch resources from other
-func (r *BatchResources) Sub(other BatchResources) error {
+// Sub returns new batch resources with other subtracted from the batch resources. The subtraction
+// is done on a copy, so the batch resources are left unchanged if any of the resources underflows
+func (r BatchResources) Sub(other BatchResources) (BatchResources, error) {
 	// Bytes
 	if other.Bytes > r.Bytes {
-		return ErrBatchResourceBytesUnderflow
+		return r, ErrBatchResourceBytesUnderflow
 	}
-	bytesBackup := r.Bytes
-	r.Bytes -= other.Bytes
-	err := r.ZKCounters.Sub(other.ZKCounters)
+	result := r
+	result.Bytes -= other.Bytes
+	err := result.ZKCounters.Sub(other.ZKCounters)
 	if err != nil {
-		r.Bytes = bytesBackup
-		return NewBatchRemainingResourcesUnderflowError(err, err.Error())
+		return r, NewBatchRemainingResourcesUnderflowError(err, err.Error())
 	}
 
-	return err
+	return result, nil
 }
 
 // SumUp sum ups the batch resources from other
@@ -250,6 +278,73 @@ func (r *BatchResources) SumUp(other BatchResources) {
 	r.ZKCounters.SumUp(other.ZKCounters)
 }
 
+// ScaleBy returns new batch resources with each resource of the batch resources multiplied by pct
+// (between 0 and 1), rounded up to the next integer
+func (r BatchResources) ScaleBy(pct float64) BatchResources {
+	scaleUint64 := func(value uint64) uint64 {
+		return uint64(math.Ceil(float64(value) * pct))
+	}
+	scaleUint32 := func(value uint32) uint32 {
+		return uint32(math.Ceil(float64(value) * pct))
+	}
+
+	return BatchResources{
+		ZKCounters: ZKCounters{
+			GasUsed:              scaleUint64(r.ZKCounters.GasUsed),
+			UsedKeccakHashes:     scaleUint32(r.ZKCounters.UsedKeccakHashes),
+			UsedPoseidonHashes:   scaleUint32(r.ZKCounters.UsedPoseidonHashes),
+			UsedPoseidonPaddings: scaleUint32(r.ZKCounters.UsedPoseidonPaddings),
+			UsedMemAligns:        scaleUint32(r.ZKCounters.UsedMemAligns),
+			UsedArithmetics:      scaleUint32(r.ZKCounters.UsedArithmetics),
+			UsedBinaries:         scaleUint32(r.ZKCounters.UsedBinaries),
+			UsedSteps:            scaleUint32(r.ZKCounters.UsedSteps),
+			UsedSha256Hashes_V2:  scaleUint32(r.ZKCounters.UsedSha256Hashes_V2),
+		},
+		Bytes: scaleUint64(r.Bytes),
+	}
+}
+
+// BatchGasProfile is the distribution of the gas used by the transactions of a batch
+type BatchGasProfile struct {
+	MinGas   uint64
+	MaxGas   uint64
+	AvgGas   uint64
+	TotalGas uint64
+}
+
+// ComputeGasProfile computes the gas profile of a batch from the gas used by each of its transactions.
+// The profile of a batch without transactions has all its values set to 0
+func ComputeGasProfile(txsGasUsed []uint64) BatchGasProfile {
+	if len(txsGasUsed) == 0 {
+		return BatchGasProfile{}
+	}
+
+	profile := BatchGasProfile{MinGas: txsGasUsed[0], MaxGas: txsGasUsed[0]}
+	for _, gasUsed := range txsGasUsed {
+		profile.MinGas = min(profile.MinGas, gasUsed)
+		profile.MaxGas = max(profile.MaxGas, gasUsed)
+		profile.TotalGas += gasUsed
+	}
+	profile.AvgGas = profile.TotalGas / uint64(len(txsGasUsed))
+
+	return profile
+}
+
+// ComputeAverageEffectiveGasPrice computes the average of the effective gas prices of the transactions of a batch.
+// It returns nil if the batch has no transactions
+func ComputeAverageEffectiveGasPrice(txsEffectiveGasPrice []*big.Int) *big.Int {
+	if len(txsEffectiveGasPrice) == 0 {
+		return nil
+	}
+
+	total := big.NewInt(0)
+	for _, effectiveGasPrice := range txsEffectiveGasPrice {
+		total.Add(total, effectiveGasPrice)
+	}
+
+	return total.Div(total, big.NewInt(int64(len(txsEffectiveGasPrice))))
+}
+
 // InfoReadWrite has information about modified addresses during the execution
 type InfoReadWrite struct {
 	Address common.Address
@@ -0,0 +1,185 @@
+package state
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestZKCountersIsZero(t *testing.T) {
+	assert.True(t, ZKCounters{}.IsZero())
+
+	counters := []ZKCounters{
+		{GasUsed: 1},
+		{UsedKeccakHashes: 1},
+		{UsedPoseidonHashes: 1},
+		{UsedPoseidonPaddings: 1},
+		{UsedMemAligns: 1},
+		{UsedArithmetics: 1},
+		{UsedBinaries: 1},
+		{UsedSteps: 1},
+		{UsedSha256Hashes_V2: 1},
+	}
+	for _, c := range counters {
+		assert.False(t, c.IsZero(), "%+v", c)
+	}
+}
+
+func TestBatchResourcesSub(t *testing.T) {
+	remaining := BatchResources{
+		ZKCounters: ZKCounters{GasUsed: 10, UsedKeccakHashes: 2, UsedSteps: 300},
+		Bytes:      100,
+	}
+
+	result, err := remaining.Sub(BatchResources{
+		ZKCounters: ZKCounters{GasUsed: 4, UsedKeccakHashes: 2, UsedSteps: 100},
+		Bytes:      60,
+	})
+	assert.NoError(t, err)
+	assert.Equal(t, BatchResources{ZKCounters: ZKCounters{GasUsed: 6, UsedSteps: 200}, Bytes: 40}, result)
+
+	backup := remaining
+	result, err = remaining.Sub(BatchResources{Bytes: 101})
+	assert.ErrorIs(t, err, ErrBatchResourceBytesUnderflow)
+	assert.Equal(t, backup, result)
+	assert.Equal(t, backup, remaining)
+
+	// the bytes fit but a zk counter underflows
+	result, err = remaining.Sub(BatchResources{ZKCounters: ZKCounters{UsedSteps: 301}, Bytes: 50})
+	var underflowErr *BatchRemainingResourcesUnderflowError
+	assert.ErrorAs(t, err, &underflowErr)
+	assert.Equal(t, backup, result)
+	assert.Equal(t, backup, remaining)
+}
+
+func TestZKCountersDiff(t *testing.T) {
+	// Counters used by a batch before and after incrementally processing a new L2 block
+	before := ZKCounters{
+		GasUsed:              21000,
+		UsedKeccakHashes:     10,
+		UsedPoseidonHashes:   100,
+		UsedPoseidonPaddings: 5,
+		UsedMemAligns:        2,
+		UsedArithmetics:      20,
+		UsedBinaries:         30,
+		UsedSteps:            1000,
+		UsedSha256Hashes_V2:  1,
+	}
+	after := ZKCounters{
+		GasUsed:              63000,
+		UsedKeccakHashes:     25,
+		UsedPoseidonHashes:   180,
+		UsedPoseidonPaddings: 9,
+		UsedMemAligns:        2,
+		UsedArithmetics:      35,
+		UsedBinaries:         70,
+		UsedSteps:            2600,
+		UsedSha256Hashes_V2:  1,
+	}
+
+	expected := ZKCounters{
+		GasUsed:              42000,
+		UsedKeccakHashes:     15,
+		UsedPoseidonHashes:   80,
+		UsedPoseidonPaddings: 4,
+		UsedArithmetics:      15,
+		UsedBinaries:         40,
+		UsedSteps:            1600,
+	}
+	assert.Equal(t, expected, after.Diff(before))
+	assert.True(t, before.Diff(after).IsZero())
+	assert.True(t, after.Diff(after).IsZero())
+}
+
+func TestBatchResourcesScaleBy(t *testing.T) {
+	resources := BatchResources{
+		ZKCounters: ZKCounters{GasUsed: 30000000, UsedKeccakHashes: 2145, UsedSteps: 7570538, UsedSha256Hashes_V2: 1596},
+		Bytes:      120000,
+	}
+
+	expected := BatchResources{
+		ZKCounters: ZKCounters{GasUsed: 3000000, UsedKeccakHashes: 215, UsedSteps: 757054, UsedSha256Hashes_V2: 160},
+		Bytes:      12000,
+	}
+	assert.Equal(t, expected, resources.ScaleBy(0.1))
+	assert.Equal(t, resources, resources.ScaleBy(1))
+	assert.True(t, resources.ScaleBy(0).ZKCounters.IsZero())
+}
+
+func TestBatchConstraintsCfgCloseThreshold(t *testing.T) {
+	constraints := BatchConstraintsCfg{
+		MaxBatchBytesSize:    120000,
+		MaxCumulativeGasUsed: 30000000,
+		MaxKeccakHashes:      2145,
+		MaxPoseidonHashes:    252357,
+		MaxPoseidonPaddings:  135191,
+		MaxMemAligns:         236585,
+		MaxArithmetics:       236585,
+		MaxBinaries:          473170,
+		MaxSteps:             7570538,
+		MaxSHA256Hashes:      1596,
+	}
+
+	maxResources := constraints.ToMaxResources()
+	assert.Equal(t, constraints.MaxBatchBytesSize, maxResources.Bytes)
+	assert.Equal(t, constraints.MaxCumulativeGasUsed, maxResources.ZKCounters.GasUsed)
+	assert.Equal(t, constraints.MaxSteps, maxResources.ZKCounters.UsedSteps)
+	assert.True(t, constraints.IsWithinConstraints(maxResources.ZKCounters))
+
+	threshold := constraints.CloseThreshold(10)
+	assert.Equal(t, uint64(12000), threshold.Bytes)
+	assert.Equal(t, uint64(3000000), threshold.ZKCounters.GasUsed)
+	assert.Equal(t, uint32(215), threshold.ZKCounters.UsedKeccakHashes)
+	assert.Equal(t, uint32(25236), threshold.ZKCounters.UsedPoseidonHashes)
+	assert.Equal(t, uint32(13520), threshold.ZKCounters.UsedPoseidonPaddings)
+	assert.Equal(t, uint32(23659), threshold.ZKCounters.UsedMemAligns)
+	assert.Equal(t, uint32(23659), threshold.ZKCounters.UsedArithmetics)
+	assert.Equal(t, uint32(47317), threshold.ZKCounters.UsedBinaries)
+	assert.Equal(t, uint32(757054), threshold.ZKCounters.UsedSteps)
+	assert.Equal(t, uint32(160), threshold.ZKCounters.UsedSha256Hashes_V2)
+
+	assert.Equal(t, maxResources, constraints.CloseThreshold(100))
+}
+
+func TestBatchConstraintsCfgZeroConstraints(t *testing.T) {
+	constraints := BatchConstraintsCfg{
+		MaxTxsPerBatch:       300,
+		MaxBatchBytesSize:    120000,
+		MaxCumulativeGasUsed: 30000000,
+		MaxKeccakHashes:      2145,
+		MaxPoseidonHashes:    252357,
+		MaxPoseidonPaddings:  135191,
+		MaxMemAligns:         236585,
+		MaxArithmetics:       236585,
+		MaxBinaries:          473170,
+		MaxSteps:             7570538,
+		MaxSHA256Hashes:      1596,
+	}
+	assert.Empty(t, constraints.ZeroConstraints())
+
+	constraints.MaxKeccakHashes = 0
+	constraints.MaxSHA256Hashes = 0
+	assert.Equal(t, []string{"MaxKeccakHashes", "MaxSHA256Hashes"}, constraints.ZeroConstraints())
+}
+
+func TestBatchConstraintsCfgValidate(t *testing.T) {
+	assert.Error(t, BatchConstraintsCfg{}.Validate())
+	assert.NoError(t, BatchConstraintsCfg{MaxForcedBatchesPerIteration: 1}.Validate())
+}
+
+func TestComputeGasProfile(t *testing.T) {
+	assert.Equal(t, BatchGasProfile{}, ComputeGasProfile(nil))
+
+	expected := BatchGasProfile{MinGas: 21000, MaxGas: 100000, AvgGas: 50000, TotalGas: 150000}
+	assert.Equal(t, expected, ComputeGasProfile([]uint64{29000, 21000, 100000}))
+}
+
+func TestComputeAverageEffectiveGasPrice(t *testing.T) {
+	assert.Nil(t, ComputeAverageEffectiveGasPrice(nil))
+
+	txsEffectiveGasPrice := []*big.Int{big.NewInt(1000000000), big.NewInt(2000000000), big.NewInt(4500000000)}
+	assert.Equal(t, big.NewInt(2500000000), ComputeAverageEffectiveGasPrice(txsEffectiveGasPrice))
+	// The effective gas prices of the txs are not modified
+	assert.Equal(t, big.NewInt(1000000000), txsEffectiveGasPrice[0])
+}
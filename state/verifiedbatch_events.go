@@ -0,0 +1,106 @@
+package state
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+const newVerifiedBatchesCheckInterval = time.Second
+
+// NewVerifiedBatchEventHandler represent a func that will be called by the
+// state when a NewVerifiedBatchEvent is triggered
+type NewVerifiedBatchEventHandler func(e NewVerifiedBatchEvent)
+
+// NewVerifiedBatchEvent is a struct provided from the state to the NewVerifiedBatchEventHandler
+// when a new verified batch is detected. A verification on L1 can verify several batches at once,
+// in that case the event is triggered only for the last batch verified, which implies that all the
+// previous ones are verified too
+type NewVerifiedBatchEvent struct {
+	VerifiedBatch VerifiedBatch
+}
+
+// StartToMonitorNewVerifiedBatches starts a go routine that will monitor
+// the batches verified on L1 stored by the synchronizer and execute the
+// handlers registered to be executed when a new verified batch is detected.
+// This is used by the RPC WebSocket subscriptions to verified batches.
+func (s *State) StartToMonitorNewVerifiedBatches() {
+	go InfiniteSafeRun(s.monitorNewVerifiedBatches, "fail to monitor new verified batches: %v:", time.Second)
+}
+
+// RegisterNewVerifiedBatchEventHandler add the provided handler to the list of handlers
+// that will be triggered when a new verified batch event is triggered
+func (s *State) RegisterNewVerifiedBatchEventHandler(h NewVerifiedBatchEventHandler) {
+	log.Info("new verified batch event handler registered")
+	s.newVerifiedBatchEventHandlers = append(s.newVerifiedBatchEventHandlers, h)
+}
+
+func (s *State) monitorNewVerifiedBatches() {
+	waitNextCycle := func() {
+		time.Sleep(newVerifiedBatchesCheckInterval)
+	}
+
+	lastVerifiedBatchNumberSeen := uint64(0)
+	lastVerifiedBatch, err := s.GetLastVerifiedBatch(context.Background(), nil)
+	if err == nil {
+		lastVerifiedBatchNumberSeen = lastVerifiedBatch.BatchNumber
+	} else if !errors.Is(err, ErrNotFound) {
+		log.Fatalf("failed to load the last verified batch: %v", err)
+	}
+
+	for {
+		if len(s.newVerifiedBatchEventHandlers) == 0 {
+			waitNextCycle()
+			continue
+		}
+
+		lastVerifiedBatch, err := s.GetLastVerifiedBatch(context.Background(), nil)
+		if errors.Is(err, ErrNotFound) {
+			waitNextCycle()
+			continue
+		} else if err != nil {
+			log.Errorf("failed to get last verified batch while monitoring new verified batches: %v", err)
+			waitNextCycle()
+			continue
+		}
+
+		// the verified batches have been reorged
+		if lastVerifiedBatch.BatchNumber < lastVerifiedBatchNumberSeen {
+			log.Infof("last verified batch decreased from %v to %v", lastVerifiedBatchNumberSeen, lastVerifiedBatch.BatchNumber)
+			lastVerifiedBatchNumberSeen = lastVerifiedBatch.BatchNumber
+		}
+
+		for bn := lastVerifiedBatchNumberSeen + 1; bn <= lastVerifiedBatch.BatchNumber; bn++ {
+			verifiedBatch, err := s.GetVerifiedBatch(context.Background(), bn, nil)
+			if errors.Is(err, ErrNotFound) {
+				// verified by a later verification that verified several batches at once
+				lastVerifiedBatchNumberSeen = bn
+				continue
+			} else if err != nil {
+				log.Errorf("failed to get verified batch while monitoring new verified batches: %v", err)
+				break
+			}
+
+			log.Debugf("[monitorNewVerifiedBatches] new verified batch detected: %v", bn)
+			s.triggerNewVerifiedBatchEventHandlers(NewVerifiedBatchEvent{VerifiedBatch: *verifiedBatch})
+			lastVerifiedBatchNumberSeen = bn
+		}
+
+		waitNextCycle()
+	}
+}
+
+func (s *State) triggerNewVerifiedBatchEventHandlers(e NewVerifiedBatchEvent) {
+	for _, handler := range s.newVerifiedBatchEventHandlers {
+		func(h NewVerifiedBatchEventHandler) {
+			defer func() {
+				if r := recover(); r != nil {
+					log.Errorf("failed and recovered in NewVerifiedBatchEventHandler: %v", r)
+				}
+			}()
+			h(e)
+		}(handler)
+	}
+}
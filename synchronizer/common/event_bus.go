@@ -0,0 +1,39 @@
+package common
+
+import "sync"
+
+// EventBus is a publish/subscribe bus used to notify the subsystems interested in synchronizer events
+type EventBus interface {
+	// Publish sends the event to all the subscribers
+	Publish(event interface{})
+	// Subscribe registers a handler that is called for every published event
+	Subscribe(handler func(event interface{}))
+}
+
+// SyncEventBus is an EventBus that calls the subscribers synchronously, in subscription order
+type SyncEventBus struct {
+	mutex    sync.RWMutex
+	handlers []func(event interface{})
+}
+
+// NewSyncEventBus creates a new SyncEventBus
+func NewSyncEventBus() *SyncEventBus {
+	return &SyncEventBus{}
+}
+
+// Publish sends the event to all the subscribers
+func (b *SyncEventBus) Publish(event interface{}) {
+	b.mutex.RLock()
+	handlers := b.handlers
+	b.mutex.RUnlock()
+	for _, handler := range handlers {
+		handler(event)
+	}
+}
+
+// Subscribe registers a handler that is called for every published event
+func (b *SyncEventBus) Subscribe(handler func(event interface{})) {
+	b.mutex.Lock()
+	defer b.mutex.Unlock()
+	b.handlers = append(b.handlers, handler)
+}
@@ -0,0 +1,23 @@
+package common
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/require"
+)
+
+func TestSyncEventBusPublishToAllSubscribers(t *testing.T) {
+	bus := NewSyncEventBus()
+	bus.Publish("no subscribers")
+
+	received := []string{}
+	bus.Subscribe(func(event interface{}) {
+		received = append(received, "first:"+event.(string))
+	})
+	bus.Subscribe(func(event interface{}) {
+		received = append(received, "second:"+event.(string))
+	})
+	bus.Publish("event")
+
+	require.Equal(t, []string{"first:event", "second:event"}, received)
+}
@@ -12,6 +12,23 @@ type Config struct {
 	SyncChunkSize uint64 `mapstructure:"SyncChunkSize"`
 	// TrustedSequencerURL is the rpc url to connect and sync the trusted state
 	TrustedSequencerURL string `mapstructure:"TrustedSequencerURL"`
+	// MaxIncrementalRetries is the number of consecutive failures processing incrementally a trusted batch
+	// after which the batch is fully reprocessed. 0 disables it
+	MaxIncrementalRetries int `mapstructure:"MaxIncrementalRetries"`
+	// MaxTrustedBatchProcessingTime is the maximum time to process a trusted batch, after it the processing
+	// is aborted and retried in the next sync iteration. 0 disables it
+	MaxTrustedBatchProcessingTime types.Duration `mapstructure:"MaxTrustedBatchProcessingTime"`
+	// MaxFlushIDRetries is the number of times a failed check of the flushID is retried, with an exponential
+	// backoff, before committing a trusted batch to the state. 0 disables it
+	MaxFlushIDRetries int `mapstructure:"MaxFlushIDRetries"`
+	// HealthCheckHost is the address to bind the health check http server
+	HealthCheckHost string `mapstructure:"HealthCheckHost"`
+	// HealthCheckPort is the port to bind the health check http server, used by liveness and readiness
+	// probes. 0 disables it
+	HealthCheckPort int `mapstructure:"HealthCheckPort"`
+	// HealthCheckMaxLag is the max number of batches the node can be behind before the health check
+	// reports it as unhealthy
+	HealthCheckMaxLag uint64 `mapstructure:"HealthCheckMaxLag"`
 
 	// L1SynchronizationMode define how to synchronize with L1:
 	// - parallel: Request data to L1 in parallel, and process sequentially. The advantage is that executor is not blocked waiting for L1 data
@@ -0,0 +1,64 @@
+package synchronizer
+
+import (
+	"encoding/json"
+	"net/http"
+
+	"github.com/0xPolygonHermez/zkevm-node/log"
+)
+
+const (
+	// HealthCheckEndpoint is the path of the health check http endpoint
+	HealthCheckEndpoint = "/health"
+
+	healthStatusOK      = "ok"
+	healthStatusLagging = "lagging"
+	healthStatusError   = "error"
+)
+
+// HealthResponse is the body returned by the health check http endpoint
+type HealthResponse struct {
+	Status        string `json:"status"`
+	BatchesBehind uint64 `json:"batchesBehind"`
+	Error         string `json:"error,omitempty"`
+}
+
+type healthHandler struct {
+	sync   Synchronizer
+	maxLag uint64
+}
+
+// NewHealthHandler returns an http.Handler to be used by liveness and readiness probes. It responds
+// with 200 if the synchronizer is at most maxLag batches behind and with 503 otherwise
+func NewHealthHandler(s Synchronizer, maxLag uint64) http.Handler {
+	return &healthHandler{
+		sync:   s,
+		maxLag: maxLag,
+	}
+}
+
+// ServeHTTP implements http.Handler
+func (h *healthHandler) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	res := HealthResponse{Status: healthStatusOK}
+	statusCode := http.StatusOK
+
+	batchesBehind, err := h.sync.BatchesBehind(r.Context())
+	if err != nil {
+		log.Warnf("health check failed to get the batches behind. Error: %v", err)
+		res.Status = healthStatusError
+		res.Error = err.Error()
+		statusCode = http.StatusServiceUnavailable
+	} else {
+		res.BatchesBehind = batchesBehind
+		if batchesBehind > h.maxLag {
+			res.Status = healthStatusLagging
+			statusCode = http.StatusServiceUnavailable
+		}
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(statusCode)
+	if err := json.NewEncoder(w).Encode(res); err != nil {
+		log.Errorf("failed to write health check response. Error: %v", err)
+	}
+}
@@ -0,0 +1,102 @@
+package synchronizer
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type synchronizerStub struct {
+	batchesBehind uint64
+	err           error
+}
+
+func (s *synchronizerStub) Sync() error { return nil }
+
+func (s *synchronizerStub) Stop() {}
+
+func (s *synchronizerStub) BatchesBehind(ctx context.Context) (uint64, error) {
+	return s.batchesBehind, s.err
+}
+
+func TestHealthHandler(t *testing.T) {
+	testCases := []struct {
+		name               string
+		sync               *synchronizerStub
+		expectedStatusCode int
+		expectedResponse   HealthResponse
+	}{
+		{
+			name:               "synced",
+			sync:               &synchronizerStub{batchesBehind: 0},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   HealthResponse{Status: "ok", BatchesBehind: 0},
+		},
+		{
+			name:               "behind within max lag",
+			sync:               &synchronizerStub{batchesBehind: 5},
+			expectedStatusCode: http.StatusOK,
+			expectedResponse:   HealthResponse{Status: "ok", BatchesBehind: 5},
+		},
+		{
+			name:               "behind over max lag",
+			sync:               &synchronizerStub{batchesBehind: 6},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedResponse:   HealthResponse{Status: "lagging", BatchesBehind: 6},
+		},
+		{
+			name:               "error getting batches behind",
+			sync:               &synchronizerStub{err: errors.New("trusted sequencer unreachable")},
+			expectedStatusCode: http.StatusServiceUnavailable,
+			expectedResponse:   HealthResponse{Status: "error", Error: "trusted sequencer unreachable"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			handler := NewHealthHandler(tc.sync, 5)
+			rec := httptest.NewRecorder()
+			handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, HealthCheckEndpoint, nil))
+
+			assert.Equal(t, tc.expectedStatusCode, rec.Code)
+			assert.Equal(t, "application/json", rec.Header().Get("Content-Type"))
+			var res HealthResponse
+			require.NoError(t, json.Unmarshal(rec.Body.Bytes(), &res))
+			assert.Equal(t, tc.expectedResponse, res)
+		})
+	}
+}
+
+func TestBatchesBehind(t *testing.T) {
+	ctx := context.Background()
+
+	t.Run("permissionless node compares with the trusted sequencer", func(t *testing.T) {
+		st := newStateMock(t)
+		zkEVMClient := newZkEVMClientMock(t)
+		sync := &ClientSynchronizer{state: st, zkEVMClient: zkEVMClient}
+		st.EXPECT().GetLastBatchNumber(ctx, nil).Return(uint64(10), nil).Once()
+		zkEVMClient.EXPECT().BatchNumber(ctx).Return(uint64(15), nil).Once()
+
+		batchesBehind, err := sync.BatchesBehind(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(5), batchesBehind)
+	})
+
+	t.Run("trusted sequencer compares with L1", func(t *testing.T) {
+		st := newStateMock(t)
+		etherMan := newEthermanMock(t)
+		sync := &ClientSynchronizer{isTrustedSequencer: true, state: st, etherMan: etherMan}
+		st.EXPECT().GetLastVirtualBatchNum(ctx, nil).Return(uint64(10), nil).Once()
+		etherMan.EXPECT().GetLatestBatchNumber().Return(uint64(8), nil).Once()
+
+		batchesBehind, err := sync.BatchesBehind(ctx)
+		require.NoError(t, err)
+		assert.Equal(t, uint64(0), batchesBehind)
+	})
+}
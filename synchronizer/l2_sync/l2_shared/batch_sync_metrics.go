@@ -0,0 +1,95 @@
+package l2_shared
+
+import (
+	"sync/atomic"
+
+	"github.com/0xPolygonHermez/zkevm-node/synchronizer/metrics"
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+var (
+	trustedBatchesProcessedDesc = prometheus.NewDesc(metrics.TrustedBatchesProcessedName,
+		"[SYNCHRONIZER] number of trusted batches processed since start by mode",
+		[]string{metrics.TrustedBatchesProcessedLabelName}, nil)
+	trustedBatchesProcessErrorsDesc = prometheus.NewDesc(metrics.TrustedBatchesProcessErrorsName,
+		"[SYNCHRONIZER] number of errors processing trusted batches since start", nil, nil)
+	cacheResetTotalDesc = prometheus.NewDesc(metrics.CacheResetTotalName,
+		"[SYNCHRONIZER] number of resets of the trusted state cache since start", nil, nil)
+)
+
+// BatchSyncMetrics contains the number of trusted batches processed in each mode and the
+// number of errors processing them since the node started
+type BatchSyncMetrics struct {
+	FullProcessCount        int64
+	IncrementalProcessCount int64
+	ReprocessCount          int64
+	NothingProcessCount     int64
+	ErrorCount              int64
+	CacheResetCount         int64
+}
+
+// batchSyncCounters are the counters of BatchSyncMetrics, updated atomically
+type batchSyncCounters struct {
+	fullProcessCount        atomic.Int64
+	incrementalProcessCount atomic.Int64
+	reprocessCount          atomic.Int64
+	nothingProcessCount     atomic.Int64
+	errorCount              atomic.Int64
+	cacheResetCount         atomic.Int64
+}
+
+// countProcessed increases the counter of the mode used to process a batch successfully
+func (c *batchSyncCounters) countProcessed(mode BatchProcessMode) {
+	switch mode {
+	case FullProcessMode:
+		c.fullProcessCount.Add(1)
+	case IncrementalProcessMode:
+		c.incrementalProcessCount.Add(1)
+	case ReprocessProcessMode:
+		c.reprocessCount.Add(1)
+	case NothingProcessMode:
+		c.nothingProcessCount.Add(1)
+	}
+}
+
+// onEvent is the EventBus handler that counts the batches processed
+func (c *batchSyncCounters) onEvent(event interface{}) {
+	if e, ok := event.(BatchProcessedEvent); ok {
+		c.countProcessed(e.Mode)
+	}
+}
+
+// countError increases the counter of errors processing a batch
+func (c *batchSyncCounters) countError() {
+	c.errorCount.Add(1)
+}
+
+// Metrics returns the number of trusted batches processed in each mode since the node started
+func (s *ProcessorTrustedBatchSync) Metrics() BatchSyncMetrics {
+	return BatchSyncMetrics{
+		FullProcessCount:        s.counters.fullProcessCount.Load(),
+		IncrementalProcessCount: s.counters.incrementalProcessCount.Load(),
+		ReprocessCount:          s.counters.reprocessCount.Load(),
+		NothingProcessCount:     s.counters.nothingProcessCount.Load(),
+		ErrorCount:              s.counters.errorCount.Load(),
+		CacheResetCount:         s.counters.cacheResetCount.Load(),
+	}
+}
+
+// Describe implements prometheus.Collector
+func (s *ProcessorTrustedBatchSync) Describe(ch chan<- *prometheus.Desc) {
+	ch <- trustedBatchesProcessedDesc
+	ch <- trustedBatchesProcessErrorsDesc
+	ch <- cacheResetTotalDesc
+}
+
+// Collect implements prometheus.Collector
+func (s *ProcessorTrustedBatchSync) Collect(ch chan<- prometheus.Metric) {
+	m := s.Metrics()
+	ch <- prometheus.MustNewConstMetric(trustedBatchesProcessedDesc, prometheus.CounterValue, float64(m.FullProcessCount), string(FullProcessMode))
+	ch <- prometheus.MustNewConstMetric(trustedBatchesProcessedDesc, prometheus.CounterValue, float64(m.IncrementalProcessCount), string(IncrementalProcessMode))
+	ch <- prometheus.MustNewConstMetric(trustedBatchesProcessedDesc, prometheus.CounterValue, float64(m.ReprocessCount), string(ReprocessProcessMode))
+	ch <- prometheus.MustNewConstMetric(trustedBatchesProcessedDesc, prometheus.CounterValue, float64(m.NothingProcessCount), string(NothingProcessMode))
+	ch <- prometheus.MustNewConstMetric(trustedBatchesProcessErrorsDesc, prometheus.CounterValue, float64(m.ErrorCount))
+	ch <- prometheus.MustNewConstMetric(cacheResetTotalDesc, prometheus.CounterValue, float64(m.CacheResetCount))
+}
@@ -0,0 +1,105 @@
+package l2_shared_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	syncCommon "github.com/0xPolygonHermez/zkevm-node/synchronizer/common"
+	"github.com/0xPolygonHermez/zkevm-node/synchronizer/l2_sync/l2_shared"
+	mock_l2_shared "github.com/0xPolygonHermez/zkevm-node/synchronizer/l2_sync/l2_shared/mocks"
+	"github.com/jackc/pgx/v4"
+	"github.com/prometheus/client_golang/prometheus/testutil"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestProcessorTrustedBatchSyncMetrics(t *testing.T) {
+	stepsMock := mock_l2_shared.NewSyncTrustedBatchExecutor(t)
+	sut := l2_shared.NewProcessorTrustedBatchSync(stepsMock, syncCommon.DefaultTimeProvider{}, l2_shared.ProcessorTrustedBatchSyncConfig{}, nil, nil)
+	ctx := context.Background()
+
+	status := l2_shared.TrustedState{
+		LastTrustedBatches: []*state.Batch{nil, {BatchNumber: 122}},
+	}
+	trustedBatch := &types.Batch{Number: 123}
+
+	stepsMock.EXPECT().FullProcess(ctx, mock.Anything, mock.Anything).Return(&l2_shared.ProcessResponse{ClearCache: true}, nil).Once()
+	_, err := sut.ProcessTrustedBatch(ctx, trustedBatch, status, nil, "")
+	require.NoError(t, err)
+
+	stepsMock.EXPECT().FullProcess(ctx, mock.Anything, mock.Anything).Return(nil, errors.New("some error")).Once()
+	_, err = sut.ProcessTrustedBatch(ctx, trustedBatch, status, nil, "")
+	require.Error(t, err)
+
+	status.LastTrustedBatches[0] = &state.Batch{BatchNumber: 123}
+	stepsMock.EXPECT().ReProcess(ctx, mock.Anything, mock.Anything).Return(&l2_shared.ProcessResponse{}, nil).Once()
+	_, err = sut.ProcessTrustedBatch(ctx, trustedBatch, status, nil, "")
+	require.NoError(t, err)
+
+	require.Equal(t, l2_shared.BatchSyncMetrics{
+		FullProcessCount: 1,
+		ReprocessCount:   1,
+		ErrorCount:       1,
+	}, sut.Metrics())
+	require.Equal(t, 6, testutil.CollectAndCount(sut))
+}
+
+func TestProcessorTrustedBatchSyncPublishesBatchProcessedEvent(t *testing.T) {
+	stepsMock := mock_l2_shared.NewSyncTrustedBatchExecutor(t)
+	eventBus := syncCommon.NewSyncEventBus()
+	events := []interface{}{}
+	eventBus.Subscribe(func(event interface{}) {
+		events = append(events, event)
+	})
+	sut := l2_shared.NewProcessorTrustedBatchSync(stepsMock, syncCommon.DefaultTimeProvider{}, l2_shared.ProcessorTrustedBatchSyncConfig{}, eventBus, nil)
+	ctx := context.Background()
+
+	status := l2_shared.TrustedState{
+		LastTrustedBatches: []*state.Batch{nil, {BatchNumber: 122}},
+	}
+	trustedBatch := &types.Batch{Number: 123}
+
+	stepsMock.EXPECT().FullProcess(ctx, mock.Anything, mock.Anything).Return(nil, errors.New("some error")).Once()
+	_, err := sut.ProcessTrustedBatch(ctx, trustedBatch, status, nil, "")
+	require.Error(t, err)
+	require.Empty(t, events)
+
+	stepsMock.EXPECT().FullProcess(ctx, mock.Anything, mock.Anything).Return(&l2_shared.ProcessResponse{ClearCache: true}, nil).Once()
+	_, err = sut.ProcessTrustedBatch(ctx, trustedBatch, status, nil, "")
+	require.NoError(t, err)
+	require.Equal(t, []interface{}{l2_shared.BatchProcessedEvent{BatchNumber: 123, Mode: l2_shared.FullProcessMode}}, events)
+	require.Equal(t, int64(1), sut.Metrics().FullProcessCount)
+}
+
+func TestProcessorTrustedBatchSyncReturnsErrProcessingTimeout(t *testing.T) {
+	stepsMock := mock_l2_shared.NewSyncTrustedBatchExecutor(t)
+	cfg := l2_shared.ProcessorTrustedBatchSyncConfig{MaxProcessingTime: 10 * time.Millisecond}
+	sut := l2_shared.NewProcessorTrustedBatchSync(stepsMock, syncCommon.DefaultTimeProvider{}, cfg, nil, nil)
+	ctx := context.Background()
+
+	status := l2_shared.TrustedState{
+		LastTrustedBatches: []*state.Batch{nil, {BatchNumber: 122}},
+	}
+	trustedBatch := &types.Batch{Number: 123}
+
+	stepsMock.EXPECT().FullProcess(mock.Anything, mock.Anything, mock.Anything).RunAndReturn(
+		func(ctx context.Context, data *l2_shared.ProcessData, dbTx pgx.Tx) (*l2_shared.ProcessResponse, error) {
+			<-ctx.Done()
+			return nil, ctx.Err()
+		}).Once()
+	_, err := sut.ProcessTrustedBatch(ctx, trustedBatch, status, nil, "")
+	require.ErrorIs(t, err, l2_shared.ErrProcessingTimeout)
+	require.Equal(t, int64(1), sut.Metrics().ErrorCount)
+}
+
+func TestProcessorTrustedBatchSyncResetCountsReset(t *testing.T) {
+	stepsMock := mock_l2_shared.NewSyncTrustedBatchExecutor(t)
+	sut := l2_shared.NewProcessorTrustedBatchSync(stepsMock, syncCommon.DefaultTimeProvider{}, l2_shared.ProcessorTrustedBatchSyncConfig{}, nil, nil)
+
+	require.NoError(t, sut.Reset(context.Background()))
+	require.Equal(t, int64(1), sut.Metrics().CacheResetCount)
+}
@@ -88,6 +88,52 @@ func (_c *BatchProcessor_ProcessTrustedBatch_Call) RunAndReturn(run func(context
 	return _c
 }
 
+// Reset provides a mock function with given fields: ctx
+func (_m *BatchProcessor) Reset(ctx context.Context) error {
+	ret := _m.Called(ctx)
+
+	if len(ret) == 0 {
+		panic("no return value specified for Reset")
+	}
+
+	var r0 error
+	if rf, ok := ret.Get(0).(func(context.Context) error); ok {
+		r0 = rf(ctx)
+	} else {
+		r0 = ret.Error(0)
+	}
+
+	return r0
+}
+
+// BatchProcessor_Reset_Call is a *mock.Call that shadows Run/Return methods with type explicit version for method 'Reset'
+type BatchProcessor_Reset_Call struct {
+	*mock.Call
+}
+
+// Reset is a helper method to define mock.On call
+//   - ctx context.Context
+func (_e *BatchProcessor_Expecter) Reset(ctx interface{}) *BatchProcessor_Reset_Call {
+	return &BatchProcessor_Reset_Call{Call: _e.mock.On("Reset", ctx)}
+}
+
+func (_c *BatchProcessor_Reset_Call) Run(run func(ctx context.Context)) *BatchProcessor_Reset_Call {
+	_c.Call.Run(func(args mock.Arguments) {
+		run(args[0].(context.Context))
+	})
+	return _c
+}
+
+func (_c *BatchProcessor_Reset_Call) Return(_a0 error) *BatchProcessor_Reset_Call {
+	_c.Call.Return(_a0)
+	return _c
+}
+
+func (_c *BatchProcessor_Reset_Call) RunAndReturn(run func(context.Context) error) *BatchProcessor_Reset_Call {
+	_c.Call.Return(run)
+	return _c
+}
+
 // NewBatchProcessor creates a new instance of BatchProcessor. It also registers a testing interface on the mock and a cleanup function to assert the mocks expectations.
 // The first argument is typically a *testing.T value.
 func NewBatchProcessor(t interface {
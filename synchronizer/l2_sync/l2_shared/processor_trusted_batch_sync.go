@@ -3,6 +3,7 @@ package l2_shared
 import (
 	"context"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"time"
 
@@ -11,6 +12,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	syncCommon "github.com/0xPolygonHermez/zkevm-node/synchronizer/common"
 	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	"github.com/jackc/pgx/v4"
 )
 
@@ -28,6 +30,14 @@ const (
 	NothingProcessMode BatchProcessMode = "nothing"
 )
 
+var (
+	// ErrProcessingTimeout is returned when processing a trusted batch takes longer than the configured MaxProcessingTime
+	ErrProcessingTimeout = errors.New("timeout processing trusted batch")
+	// ErrBatchResultMismatch is returned when the stateRoot or the LocalExitRoot of a processed batch are
+	// different from the ones of the trusted batch
+	ErrBatchResultMismatch = errors.New("batch result doesn't match the trusted batch")
+)
+
 // ProcessData contains the data required to process a batch
 type ProcessData struct {
 	BatchNumber       uint64
@@ -43,6 +53,16 @@ type ProcessData struct {
 	Description string
 	// DebugPrefix is used to log, must prefix all logs entries
 	DebugPrefix string
+	// RetryCount is the number of consecutive failed attempts to process this batch
+	RetryCount int
+}
+
+// BatchProcessedEvent is published to the EventBus when a trusted batch has been processed successfully
+type BatchProcessedEvent struct {
+	BatchNumber uint64
+	Mode        BatchProcessMode
+	// BatchClosed is true if the batch has been closed
+	BatchClosed bool
 }
 
 // ProcessResponse contains the response of the process of a batch
@@ -79,15 +99,54 @@ type SyncTrustedBatchExecutor interface {
 type ProcessorTrustedBatchSync struct {
 	Steps        SyncTrustedBatchExecutor
 	timeProvider syncCommon.TimeProvider
+	eventBus     syncCommon.EventBus
+	counters     batchSyncCounters
+	cfg          ProcessorTrustedBatchSyncConfig
+	// forkIDs is used to detect the first batch of a new forkID (nil disables it)
+	forkIDs ForkIDGetter
+	// forkIDCheckedBatchNumber is the last batch checked for a forkID upgrade, so the upgrade is handled
+	// only once while the batch is WIP
+	forkIDCheckedBatchNumber uint64
+	// failedBatchNumber and retryCount track the consecutive failures processing a batch
+	failedBatchNumber uint64
+	retryCount        int
+}
+
+// ProcessorTrustedBatchSyncConfig is the configuration of ProcessorTrustedBatchSync
+type ProcessorTrustedBatchSyncConfig struct {
+	// MaxIncrementalRetries is the number of failed attempts after which a batch that
+	// would be processed incrementally is fully reprocessed (0 disables it)
+	MaxIncrementalRetries int
+	// MaxProcessingTime is the maximum time to process a trusted batch (0 disables it)
+	MaxProcessingTime time.Duration
+	// MaxFlushIDRetries is the number of times a failed check of the flushID is retried, with an exponential
+	// backoff, before returning the error (0 disables it)
+	MaxFlushIDRetries int
+}
+
+// ForkIDGetter returns the forkID of a batch
+type ForkIDGetter interface {
+	GetForkIDByBatchNumber(batchNumber uint64) uint64
 }
 
-// NewProcessorTrustedBatchSync creates a new SyncTrustedStateBatchExecutorTemplate
+// NewProcessorTrustedBatchSync creates a new SyncTrustedStateBatchExecutorTemplate. A BatchProcessedEvent
+// is published to eventBus for each batch processed, if eventBus is nil an internal one is used.
+// forkIDs is used to fully reprocess the first batch of a new forkID
 func NewProcessorTrustedBatchSync(steps SyncTrustedBatchExecutor,
-	timeProvider syncCommon.TimeProvider) *ProcessorTrustedBatchSync {
-	return &ProcessorTrustedBatchSync{
+	timeProvider syncCommon.TimeProvider, cfg ProcessorTrustedBatchSyncConfig, eventBus syncCommon.EventBus,
+	forkIDs ForkIDGetter) *ProcessorTrustedBatchSync {
+	if eventBus == nil {
+		eventBus = syncCommon.NewSyncEventBus()
+	}
+	res := &ProcessorTrustedBatchSync{
 		Steps:        steps,
 		timeProvider: timeProvider,
+		eventBus:     eventBus,
+		cfg:          cfg,
+		forkIDs:      forkIDs,
 	}
+	eventBus.Subscribe(res.counters.onEvent)
+	return res
 }
 
 // ProcessTrustedBatch processes a trusted batch and return the new state
@@ -104,13 +163,20 @@ func (s *ProcessorTrustedBatchSync) ProcessTrustedBatch(ctx context.Context, tru
 		tmpBatch := *status.LastTrustedBatches[1]
 		statePreviousBatch = &tmpBatch
 	}
-	processMode, err := s.getModeForProcessBatch(trustedBatch, stateCurrentBatch, statePreviousBatch)
+	processMode, err := s.getModeForProcessBatch(ctx, trustedBatch, stateCurrentBatch, statePreviousBatch, s.getRetryCount(uint64(trustedBatch.Number)))
 	processMode.DebugPrefix = fmt.Sprintf("%s mode %s:", debugPrefix, processMode.Mode)
 	if err != nil {
 		log.Error("%s error getting processMode. Error: ", debugPrefix, trustedBatch.Number, err)
+		s.counters.countError()
 		return nil, err
 	}
 	log.Infof("%s  Processing trusted batch: mode=%s desc=%s", processMode.DebugPrefix, processMode.Mode, processMode.Description)
+	processCtx := ctx
+	if s.cfg.MaxProcessingTime > 0 {
+		var cancel context.CancelFunc
+		processCtx, cancel = context.WithTimeout(ctx, s.cfg.MaxProcessingTime)
+		defer cancel()
+	}
 	var processBatchResp *ProcessResponse = nil
 	switch processMode.Mode {
 	case NothingProcessMode:
@@ -118,16 +184,21 @@ func (s *ProcessorTrustedBatchSync) ProcessTrustedBatch(ctx context.Context, tru
 		err = nil
 	case FullProcessMode:
 		log.Debugf("%s is not on database, so is the first time we process it", debugPrefix)
-		processBatchResp, err = s.Steps.FullProcess(ctx, &processMode, dbTx)
+		processBatchResp, err = s.Steps.FullProcess(processCtx, &processMode, dbTx)
 	case IncrementalProcessMode:
 		log.Debugf("%s is partially synchronized", processMode.DebugPrefix)
-		processBatchResp, err = s.Steps.IncrementalProcess(ctx, &processMode, dbTx)
+		processBatchResp, err = s.Steps.IncrementalProcess(processCtx, &processMode, dbTx)
 	case ReprocessProcessMode:
 		log.Debugf("%s is partially synchronized but we don't have intermediate stateRoot so it needs to be fully reprocessed", processMode.DebugPrefix)
-		processBatchResp, err = s.Steps.ReProcess(ctx, &processMode, dbTx)
+		processBatchResp, err = s.Steps.ReProcess(processCtx, &processMode, dbTx)
+	}
+	if err != nil && ctx.Err() == nil && errors.Is(processCtx.Err(), context.DeadlineExceeded) {
+		err = fmt.Errorf("%w %v after %s: %s", ErrProcessingTimeout, trustedBatch.Number, s.cfg.MaxProcessingTime, err.Error())
 	}
 	if err != nil {
 		log.Errorf("%s error processing trusted batch. Error: %s", processMode.DebugPrefix, err)
+		s.counters.countError()
+		s.registerFailure(processMode.BatchNumber)
 		return nil, err
 	}
 
@@ -135,10 +206,19 @@ func (s *ProcessorTrustedBatchSync) ProcessTrustedBatch(ctx context.Context, tru
 		err = checkProcessBatchResultMatchExpected(&processMode, processBatchResp.ProcessBatchResponse)
 		if err != nil {
 			log.Error("%s error verifying batch result!  Error: ", debugPrefix, err)
+			s.counters.countError()
+			s.registerFailure(processMode.BatchNumber)
 			return nil, err
 		}
 	}
 
+	s.retryCount = 0
+	s.eventBus.Publish(BatchProcessedEvent{
+		BatchNumber: processMode.BatchNumber,
+		Mode:        processMode.Mode,
+		BatchClosed: processMode.BatchMustBeClosed,
+	})
+
 	if processBatchResp != nil && !processBatchResp.ClearCache {
 		newStatus := updateCache(status, processBatchResp, processMode.BatchMustBeClosed)
 		log.Debugf("%s Batch %v synchronized, updated cache for next run", debugPrefix, trustedBatch.Number)
@@ -149,6 +229,31 @@ func (s *ProcessorTrustedBatchSync) ProcessTrustedBatch(ctx context.Context, tru
 	}
 }
 
+// Reset is called when a critical inconsistency is detected. The cached LastTrustedBatches are cleared
+// by the caller, so it only counts the reset
+func (s *ProcessorTrustedBatchSync) Reset(ctx context.Context) error {
+	s.counters.cacheResetCount.Add(1)
+	log.Warn("trusted batch processor cache reset")
+	return nil
+}
+
+// getRetryCount returns the number of consecutive failed attempts to process the batch
+func (s *ProcessorTrustedBatchSync) getRetryCount(batchNumber uint64) int {
+	if s.failedBatchNumber != batchNumber {
+		return 0
+	}
+	return s.retryCount
+}
+
+// registerFailure increments the retry count of the batch, restarting it if the
+// previous failure was for another batch
+func (s *ProcessorTrustedBatchSync) registerFailure(batchNumber uint64) {
+	s.retryCount = s.getRetryCount(batchNumber) + 1
+	s.failedBatchNumber = batchNumber
+}
+
+// updateCache returns the trusted state for the next run. The batch is copied so the returned state
+// doesn't share memory with the response
 func updateCache(status TrustedState, response *ProcessResponse, closedBatch bool) TrustedState {
 	res := TrustedState{
 		LastTrustedBatches: []*state.Batch{nil, nil},
@@ -157,7 +262,7 @@ func updateCache(status TrustedState, response *ProcessResponse, closedBatch boo
 		return res
 	}
 	if response.UpdateBatch != nil {
-		res.LastTrustedBatches[0] = response.UpdateBatch
+		res.LastTrustedBatches[0] = copyBatch(response.UpdateBatch)
 	}
 	if response.ProcessBatchResponse != nil && response.UpdateBatchWithProcessBatchResponse && res.LastTrustedBatches[0] != nil {
 		//if res.LastTrustedBatches[0].BatchNumber != uint64(response.ProcessBatchResponse.NewBatchNumber) {
@@ -175,7 +280,22 @@ func updateCache(status TrustedState, response *ProcessResponse, closedBatch boo
 	return res
 }
 
-func (s *ProcessorTrustedBatchSync) getModeForProcessBatch(trustedNodeBatch *types.Batch, stateBatch *state.Batch, statePreviousBatch *state.Batch) (ProcessData, error) {
+// copyBatch returns a deep copy of the batch
+func copyBatch(batch *state.Batch) *state.Batch {
+	res := *batch
+	res.BatchL2Data = common.CopyBytes(batch.BatchL2Data)
+	if batch.Transactions != nil {
+		res.Transactions = make([]ethTypes.Transaction, len(batch.Transactions))
+		copy(res.Transactions, batch.Transactions)
+	}
+	if batch.ForcedBatchNum != nil {
+		forcedBatchNum := *batch.ForcedBatchNum
+		res.ForcedBatchNum = &forcedBatchNum
+	}
+	return &res
+}
+
+func (s *ProcessorTrustedBatchSync) getModeForProcessBatch(ctx context.Context, trustedNodeBatch *types.Batch, stateBatch *state.Batch, statePreviousBatch *state.Batch, retryCount int) (ProcessData, error) {
 	// Check parameters
 	if trustedNodeBatch == nil || statePreviousBatch == nil {
 		return ProcessData{}, fmt.Errorf("trustedNodeBatch and statePreviousBatch can't be nil")
@@ -200,7 +320,14 @@ func (s *ProcessorTrustedBatchSync) getModeForProcessBatch(trustedNodeBatch *typ
 		} else {
 			// We have a previous batch, but in node something change
 			// We have processed this batch before, and we have the intermediate state root, so is going to be process only new Tx.
-			if stateBatch.StateRoot != state.ZeroHash {
+			if stateBatch.StateRoot != state.ZeroHash && s.cfg.MaxIncrementalRetries > 0 && retryCount >= s.cfg.MaxIncrementalRetries {
+				// The incremental process has failed too many times, so the batch is fully reprocessed
+				result = ProcessData{
+					Mode:         ReprocessProcessMode,
+					OldStateRoot: statePreviousBatch.StateRoot,
+					Description:  fmt.Sprintf("batch exists + incremental process failed %d times ", retryCount) + strSync,
+				}
+			} else if stateBatch.StateRoot != state.ZeroHash {
 				result = ProcessData{
 					Mode:         IncrementalProcessMode,
 					OldStateRoot: stateBatch.StateRoot,
@@ -219,7 +346,16 @@ func (s *ProcessorTrustedBatchSync) getModeForProcessBatch(trustedNodeBatch *typ
 	if result.Mode == "" {
 		return result, fmt.Errorf("failed to get mode for process batch %v", trustedNodeBatch.Number)
 	}
+	if s.checkForkIDUpgrade(uint64(trustedNodeBatch.Number)) && result.Mode == IncrementalProcessMode {
+		// The intermediate state root could have been calculated with the previous forkID, so all the txs are reprocessed
+		result = ProcessData{
+			Mode:         ReprocessProcessMode,
+			OldStateRoot: statePreviousBatch.StateRoot,
+			Description:  "batch exists + first batch of a new forkID",
+		}
+	}
 	result.BatchNumber = uint64(trustedNodeBatch.Number)
+	result.RetryCount = retryCount
 	result.BatchMustBeClosed = result.Mode != NothingProcessMode && isTrustedBatchClosed(trustedNodeBatch)
 	result.StateBatch = stateBatch
 	result.TrustedBatch = trustedNodeBatch
@@ -228,6 +364,22 @@ func (s *ProcessorTrustedBatchSync) getModeForProcessBatch(trustedNodeBatch *typ
 	return result, nil
 }
 
+// checkForkIDUpgrade returns true if the batch is the first batch of a new forkID. The forkID intervals
+// in memory are already updated when the forkID is synced from L1. Each batch number is checked only once,
+// so the next iterations of a WIP batch are not forced to be reprocessed again
+func (s *ProcessorTrustedBatchSync) checkForkIDUpgrade(batchNumber uint64) bool {
+	if s.forkIDs == nil || batchNumber == 0 || batchNumber == s.forkIDCheckedBatchNumber {
+		return false
+	}
+	s.forkIDCheckedBatchNumber = batchNumber
+	newForkID := s.forkIDs.GetForkIDByBatchNumber(batchNumber)
+	if newForkID == s.forkIDs.GetForkIDByBatchNumber(batchNumber-1) {
+		return false
+	}
+	log.Infof("batch %d is the first batch of forkID %d", batchNumber, newForkID)
+	return true
+}
+
 func isTrustedBatchClosed(batch *types.Batch) bool {
 	return batch.Closed
 }
@@ -279,10 +431,10 @@ func checkIfSyncedWhitoutWIP(stateBatch *state.Batch, trustedBatch *types.Batch)
 
 func checkStateRootAndLER(batchNumber uint64, expectedStateRoot common.Hash, expectedLER common.Hash, calculatedStateRoot common.Hash, calculatedLER common.Hash) error {
 	if calculatedStateRoot != expectedStateRoot {
-		return fmt.Errorf("batch %v: stareRoot calculated [%s] is different from the one in the batch [%s]", batchNumber, calculatedStateRoot, expectedStateRoot)
+		return fmt.Errorf("batch %v: stareRoot calculated [%s] is different from the one in the batch [%s]: %w", batchNumber, calculatedStateRoot, expectedStateRoot, ErrBatchResultMismatch)
 	}
 	if calculatedLER != expectedLER {
-		return fmt.Errorf("batch %v: LocalExitRoot calculated [%s] is different from the one in the batch [%s]", batchNumber, calculatedLER, expectedLER)
+		return fmt.Errorf("batch %v: LocalExitRoot calculated [%s] is different from the one in the batch [%s]: %w", batchNumber, calculatedLER, expectedLER, ErrBatchResultMismatch)
 	}
 	return nil
 }
@@ -0,0 +1,226 @@
+package l2_shared
+
+import (
+	"bytes"
+	"context"
+	"encoding/hex"
+	"sync"
+	"testing"
+
+	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
+	"github.com/0xPolygonHermez/zkevm-node/state"
+	syncCommon "github.com/0xPolygonHermez/zkevm-node/synchronizer/common"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/require"
+)
+
+const (
+	// changeL2Block + deltaTimeStamp + indexL1InfoTree
+	codedL2BlockHeader = "0b73e6af6f00000000"
+	// 2 x [ tx coded in RLP + r,s,v,efficiencyPercentage]
+	codedRLP2Txs1 = "ee02843b9aca00830186a0944d5cf5032b2a844602278b01199ed191a86c93ff88016345785d8a0000808203e88080bff0e780ba7db409339fd3f71969fa2cbf1b8535f6c725a1499d3318d3ef9c2b6340ddfab84add2c188f9efddb99771db1fe621c981846394ea4f035c85bcdd51bffee03843b9aca00830186a0944d5cf5032b2a844602278b01199ed191a86c93ff88016345785d8a0000808203e880805b346aa02230b22e62f73608de9ff39a162a6c24be9822209c770e3685b92d0756d5316ef954eefc58b068231ccea001fb7ac763ebe03afd009ad71cab36861e1bff"
+)
+
+type batchFields struct {
+	number   uint64
+	ger      common.Hash
+	ler      common.Hash
+	sr       common.Hash
+	coinbase common.Address
+	l2Data   []byte
+}
+
+func (b batchFields) toStateBatch() *state.Batch {
+	return &state.Batch{
+		BatchNumber:    b.number,
+		GlobalExitRoot: b.ger,
+		LocalExitRoot:  b.ler,
+		StateRoot:      b.sr,
+		Coinbase:       b.coinbase,
+		BatchL2Data:    b.l2Data,
+	}
+}
+
+func (b batchFields) toTrustedBatch() *types.Batch {
+	return &types.Batch{
+		Number:         types.ArgUint64(b.number),
+		GlobalExitRoot: b.ger,
+		LocalExitRoot:  b.ler,
+		StateRoot:      b.sr,
+		Coinbase:       b.coinbase,
+		BatchL2Data:    b.l2Data,
+	}
+}
+
+func (b batchFields) equal(other batchFields) bool {
+	return b.number == other.number && b.ger == other.ger && b.ler == other.ler && b.sr == other.sr &&
+		b.coinbase == other.coinbase && bytes.Equal(b.l2Data, other.l2Data)
+}
+
+func FuzzCheckIfSyncedWithoutWIP(f *testing.F) {
+	batchL2Data, err := hex.DecodeString(codedL2BlockHeader + codedRLP2Txs1)
+	require.NoError(f, err)
+	trustedBatchL2Data, err := hex.DecodeString(codedL2BlockHeader + codedRLP2Txs1 + codedL2BlockHeader + codedRLP2Txs1)
+	require.NoError(f, err)
+	stateRoot := common.HexToHash("0x723e5c4c7ee7890e1e66c2e391d553ee792d2204ecb4fe921830f12f8dcd1a92").Bytes()
+
+	// Synced batches
+	f.Add(uint64(123), uint64(123), []byte{}, []byte{}, []byte{}, []byte{}, stateRoot, stateRoot, []byte{}, []byte{}, batchL2Data, batchL2Data)
+	// Trusted batch with more data than the state batch
+	f.Add(uint64(123), uint64(123), []byte{}, []byte{}, []byte{}, []byte{}, stateRoot, stateRoot, []byte{}, []byte{}, batchL2Data, trustedBatchL2Data)
+	// Different batch number and state root
+	f.Add(uint64(123), uint64(124), []byte{1}, []byte{1}, []byte{2}, []byte{2}, []byte{}, stateRoot, []byte{3}, []byte{3}, batchL2Data, batchL2Data)
+
+	f.Fuzz(func(t *testing.T, numberA, numberB uint64, gerA, gerB, lerA, lerB, srA, srB, coinbaseA, coinbaseB, l2DataA, l2DataB []byte) {
+		a := batchFields{
+			number:   numberA,
+			ger:      common.BytesToHash(gerA),
+			ler:      common.BytesToHash(lerA),
+			sr:       common.BytesToHash(srA),
+			coinbase: common.BytesToAddress(coinbaseA),
+			l2Data:   l2DataA,
+		}
+		b := batchFields{
+			number:   numberB,
+			ger:      common.BytesToHash(gerB),
+			ler:      common.BytesToHash(lerB),
+			sr:       common.BytesToHash(srB),
+			coinbase: common.BytesToAddress(coinbaseB),
+			l2Data:   l2DataB,
+		}
+
+		synced, _ := checkIfSyncedWhitoutWIP(a.toStateBatch(), b.toTrustedBatch())
+		syncedSwapped, _ := checkIfSyncedWhitoutWIP(b.toStateBatch(), a.toTrustedBatch())
+
+		require.Equal(t, synced, syncedSwapped)
+		// A false positive makes the caller skip the reprocessing of the batch, so the batches
+		// must only be considered synced if all the checked fields are equal
+		require.Equal(t, a.equal(b), synced)
+	})
+}
+
+func TestUpdateCacheDoesNotShareMemoryWithResponse(t *testing.T) {
+	forcedBatchNum := uint64(3)
+	newOriginalBatch := func() *state.Batch {
+		forcedBatchNum := forcedBatchNum
+		return &state.Batch{
+			BatchNumber:    123,
+			Coinbase:       common.HexToAddress("0x1"),
+			BatchL2Data:    []byte{1, 2, 3},
+			StateRoot:      common.HexToHash("0x2"),
+			LocalExitRoot:  common.HexToHash("0x3"),
+			AccInputHash:   common.HexToHash("0x4"),
+			GlobalExitRoot: common.HexToHash("0x5"),
+			ForcedBatchNum: &forcedBatchNum,
+			WIP:            true,
+		}
+	}
+
+	for _, closedBatch := range []bool{false, true} {
+		updateBatch := newOriginalBatch()
+		response := &ProcessResponse{
+			ProcessBatchResponse: &state.ProcessBatchResponse{
+				NewStateRoot:     common.HexToHash("0x6"),
+				NewLocalExitRoot: common.HexToHash("0x7"),
+				NewAccInputHash:  common.HexToHash("0x8"),
+			},
+			UpdateBatch:                         updateBatch,
+			UpdateBatchWithProcessBatchResponse: true,
+		}
+
+		res := updateCache(TrustedState{}, response, closedBatch)
+
+		cachedBatch := res.LastTrustedBatches[0]
+		if closedBatch {
+			cachedBatch = res.LastTrustedBatches[1]
+		}
+		require.NotNil(t, cachedBatch)
+		require.Equal(t, response.ProcessBatchResponse.NewStateRoot, cachedBatch.StateRoot)
+		// The response batch must not be updated with the process batch response
+		require.Equal(t, newOriginalBatch(), updateBatch)
+
+		// Modify the cached batch concurrently with reading the response batch, so the race detector
+		// catches any memory shared between them
+		var wg sync.WaitGroup
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			cachedBatch.BatchNumber++
+			cachedBatch.Coinbase = common.HexToAddress("0xff")
+			cachedBatch.BatchL2Data[0] = 0xff
+			cachedBatch.StateRoot = common.HexToHash("0xff")
+			cachedBatch.LocalExitRoot = common.HexToHash("0xff")
+			cachedBatch.AccInputHash = common.HexToHash("0xff")
+			cachedBatch.GlobalExitRoot = common.HexToHash("0xff")
+			*cachedBatch.ForcedBatchNum++
+			cachedBatch.WIP = !cachedBatch.WIP
+		}()
+		originalBatch := *updateBatch
+		originalL2Data := bytes.Clone(updateBatch.BatchL2Data)
+		originalForcedBatchNum := *updateBatch.ForcedBatchNum
+		wg.Wait()
+
+		require.Equal(t, newOriginalBatch(), updateBatch)
+		require.Equal(t, newOriginalBatch().BatchNumber, originalBatch.BatchNumber)
+		require.Equal(t, []byte{1, 2, 3}, originalL2Data)
+		require.Equal(t, forcedBatchNum, originalForcedBatchNum)
+	}
+}
+
+func TestGetModeForProcessBatchEscalatesToReprocessAfterMaxRetries(t *testing.T) {
+	sut := NewProcessorTrustedBatchSync(nil, syncCommon.DefaultTimeProvider{}, ProcessorTrustedBatchSyncConfig{MaxIncrementalRetries: 2}, nil, nil)
+	trustedBatch := &types.Batch{Number: 123, StateRoot: common.HexToHash("0x2")}
+	stateBatch := &state.Batch{BatchNumber: 123, StateRoot: common.HexToHash("0x1"), WIP: true}
+	statePreviousBatch := &state.Batch{BatchNumber: 122, StateRoot: common.HexToHash("0x3")}
+
+	for retry := 0; retry < 2; retry++ {
+		processData, err := sut.getModeForProcessBatch(context.Background(), trustedBatch, stateBatch, statePreviousBatch, sut.getRetryCount(123))
+		require.NoError(t, err)
+		require.Equal(t, IncrementalProcessMode, processData.Mode)
+		require.Equal(t, retry, processData.RetryCount)
+		require.Equal(t, stateBatch.StateRoot, processData.OldStateRoot)
+		sut.registerFailure(123)
+	}
+
+	processData, err := sut.getModeForProcessBatch(context.Background(), trustedBatch, stateBatch, statePreviousBatch, sut.getRetryCount(123))
+	require.NoError(t, err)
+	require.Equal(t, ReprocessProcessMode, processData.Mode)
+	require.Equal(t, 2, processData.RetryCount)
+	require.Equal(t, statePreviousBatch.StateRoot, processData.OldStateRoot)
+
+	// A failure on another batch restarts the count
+	sut.registerFailure(124)
+	require.Equal(t, 0, sut.getRetryCount(123))
+	require.Equal(t, 1, sut.getRetryCount(124))
+}
+
+type forkIDGetterStub map[uint64]uint64
+
+func (f forkIDGetterStub) GetForkIDByBatchNumber(batchNumber uint64) uint64 {
+	return f[batchNumber]
+}
+
+func TestGetModeForProcessBatchForcesReprocessOnForkIDUpgrade(t *testing.T) {
+	forkIDs := forkIDGetterStub{122: 7, 123: 8}
+	sut := NewProcessorTrustedBatchSync(nil, syncCommon.DefaultTimeProvider{}, ProcessorTrustedBatchSyncConfig{}, nil, forkIDs)
+	trustedBatch := &types.Batch{Number: 123, StateRoot: common.HexToHash("0x2")}
+	stateBatch := &state.Batch{BatchNumber: 123, StateRoot: common.HexToHash("0x1"), WIP: true}
+	statePreviousBatch := &state.Batch{BatchNumber: 122, StateRoot: common.HexToHash("0x3")}
+
+	processData, err := sut.getModeForProcessBatch(context.Background(), trustedBatch, stateBatch, statePreviousBatch, 0)
+	require.NoError(t, err)
+	require.Equal(t, ReprocessProcessMode, processData.Mode)
+	require.Equal(t, statePreviousBatch.StateRoot, processData.OldStateRoot)
+
+	// The batch is still WIP: the upgrade has already been handled, so it's processed incrementally
+	processData, err = sut.getModeForProcessBatch(context.Background(), trustedBatch, stateBatch, statePreviousBatch, 0)
+	require.NoError(t, err)
+	require.Equal(t, IncrementalProcessMode, processData.Mode)
+
+	// Same forkID as the previous batch: no upgrade
+	sut = NewProcessorTrustedBatchSync(nil, syncCommon.DefaultTimeProvider{}, ProcessorTrustedBatchSyncConfig{}, nil, forkIDs)
+	forkIDs[122] = 8
+	processData, err = sut.getModeForProcessBatch(context.Background(), trustedBatch, stateBatch, statePreviousBatch, 0)
+	require.NoError(t, err)
+	require.Equal(t, IncrementalProcessMode, processData.Mode)
+}
@@ -8,6 +8,7 @@ package l2_shared
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"math/big"
 	"time"
@@ -22,6 +23,8 @@ import (
 
 const (
 	firstTrustedBatchNumber = uint64(2)
+	// initialFlushIDRetryBackoff is the time to wait before the first retry of CheckFlushID, it's doubled on each retry
+	initialFlushIDRetryBackoff = 100 * time.Millisecond
 )
 
 // StateInterface contains the methods required to interact with the state.
@@ -36,6 +39,8 @@ type StateInterface interface {
 type BatchProcessor interface {
 	// ProcessTrustedBatch processes a trusted batch
 	ProcessTrustedBatch(ctx context.Context, trustedBatch *types.Batch, status TrustedState, dbTx pgx.Tx, debugPrefix string) (*TrustedState, error)
+	// Reset clears the in-memory state of the processor
+	Reset(ctx context.Context) error
 }
 
 // TrustedState is the trusted state, basically contains the batch cache
@@ -50,14 +55,20 @@ type TrustedBatchesRetrieve struct {
 	sync                   syncinterfaces.SynchronizerFlushIDManager
 	TrustedStateMngr       TrustedStateManager
 	firstBatchNumberToSync uint64
+	// maxFlushIDRetries is the number of times CheckFlushID is retried before returning the error
+	maxFlushIDRetries int
+	// flushIDRetryBackoff is the time to wait before the first retry of CheckFlushID
+	flushIDRetryBackoff time.Duration
 }
 
-// NewTrustedBatchesRetrieve creates a new SyncTrustedStateTemplate
+// NewTrustedBatchesRetrieve creates a new SyncTrustedStateTemplate. A failed check of the flushID is
+// retried up to maxFlushIDRetries times
 func NewTrustedBatchesRetrieve(batchExecutor BatchProcessor,
 	zkEVMClient syncinterfaces.ZKEVMClientTrustedBatchesGetter,
 	state StateInterface,
 	sync syncinterfaces.SynchronizerFlushIDManager,
 	TrustedStateMngr TrustedStateManager,
+	maxFlushIDRetries int,
 ) *TrustedBatchesRetrieve {
 	return &TrustedBatchesRetrieve{
 		batchExecutor:          batchExecutor,
@@ -66,6 +77,8 @@ func NewTrustedBatchesRetrieve(batchExecutor BatchProcessor,
 		sync:                   sync,
 		TrustedStateMngr:       TrustedStateMngr,
 		firstBatchNumberToSync: firstTrustedBatchNumber,
+		maxFlushIDRetries:      maxFlushIDRetries,
+		flushIDRetryBackoff:    initialFlushIDRetryBackoff,
 	}
 }
 
@@ -132,10 +145,15 @@ func (s *TrustedBatchesRetrieve) syncTrustedBatchesToFrom(ctx context.Context, l
 		if err != nil {
 			log.Errorf("%s error processing trusted batch %d: %v", debugPrefix, batchNumberToSync, err)
 			s.TrustedStateMngr.Clear()
+			if errors.Is(err, ErrBatchResultMismatch) {
+				if resetErr := s.batchExecutor.Reset(ctx); resetErr != nil {
+					log.Errorf("%s error resetting the batch processor. Error: %v", debugPrefix, resetErr)
+				}
+			}
 			return rollback(ctx, dbTx, err)
 		}
 		log.Debug("%s Checking FlushID to commit trustedState data to db", debugPrefix)
-		err = s.sync.CheckFlushID(dbTx)
+		err = s.checkFlushIDWithRetries(ctx, dbTx, debugPrefix)
 		if err != nil {
 			log.Errorf("%s error checking flushID. Error: %v", debugPrefix, err)
 			s.TrustedStateMngr.Clear()
@@ -161,6 +179,24 @@ func (s *TrustedBatchesRetrieve) syncTrustedBatchesToFrom(ctx context.Context, l
 	return nil
 }
 
+// checkFlushIDWithRetries calls CheckFlushID retrying it up to maxFlushIDRetries times with an exponential
+// backoff, so a transient failure doesn't force to process again the whole trusted batch
+func (s *TrustedBatchesRetrieve) checkFlushIDWithRetries(ctx context.Context, dbTx pgx.Tx, debugPrefix string) error {
+	backoff := s.flushIDRetryBackoff
+	err := s.sync.CheckFlushID(dbTx)
+	for retry := 1; err != nil && retry <= s.maxFlushIDRetries; retry++ {
+		log.Warnf("%s error checking flushID, retrying (%d/%d) in %s. Error: %v", debugPrefix, retry, s.maxFlushIDRetries, backoff, err)
+		select {
+		case <-ctx.Done():
+			return ctx.Err()
+		case <-time.After(backoff):
+		}
+		backoff *= 2
+		err = s.sync.CheckFlushID(dbTx)
+	}
+	return err
+}
+
 func rollback(ctx context.Context, dbTx pgx.Tx, err error) error {
 	rollbackErr := dbTx.Rollback(ctx)
 	if rollbackErr != nil {
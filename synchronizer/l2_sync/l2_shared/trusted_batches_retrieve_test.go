@@ -0,0 +1,39 @@
+package l2_shared
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	mock_syncinterfaces "github.com/0xPolygonHermez/zkevm-node/synchronizer/common/syncinterfaces/mocks"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckFlushIDWithRetriesRetriesBeforeFailing(t *testing.T) {
+	syncMock := mock_syncinterfaces.NewSynchronizerFlushIDManager(t)
+	sut := NewTrustedBatchesRetrieve(nil, nil, nil, syncMock, TrustedStateManager{}, 2)
+	sut.flushIDRetryBackoff = time.Millisecond
+	errFlushID := errors.New("flushID mismatch")
+
+	syncMock.EXPECT().CheckFlushID(mock.Anything).Return(errFlushID).Once()
+	syncMock.EXPECT().CheckFlushID(mock.Anything).Return(nil).Once()
+	err := sut.checkFlushIDWithRetries(context.Background(), nil, "")
+	require.NoError(t, err)
+
+	syncMock.EXPECT().CheckFlushID(mock.Anything).Return(errFlushID).Times(3)
+	err = sut.checkFlushIDWithRetries(context.Background(), nil, "")
+	require.ErrorIs(t, err, errFlushID)
+}
+
+func TestCheckFlushIDWithRetriesStopsWhenContextIsDone(t *testing.T) {
+	syncMock := mock_syncinterfaces.NewSynchronizerFlushIDManager(t)
+	sut := NewTrustedBatchesRetrieve(nil, nil, nil, syncMock, TrustedStateManager{}, 2)
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	syncMock.EXPECT().CheckFlushID(mock.Anything).Return(errors.New("flushID mismatch")).Once()
+	err := sut.checkFlushIDWithRetries(ctx, nil, "")
+	require.ErrorIs(t, err, context.Canceled)
+}
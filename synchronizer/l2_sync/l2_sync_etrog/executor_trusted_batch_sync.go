@@ -8,6 +8,7 @@ import (
 
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
 	"github.com/0xPolygonHermez/zkevm-node/log"
+	"github.com/0xPolygonHermez/zkevm-node/metrics"
 	"github.com/0xPolygonHermez/zkevm-node/state"
 	syncCommon "github.com/0xPolygonHermez/zkevm-node/synchronizer/common"
 	"github.com/0xPolygonHermez/zkevm-node/synchronizer/common/syncinterfaces"
@@ -52,14 +53,16 @@ type SyncTrustedBatchExecutorForEtrog struct {
 // NewSyncTrustedBatchExecutorForEtrog creates a new prcessor for sync with L2 batches
 func NewSyncTrustedBatchExecutorForEtrog(zkEVMClient syncinterfaces.ZKEVMClientTrustedBatchesGetter,
 	state l2_shared.StateInterface, stateBatchExecutor StateInterface,
-	sync syncinterfaces.SynchronizerFlushIDManager, timeProvider syncCommon.TimeProvider) *l2_shared.TrustedBatchesRetrieve {
+	sync syncinterfaces.SynchronizerFlushIDManager, timeProvider syncCommon.TimeProvider, cfg l2_shared.ProcessorTrustedBatchSyncConfig,
+	eventBus syncCommon.EventBus) *l2_shared.TrustedBatchesRetrieve {
 	executorSteps := &SyncTrustedBatchExecutorForEtrog{
 		state: stateBatchExecutor,
 		sync:  sync,
 	}
 
-	executor := l2_shared.NewProcessorTrustedBatchSync(executorSteps, timeProvider)
-	a := l2_shared.NewTrustedBatchesRetrieve(executor, zkEVMClient, state, sync, *l2_shared.NewTrustedStateManager(timeProvider, time.Hour))
+	executor := l2_shared.NewProcessorTrustedBatchSync(executorSteps, timeProvider, cfg, eventBus, stateBatchExecutor)
+	metrics.RegisterCollectors(executor)
+	a := l2_shared.NewTrustedBatchesRetrieve(executor, zkEVMClient, state, sync, *l2_shared.NewTrustedStateManager(timeProvider, time.Hour), cfg.MaxFlushIDRetries)
 	return a
 }
 
@@ -165,7 +168,10 @@ func (b *SyncTrustedBatchExecutorForEtrog) IncrementalProcess(ctx context.Contex
 		return nil, err
 	}
 	debugStr := fmt.Sprintf("%s: Batch %d:", data.Mode, uint64(data.TrustedBatch.Number))
-	processBatchResp, err := b.processAndStoreTxs(ctx, &madeUpBatch, b.getProcessRequest(data, leafs, l1InfoRoot), dbTx, debugStr)
+	request := b.getProcessRequest(data, leafs, l1InfoRoot)
+	// Only the new txs are sent to the executor, the previous ones are already in the intermediate state root
+	request.Transactions = madeUpBatch.BatchL2Data
+	processBatchResp, err := b.processAndStoreTxs(ctx, &madeUpBatch, request, dbTx, debugStr)
 	if err != nil {
 		log.Errorf("%s error procesingAndStoringTxs. Error: ", data.DebugPrefix, err)
 		return nil, err
@@ -311,7 +317,7 @@ func (b *SyncTrustedBatchExecutorForEtrog) processAndStoreTxs(ctx context.Contex
 	if request.OldStateRoot == state.ZeroHash {
 		log.Warnf("%s Processing batch with oldStateRoot == zero....", debugPrefix)
 	}
-	processBatchResp, err := b.state.ProcessBatchV2(ctx, request, true)
+	processBatchResp, err := b.processBatchV2(ctx, request, true)
 	if err != nil {
 		log.Errorf("%s error processing sequencer batch for batch: %v error:%v ", debugPrefix, trustedBatch.Number, err)
 		return nil, err
@@ -339,6 +345,27 @@ func (b *SyncTrustedBatchExecutorForEtrog) processAndStoreTxs(ctx context.Contex
 	return processBatchResp, nil
 }
 
+// processBatchV2 calls state.ProcessBatchV2 returning ctx.Err() as soon as ctx is done, so a node shutdown
+// doesn't wait for the executor. The executor call is cancelled by ctx too and its response is discarded
+func (b *SyncTrustedBatchExecutorForEtrog) processBatchV2(ctx context.Context, request state.ProcessRequest, updateMerkleTree bool) (*state.ProcessBatchResponse, error) {
+	type processBatchResult struct {
+		response *state.ProcessBatchResponse
+		err      error
+	}
+	resultCh := make(chan processBatchResult, 1)
+	go func() {
+		response, err := b.state.ProcessBatchV2(ctx, request, updateMerkleTree)
+		resultCh <- processBatchResult{response: response, err: err}
+	}()
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-resultCh:
+		return result.response, result.err
+	}
+}
+
 func getResponseInfo(response *state.ProcessBatchResponse) string {
 	if len(response.BlockResponses) == 0 {
 		return "no blocks, no txs"
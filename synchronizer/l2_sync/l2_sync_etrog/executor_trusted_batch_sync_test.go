@@ -3,7 +3,9 @@ package l2_sync_etrog
 import (
 	"context"
 	"encoding/hex"
+	"math/big"
 	"testing"
+	"time"
 
 	"github.com/0xPolygonHermez/zkevm-node/jsonrpc/types"
 	"github.com/0xPolygonHermez/zkevm-node/log"
@@ -12,17 +14,94 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/synchronizer/l2_sync/l2_shared"
 	mock_l2_sync_etrog "github.com/0xPolygonHermez/zkevm-node/synchronizer/l2_sync/l2_sync_etrog/mocks"
 	"github.com/ethereum/go-ethereum/common"
+	ethTypes "github.com/ethereum/go-ethereum/core/types"
 	mock "github.com/stretchr/testify/mock"
 	"github.com/stretchr/testify/require"
 )
 
 const (
-	// changeL2Block + deltaTimeStamp + indexL1InfoTree
-	codedL2BlockHeader = "0b73e6af6f00000000"
-	// 2 x [ tx coded in RLP + r,s,v,efficiencyPercentage]
-	codedRLP2Txs1 = "ee02843b9aca00830186a0944d5cf5032b2a844602278b01199ed191a86c93ff88016345785d8a0000808203e88080bff0e780ba7db409339fd3f71969fa2cbf1b8535f6c725a1499d3318d3ef9c2b6340ddfab84add2c188f9efddb99771db1fe621c981846394ea4f035c85bcdd51bffee03843b9aca00830186a0944d5cf5032b2a844602278b01199ed191a86c93ff88016345785d8a0000808203e880805b346aa02230b22e62f73608de9ff39a162a6c24be9822209c770e3685b92d0756d5316ef954eefc58b068231ccea001fb7ac763ebe03afd009ad71cab36861e1bff"
+	// deltaTimestamp of the L2 blocks of the test batches
+	testDeltaTimestamp = uint32(0x73e6af6f)
+	// chainID used to sign the txs of the test batches
+	testChainID = 1000
 )
 
+// batchL2DataBuilder builds the BatchL2Data of a batch from its L2 blocks
+type batchL2DataBuilder struct {
+	batch state.BatchRawV2
+}
+
+func newBatchL2DataBuilder() *batchL2DataBuilder {
+	return &batchL2DataBuilder{}
+}
+
+// AddL2Block adds a L2 block with the txs, all of them with a 100% efficiency percentage
+func (b *batchL2DataBuilder) AddL2Block(deltaTimestamp uint32, indexL1InfoTree uint32, txs ...*ethTypes.Transaction) *batchL2DataBuilder {
+	block := state.L2BlockRaw{
+		DeltaTimestamp:  deltaTimestamp,
+		IndexL1InfoTree: indexL1InfoTree,
+		Transactions:    make([]state.L2TxRaw, 0, len(txs)),
+	}
+	for _, tx := range txs {
+		block.Transactions = append(block.Transactions, state.L2TxRaw{Tx: *tx, EfficiencyPercentage: state.MaxEffectivePercentage})
+	}
+	b.batch.Blocks = append(b.batch.Blocks, block)
+	return b
+}
+
+// Build encodes the batch
+func (b *batchL2DataBuilder) Build(t *testing.T) []byte {
+	batchL2Data, err := state.EncodeBatchV2(&b.batch)
+	require.NoError(t, err)
+	return batchL2Data
+}
+
+// testTxs returns two transfers of 0.1 ETH, already signed, with nonces 2 and 3
+func testTxs() []*ethTypes.Transaction {
+	to := common.HexToAddress("0x4d5cf5032b2a844602278b01199ed191a86c93ff")
+	// V = chainID * 2 + 35 for the recovery id 0 (EIP-155)
+	v := big.NewInt(testChainID*2 + 35)
+	return []*ethTypes.Transaction{
+		ethTypes.NewTx(&ethTypes.LegacyTx{
+			Nonce:    2,
+			GasPrice: big.NewInt(1000000000),
+			Gas:      100000,
+			To:       &to,
+			Value:    big.NewInt(100000000000000000),
+			V:        v,
+			R:        common.HexToHash("0xbff0e780ba7db409339fd3f71969fa2cbf1b8535f6c725a1499d3318d3ef9c2b").Big(),
+			S:        common.HexToHash("0x6340ddfab84add2c188f9efddb99771db1fe621c981846394ea4f035c85bcdd5").Big(),
+		}),
+		ethTypes.NewTx(&ethTypes.LegacyTx{
+			Nonce:    3,
+			GasPrice: big.NewInt(1000000000),
+			Gas:      100000,
+			To:       &to,
+			Value:    big.NewInt(100000000000000000),
+			V:        v,
+			R:        common.HexToHash("0x5b346aa02230b22e62f73608de9ff39a162a6c24be9822209c770e3685b92d07").Big(),
+			S:        common.HexToHash("0x56d5316ef954eefc58b068231ccea001fb7ac763ebe03afd009ad71cab36861e").Big(),
+		}),
+	}
+}
+
+func TestBatchL2DataBuilderMatchesEncodedBatchL2Data(t *testing.T) {
+	const (
+		// changeL2Block + deltaTimeStamp + indexL1InfoTree
+		codedL2BlockHeader = "0b73e6af6f00000000"
+		// 2 x [ tx coded in RLP + r,s,v,efficiencyPercentage]
+		codedRLP2Txs1 = "ee02843b9aca00830186a0944d5cf5032b2a844602278b01199ed191a86c93ff88016345785d8a0000808203e88080bff0e780ba7db409339fd3f71969fa2cbf1b8535f6c725a1499d3318d3ef9c2b6340ddfab84add2c188f9efddb99771db1fe621c981846394ea4f035c85bcdd51bffee03843b9aca00830186a0944d5cf5032b2a844602278b01199ed191a86c93ff88016345785d8a0000808203e880805b346aa02230b22e62f73608de9ff39a162a6c24be9822209c770e3685b92d0756d5316ef954eefc58b068231ccea001fb7ac763ebe03afd009ad71cab36861e1bff"
+	)
+	expected, err := hex.DecodeString(codedL2BlockHeader + codedRLP2Txs1 + codedL2BlockHeader + codedRLP2Txs1)
+	require.NoError(t, err)
+
+	batchL2Data := newBatchL2DataBuilder().
+		AddL2Block(testDeltaTimestamp, 0, testTxs()...).
+		AddL2Block(testDeltaTimestamp, 0, testTxs()...).
+		Build(t)
+	require.Equal(t, expected, batchL2Data)
+}
+
 func TestIncrementalProcessUpdateBatchL2DataOnCache(t *testing.T) {
 	// Arrange
 	stateMock := mock_l2_sync_etrog.NewStateInterface(t)
@@ -34,8 +113,13 @@ func TestIncrementalProcessUpdateBatchL2DataOnCache(t *testing.T) {
 	}
 	ctx := context.Background()
 
-	stateBatchL2Data, _ := hex.DecodeString(codedL2BlockHeader + codedRLP2Txs1)
-	trustedBatchL2Data, _ := hex.DecodeString(codedL2BlockHeader + codedRLP2Txs1 + codedL2BlockHeader + codedRLP2Txs1)
+	stateBatchL2Data := newBatchL2DataBuilder().
+		AddL2Block(testDeltaTimestamp, 0, testTxs()...).
+		Build(t)
+	trustedBatchL2Data := newBatchL2DataBuilder().
+		AddL2Block(testDeltaTimestamp, 0, testTxs()...).
+		AddL2Block(testDeltaTimestamp, 0, testTxs()...).
+		Build(t)
 	expectedStateRoot := common.HexToHash("0x723e5c4c7ee7890e1e66c2e391d553ee792d2204ecb4fe921830f12f8dcd1a92")
 	//deltaBatchL2Data := []byte{4}
 	batchNumber := uint64(123)
@@ -72,3 +156,117 @@ func TestIncrementalProcessUpdateBatchL2DataOnCache(t *testing.T) {
 	require.Equal(t, trustedBatchL2Data, res.UpdateBatch.BatchL2Data)
 	require.Equal(t, false, res.ClearCache)
 }
+
+func TestIncrementalProcessSendsOnlyDeltaBatchL2DataToExecutor(t *testing.T) {
+	// Arrange
+	stateMock := mock_l2_sync_etrog.NewStateInterface(t)
+	syncMock := mock_syncinterfaces.NewSynchronizerFlushIDManager(t)
+
+	sut := SyncTrustedBatchExecutorForEtrog{
+		state: stateMock,
+		sync:  syncMock,
+	}
+	ctx := context.Background()
+
+	stateBatchL2Data := newBatchL2DataBuilder().
+		AddL2Block(testDeltaTimestamp, 0, testTxs()...).
+		Build(t)
+	trustedBatchL2Data := newBatchL2DataBuilder().
+		AddL2Block(testDeltaTimestamp, 0, testTxs()...).
+		AddL2Block(testDeltaTimestamp, 0, testTxs()...).
+		Build(t)
+	// The delta is the second L2 block, with the deltaTimestamp of the previous blocks added to the first block
+	// of the delta so the timestamp is absolute
+	expectedDeltaBatchL2Data := newBatchL2DataBuilder().
+		AddL2Block(2*testDeltaTimestamp, 0, testTxs()...).
+		Build(t)
+	expectedStateRoot := common.HexToHash("0x723e5c4c7ee7890e1e66c2e391d553ee792d2204ecb4fe921830f12f8dcd1a92")
+	batchNumber := uint64(123)
+	data := l2_shared.ProcessData{
+		BatchNumber:  batchNumber,
+		OldStateRoot: common.Hash{},
+		TrustedBatch: &types.Batch{
+			Number:      123,
+			BatchL2Data: trustedBatchL2Data,
+			StateRoot:   expectedStateRoot,
+		},
+		StateBatch: &state.Batch{
+			BatchNumber: batchNumber,
+			BatchL2Data: stateBatchL2Data,
+		},
+	}
+
+	stateMock.EXPECT().UpdateWIPBatch(ctx, mock.Anything, mock.Anything).Return(nil).Once()
+	stateMock.EXPECT().GetL1InfoTreeDataFromBatchL2Data(ctx, []byte(expectedDeltaBatchL2Data), mock.Anything).Return(map[uint32]state.L1DataV2{}, expectedStateRoot, nil).Once()
+	stateMock.EXPECT().GetForkIDByBatchNumber(batchNumber).Return(uint64(7)).Once()
+
+	processBatchResp := &state.ProcessBatchResponse{
+		NewStateRoot: expectedStateRoot,
+	}
+	var processRequest state.ProcessRequest
+	stateMock.EXPECT().ProcessBatchV2(ctx, mock.Anything, true).Run(func(ctx context.Context, request state.ProcessRequest, updateMerkleTree bool) {
+		processRequest = request
+	}).Return(processBatchResp, nil).Once()
+
+	syncMock.EXPECT().PendingFlushID(mock.Anything, mock.Anything).Once()
+	syncMock.EXPECT().CheckFlushID(mock.Anything).Return(nil).Maybe()
+	// Act
+	res, err := sut.IncrementalProcess(ctx, &data, nil)
+	// Assert
+	require.NoError(t, err)
+	require.Equal(t, expectedDeltaBatchL2Data, processRequest.Transactions)
+	require.NotEqual(t, trustedBatchL2Data, processRequest.Transactions)
+	require.Equal(t, trustedBatchL2Data, res.UpdateBatch.BatchL2Data)
+}
+
+func TestIncrementalProcessReturnsWhenContextIsCancelled(t *testing.T) {
+	// Arrange
+	stateMock := mock_l2_sync_etrog.NewStateInterface(t)
+	syncMock := mock_syncinterfaces.NewSynchronizerFlushIDManager(t)
+
+	sut := SyncTrustedBatchExecutorForEtrog{
+		state: stateMock,
+		sync:  syncMock,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	time.AfterFunc(10*time.Millisecond, cancel)
+
+	stateBatchL2Data := newBatchL2DataBuilder().
+		AddL2Block(testDeltaTimestamp, 0, testTxs()...).
+		Build(t)
+	trustedBatchL2Data := newBatchL2DataBuilder().
+		AddL2Block(testDeltaTimestamp, 0, testTxs()...).
+		AddL2Block(testDeltaTimestamp, 0, testTxs()...).
+		Build(t)
+	expectedStateRoot := common.HexToHash("0x723e5c4c7ee7890e1e66c2e391d553ee792d2204ecb4fe921830f12f8dcd1a92")
+	batchNumber := uint64(123)
+	data := l2_shared.ProcessData{
+		BatchNumber:  batchNumber,
+		OldStateRoot: common.Hash{},
+		TrustedBatch: &types.Batch{
+			Number:      123,
+			BatchL2Data: trustedBatchL2Data,
+			StateRoot:   expectedStateRoot,
+		},
+		StateBatch: &state.Batch{
+			BatchNumber: batchNumber,
+			BatchL2Data: stateBatchL2Data,
+		},
+	}
+
+	stateMock.EXPECT().GetL1InfoTreeDataFromBatchL2Data(ctx, mock.Anything, mock.Anything).Return(map[uint32]state.L1DataV2{}, expectedStateRoot, nil).Once()
+	stateMock.EXPECT().GetForkIDByBatchNumber(batchNumber).Return(uint64(7)).Once()
+
+	// The executor doesn't answer until the test finishes
+	executorDone := make(chan struct{})
+	defer close(executorDone)
+	stateMock.EXPECT().ProcessBatchV2(ctx, mock.Anything, true).Run(func(ctx context.Context, request state.ProcessRequest, updateMerkleTree bool) {
+		<-executorDone
+	}).Return(&state.ProcessBatchResponse{NewStateRoot: expectedStateRoot}, nil).Once()
+	// Act
+	res, err := sut.IncrementalProcess(ctx, &data, nil)
+	// Assert
+	require.ErrorIs(t, err, context.Canceled)
+	require.Nil(t, res)
+}
@@ -37,6 +37,18 @@ const (
 
 	// ProcessTrustedBatchTimeName is the name of the label to process trusted batch.
 	ProcessTrustedBatchTimeName = Prefix + "process_trusted_batch_time"
+
+	// TrustedBatchesProcessedName is the name of the metric that counts the trusted batches processed by mode.
+	TrustedBatchesProcessedName = Prefix + "trusted_batches_processed"
+
+	// TrustedBatchesProcessedLabelName is the name of the label for the mode used to process the trusted batches.
+	TrustedBatchesProcessedLabelName = "mode"
+
+	// TrustedBatchesProcessErrorsName is the name of the metric that counts the errors processing trusted batches.
+	TrustedBatchesProcessErrorsName = Prefix + "trusted_batches_process_errors"
+
+	// CacheResetTotalName is the name of the metric that counts the resets of the in-memory trusted state cache.
+	CacheResetTotalName = Prefix + "cache_reset_total"
 )
 
 // Register the metrics for the synchronizer package.
@@ -27,6 +27,7 @@ import (
 	"github.com/0xPolygonHermez/zkevm-node/synchronizer/common/syncinterfaces"
 	"github.com/0xPolygonHermez/zkevm-node/synchronizer/l1_parallel_sync"
 	"github.com/0xPolygonHermez/zkevm-node/synchronizer/l1event_orders"
+	"github.com/0xPolygonHermez/zkevm-node/synchronizer/l2_sync/l2_shared"
 	"github.com/0xPolygonHermez/zkevm-node/synchronizer/l2_sync/l2_sync_etrog"
 	"github.com/0xPolygonHermez/zkevm-node/synchronizer/metrics"
 	"github.com/ethereum/go-ethereum/common"
@@ -44,6 +45,7 @@ const (
 type Synchronizer interface {
 	Sync() error
 	Stop()
+	BatchesBehind(ctx context.Context) (uint64, error)
 }
 
 // TrustedState is the struct that contains the last trusted state root and the last trusted batches
@@ -65,6 +67,7 @@ type ClientSynchronizer struct {
 	ethTxManager             ethTxManager
 	zkEVMClient              zkEVMClientInterface
 	eventLog                 syncinterfaces.EventLogInterface
+	eventBus                 syncCommon.EventBus
 	ctx                      context.Context
 	cancelCtx                context.CancelFunc
 	genesis                  state.Genesis
@@ -108,6 +111,7 @@ func NewSynchronizer(
 		ethTxManager:            ethTxManager,
 		zkEVMClient:             zkEVMClient,
 		eventLog:                eventLog,
+		eventBus:                syncCommon.NewSyncEventBus(),
 		genesis:                 genesis,
 		cfg:                     cfg,
 		proverID:                "",
@@ -116,7 +120,12 @@ func NewSynchronizer(
 		l1EventProcessors:       nil,
 	}
 	//res.syncTrustedStateExecutor = l2_sync_incaberry.NewSyncTrustedStateExecutor(res.zkEVMClient, res.state, res)
-	res.syncTrustedStateExecutor = l2_sync_etrog.NewSyncTrustedBatchExecutorForEtrog(res.zkEVMClient, res.state, res.state, res, syncCommon.DefaultTimeProvider{})
+	res.syncTrustedStateExecutor = l2_sync_etrog.NewSyncTrustedBatchExecutorForEtrog(res.zkEVMClient, res.state, res.state, res, syncCommon.DefaultTimeProvider{},
+		l2_shared.ProcessorTrustedBatchSyncConfig{
+			MaxIncrementalRetries: cfg.MaxIncrementalRetries,
+			MaxProcessingTime:     cfg.MaxTrustedBatchProcessingTime.Duration,
+			MaxFlushIDRetries:     cfg.MaxFlushIDRetries,
+		}, res.eventBus)
 	res.l1EventProcessors = defaultsL1EventProcessors(res)
 	switch cfg.L1SynchronizationMode {
 	case ParallelMode:
@@ -181,6 +190,40 @@ func (s *ClientSynchronizer) IsTrustedSequencer() bool {
 	return s.isTrustedSequencer
 }
 
+// BatchesBehind returns the number of batches the node is behind. A trusted sequencer only syncs
+// L1, so it's compared with the last batch sequenced on L1, otherwise it's compared with the last
+// batch of the trusted sequencer
+func (s *ClientSynchronizer) BatchesBehind(ctx context.Context) (uint64, error) {
+	var (
+		lastBatchNumber   uint64
+		latestBatchNumber uint64
+		err               error
+	)
+	if s.isTrustedSequencer {
+		lastBatchNumber, err = s.state.GetLastVirtualBatchNum(ctx, nil)
+		if err != nil {
+			return 0, fmt.Errorf("error getting last virtual batch number. Error: %w", err)
+		}
+		latestBatchNumber, err = s.etherMan.GetLatestBatchNumber()
+		if err != nil {
+			return 0, fmt.Errorf("error getting latest batch number from L1. Error: %w", err)
+		}
+	} else {
+		lastBatchNumber, err = s.state.GetLastBatchNumber(ctx, nil)
+		if err != nil {
+			return 0, fmt.Errorf("error getting last batch number. Error: %w", err)
+		}
+		latestBatchNumber, err = s.zkEVMClient.BatchNumber(ctx)
+		if err != nil {
+			return 0, fmt.Errorf("error getting latest batch number from trusted sequencer. Error: %w", err)
+		}
+	}
+	if latestBatchNumber <= lastBatchNumber {
+		return 0, nil
+	}
+	return latestBatchNumber - lastBatchNumber, nil
+}
+
 // Sync function will read the last state synced and will continue from that point.
 // Sync() will read blockchain events to detect rollup updates
 func (s *ClientSynchronizer) Sync() error {
@@ -40,6 +40,8 @@ var (
 		MaxBinaries:          473170,
 		MaxSteps:             7570538,
 		MaxSHA256Hashes:      1596,
+
+		MaxForcedBatchesPerIteration: 10,
 	}
 )
 